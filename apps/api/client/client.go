@@ -0,0 +1,194 @@
+// Package client is the Go client for the Terraforge API.
+//
+//	c := client.New("https://api.terraforge.gg", client.WithToken(os.Getenv("TERRAFORGE_TOKEN")))
+//	project, err := c.GetProject(ctx, "example-mod")
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultMaxRetries = 3
+	// Upper bound on a single Retry-After wait, so a misbehaving server cannot stall callers.
+	maxRetryWait = time.Minute
+)
+
+type Client struct {
+	baseUrl    string
+	token      string
+	httpClient *http.Client
+	maxRetries int
+	userAgent  string
+}
+
+type Option func(*Client)
+
+// Authenticates every request with a session JWT or a personal access token.
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// Number of times a rate limited request is retried before the 429 is returned. 0 disables retries.
+func WithMaxRetries(maxRetries int) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+	}
+}
+
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// Creates a client for the API at baseUrl, e.g. https://api.terraforge.gg. The /v1 prefix is added by the client.
+func New(baseUrl string, opts ...Option) *Client {
+	c := &Client{
+		baseUrl:    strings.TrimRight(baseUrl, "/") + "/v1",
+		httpClient: http.DefaultClient,
+		maxRetries: defaultMaxRetries,
+		userAgent:  "terraforge-go",
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Sends a request with a JSON body (when body is non nil) and decodes a JSON response into out (when out is non nil).
+func (c *Client) doJSON(ctx context.Context, method string, path string, query url.Values, body any, out any) error {
+	var payload []byte
+
+	if body != nil {
+		b, err := json.Marshal(body)
+
+		if err != nil {
+			return fmt.Errorf("encode request: %w", err)
+		}
+
+		payload = b
+	}
+
+	res, err := c.do(ctx, method, path, query, func() (io.Reader, string) {
+		if payload == nil {
+			return nil, ""
+		}
+		return bytes.NewReader(payload), "application/json"
+	})
+
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+
+	return decodeResponse(res, out)
+}
+
+// Sends a request, retrying on 429 Too Many Requests. newBody is called once per attempt so
+// the body can be replayed; a nil reader sends no body.
+func (c *Client) do(ctx context.Context, method string, path string, query url.Values, newBody func() (io.Reader, string)) (*http.Response, error) {
+	u := c.baseUrl + path
+
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	for attempt := 0; ; attempt++ {
+		body, contentType := newBody()
+
+		req, err := http.NewRequestWithContext(ctx, method, u, body)
+
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set("Accept", "application/json")
+		req.Header.Set("User-Agent", c.userAgent)
+
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+
+		if c.token != "" {
+			req.Header.Set("Authorization", "Bearer "+c.token)
+		}
+
+		res, err := c.httpClient.Do(req)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if res.StatusCode != http.StatusTooManyRequests || attempt >= c.maxRetries {
+			return res, nil
+		}
+
+		wait := retryAfter(res.Header.Get("Retry-After"), time.Now())
+		io.Copy(io.Discard, res.Body)
+		res.Body.Close()
+
+		timer := time.NewTimer(wait)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// Parses a Retry-After header given either in seconds or as an HTTP date.
+// Falls back to one second when the header is missing or invalid.
+func retryAfter(header string, now time.Time) time.Duration {
+	wait := time.Second
+
+	if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+		wait = time.Duration(seconds) * time.Second
+	} else if date, err := http.ParseTime(header); err == nil {
+		wait = date.Sub(now)
+	}
+
+	return min(max(wait, 0), maxRetryWait)
+}
+
+func decodeResponse(res *http.Response, out any) error {
+	if res.StatusCode >= 400 {
+		return decodeError(res)
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	if err := json.NewDecoder(res.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+
+	return nil
+}
+
+func pathEscape(segment string) string {
+	return url.PathEscape(segment)
+}
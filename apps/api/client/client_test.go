@@ -0,0 +1,300 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc, opts ...Option) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return New(server.URL, opts...)
+}
+
+func TestClient_SendsToken(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer tf_pat_test", r.Header.Get("Authorization"))
+		assert.Equal(t, "/v1/projects/example-mod", r.URL.Path)
+		json.NewEncoder(w).Encode(Project{Id: "1", Slug: "example-mod"})
+	}, WithToken("tf_pat_test"))
+
+	project, err := c.GetProject(context.Background(), "example-mod")
+
+	require.NoError(t, err)
+	assert.Equal(t, "example-mod", project.Slug)
+}
+
+func TestClient_DecodesProblemDetails(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(ProblemDetails{
+			Title:  "Bad Request",
+			Status: http.StatusBadRequest,
+			Detail: "One or more fields failed validation.",
+			Errors: map[string]string{"slug": "url_slug"},
+		})
+	})
+
+	_, err := c.CreateProject(context.Background(), CreateProjectRequest{Name: "Example Mod", Slug: "Not A Slug", Type: "mod"})
+
+	var apiErr *Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	assert.Equal(t, "url_slug", apiErr.Errors["slug"])
+	assert.True(t, IsStatus(err, http.StatusBadRequest))
+}
+
+func TestClient_DecodesProblemDetailsExtensions(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		json.NewEncoder(w).Encode(ProblemDetails{
+			Title:      "project release file too large",
+			Status:     http.StatusRequestEntityTooLarge,
+			Code:       "release-file-too-large",
+			Extensions: map[string]any{"maxFileSize": 524288000},
+		})
+	})
+
+	_, err := c.GetReleaseUploadUrl(context.Background(), "example-mod", 1<<30)
+
+	var apiErr *Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, "release-file-too-large", apiErr.Code)
+	// Standard members are not repeated as extensions
+	assert.Equal(t, map[string]any{"maxFileSize": float64(524288000)}, apiErr.Extensions)
+}
+
+func TestClient_ErrorWithoutProblemDetails(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad gateway", http.StatusBadGateway)
+	})
+
+	_, err := c.GetLoaderVersions(context.Background(), ListParams{})
+
+	var apiErr *Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadGateway, apiErr.StatusCode)
+	assert.Equal(t, "Bad Gateway", apiErr.Title)
+}
+
+func TestClient_RetriesRateLimitedRequests(t *testing.T) {
+	var calls atomic.Int32
+
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		json.NewEncoder(w).Encode(Page[LoaderVersion]{Data: []LoaderVersion{{Id: "1"}}})
+	})
+
+	loaderVersions, err := c.GetLoaderVersions(context.Background(), ListParams{})
+
+	require.NoError(t, err)
+	assert.Len(t, loaderVersions.Data, 1)
+	assert.EqualValues(t, 2, calls.Load())
+}
+
+func TestClient_GivesUpAfterMaxRetries(t *testing.T) {
+	var calls atomic.Int32
+
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}, WithMaxRetries(2))
+
+	_, err := c.GetLoaderVersions(context.Background(), ListParams{})
+
+	assert.True(t, IsRateLimited(err))
+	assert.EqualValues(t, 3, calls.Load())
+}
+
+func TestClient_RetryReplaysBody(t *testing.T) {
+	var calls atomic.Int32
+
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var req CreateProjectRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "example-mod", req.Slug)
+
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(Project{Slug: req.Slug})
+	})
+
+	project, err := c.CreateProject(context.Background(), CreateProjectRequest{Name: "Example Mod", Slug: "example-mod", Type: "mod"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "example-mod", project.Slug)
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, 5*time.Second, retryAfter("5", now))
+	assert.Equal(t, 10*time.Second, retryAfter(now.Add(10*time.Second).Format(http.TimeFormat), now))
+	assert.Equal(t, time.Second, retryAfter("", now))
+	assert.Equal(t, maxRetryWait, retryAfter("3600", now))
+	assert.Equal(t, time.Duration(0), retryAfter(now.Add(-time.Minute).Format(http.TimeFormat), now))
+}
+
+func TestClient_AllProjectsPaginates(t *testing.T) {
+	const total = 5
+
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		limit, _ := strconv.ParseInt(r.URL.Query().Get("limit"), 10, 64)
+		// Cursors are opaque to the client, this server uses the offset
+		offset, _ := strconv.ParseInt(r.URL.Query().Get("cursor"), 10, 64)
+		assert.Equal(t, "boss", r.URL.Query().Get("query"))
+
+		result := ProjectSearchResult{TotalHits: total, Offset: offset}
+		for i := offset; i < min(offset+limit, total); i++ {
+			result.Data = append(result.Data, Project{Id: strconv.FormatInt(i, 10)})
+		}
+		if next := offset + limit; next < total {
+			result.NextCursor = strconv.FormatInt(next, 10)
+		}
+		json.NewEncoder(w).Encode(result)
+	})
+
+	var ids []string
+	for project, err := range c.AllProjects(context.Background(), SearchParams{Query: "boss", Limit: 2}) {
+		require.NoError(t, err)
+		ids = append(ids, project.Id)
+	}
+
+	assert.Equal(t, []string{"0", "1", "2", "3", "4"}, ids)
+}
+
+func TestClient_AllReleasesFollowsCursors(t *testing.T) {
+	pages := map[string]Page[Release]{
+		"":       {Data: []Release{{VersionNumber: "1.1.0"}, {VersionNumber: "1.0.1"}}, NextCursor: "page-2"},
+		"page-2": {Data: []Release{{VersionNumber: "1.0.0"}}},
+	}
+
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/projects/example-mod/releases", r.URL.Path)
+		json.NewEncoder(w).Encode(pages[r.URL.Query().Get("cursor")])
+	})
+
+	var versions []string
+	for release, err := range c.AllReleases(context.Background(), "example-mod", ListParams{}) {
+		require.NoError(t, err)
+		versions = append(versions, release.VersionNumber)
+	}
+
+	assert.Equal(t, []string{"1.1.0", "1.0.1", "1.0.0"}, versions)
+}
+
+func TestClient_AllProjectsStopsOnError(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	var errs []error
+	for _, err := range c.AllProjects(context.Background(), SearchParams{}) {
+		errs = append(errs, err)
+	}
+
+	require.Len(t, errs, 1)
+	assert.True(t, IsStatus(errs[0], http.StatusInternalServerError))
+}
+
+func TestClient_PublishRelease(t *testing.T) {
+	fileContent := []byte("TMOD fake mod file")
+
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/projects/example-mod/releases/publish", r.URL.Path)
+
+		reader, err := r.MultipartReader()
+		require.NoError(t, err)
+
+		metadataPart, err := reader.NextPart()
+		require.NoError(t, err)
+		assert.Equal(t, "metadata", metadataPart.FormName())
+
+		var req PublishReleaseRequest
+		require.NoError(t, json.NewDecoder(metadataPart).Decode(&req))
+
+		filePart, err := reader.NextPart()
+		require.NoError(t, err)
+		assert.Equal(t, "example-mod.tmod", filePart.FileName())
+
+		b, err := io.ReadAll(filePart)
+		require.NoError(t, err)
+		assert.Equal(t, fileContent, b)
+
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(Release{VersionNumber: req.VersionNumber, FileSize: int64(len(b))})
+	})
+
+	release, err := c.PublishRelease(context.Background(), "example-mod", PublishReleaseRequest{
+		Name:            "Example Release",
+		VersionNumber:   "1.0.0",
+		LoaderVersionId: "1",
+	}, "example-mod.tmod", bytes.NewReader(fileContent))
+
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", release.VersionNumber)
+	assert.EqualValues(t, len(fileContent), release.FileSize)
+}
+
+func TestClient_PublishReleaseRetriesSeekableFile(t *testing.T) {
+	// Larger than the pipe and socket buffers, so the first attempt's writer is still copying when it is rejected
+	fileContent := bytes.Repeat([]byte("TMOD"), 1<<20)
+	var calls atomic.Int32
+
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+
+		reader, err := r.MultipartReader()
+		require.NoError(t, err)
+
+		_, err = reader.NextPart()
+		require.NoError(t, err)
+
+		filePart, err := reader.NextPart()
+		require.NoError(t, err)
+
+		b, err := io.ReadAll(filePart)
+		require.NoError(t, err)
+		assert.True(t, bytes.Equal(fileContent, b))
+
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(Release{VersionNumber: "1.0.0", FileSize: int64(len(b))})
+	})
+
+	release, err := c.PublishRelease(context.Background(), "example-mod", PublishReleaseRequest{
+		Name:            "Example Release",
+		VersionNumber:   "1.0.0",
+		LoaderVersionId: "1",
+	}, "example-mod.tmod", bytes.NewReader(fileContent))
+
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, calls.Load())
+	assert.EqualValues(t, len(fileContent), release.FileSize)
+}
@@ -0,0 +1,71 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/terraforge-gg/terraforge/internal/dto"
+)
+
+// Error is returned for any non 2xx response. The problem details body is decoded when
+// the API sent one, otherwise only StatusCode is set.
+type Error struct {
+	ProblemDetails
+	StatusCode int
+}
+
+func (e *Error) Error() string {
+	if e.Detail != "" {
+		return fmt.Sprintf("terraforge: %d %s: %s", e.StatusCode, e.Title, e.Detail)
+	}
+
+	return fmt.Sprintf("terraforge: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// Reports whether err is an API error with the given status code.
+func IsStatus(err error, status int) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.StatusCode == status
+}
+
+func IsNotFound(err error) bool {
+	return IsStatus(err, http.StatusNotFound)
+}
+
+func IsUnauthorized(err error) bool {
+	return IsStatus(err, http.StatusUnauthorized)
+}
+
+func IsRateLimited(err error) bool {
+	return IsStatus(err, http.StatusTooManyRequests)
+}
+
+// Reports whether the request was refused because the user has not verified their email.
+func IsEmailNotVerified(err error) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.Type == dto.ProblemTypeEmailNotVerified
+}
+
+// Largest error body read, problem details are small.
+const maxErrorBodySize = 64 << 10
+
+func decodeError(res *http.Response) error {
+	apiErr := &Error{StatusCode: res.StatusCode}
+
+	b, err := io.ReadAll(io.LimitReader(res.Body, maxErrorBodySize))
+
+	if err == nil && len(b) > 0 {
+		// Not every error comes from a handler (e.g. proxies), so a body that is not
+		// problem details is ignored rather than reported as a decode failure
+		_ = json.Unmarshal(b, &apiErr.ProblemDetails)
+	}
+
+	if apiErr.Title == "" {
+		apiErr.Title = http.StatusText(res.StatusCode)
+	}
+
+	return apiErr
+}
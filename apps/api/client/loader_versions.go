@@ -0,0 +1,41 @@
+package client
+
+import (
+	"context"
+	"iter"
+	"net/http"
+)
+
+// Returns a single page of loader versions, newest first.
+func (c *Client) GetLoaderVersions(ctx context.Context, params ListParams) (*Page[LoaderVersion], error) {
+	var page Page[LoaderVersion]
+
+	if err := c.doJSON(ctx, http.MethodGet, "/loader-versions", params.values(), nil, &page); err != nil {
+		return nil, err
+	}
+
+	return &page, nil
+}
+
+// Iterates over every loader version, see AllProjects.
+func (c *Client) AllLoaderVersions(ctx context.Context, params ListParams) iter.Seq2[LoaderVersion, error] {
+	return iterate(params.Cursor, func(cursor string) ([]LoaderVersion, string, error) {
+		page, err := c.GetLoaderVersions(ctx, ListParams{Limit: params.Limit, Cursor: cursor})
+
+		if err != nil {
+			return nil, "", err
+		}
+
+		return page.Data, page.NextCursor, nil
+	})
+}
+
+func (c *Client) GetLoaderVersion(ctx context.Context, id string) (*LoaderVersion, error) {
+	var loaderVersion LoaderVersion
+
+	if err := c.doJSON(ctx, http.MethodGet, "/loader-versions/"+pathEscape(id), nil, nil, &loaderVersion); err != nil {
+		return nil, err
+	}
+
+	return &loaderVersion, nil
+}
@@ -0,0 +1,62 @@
+package client
+
+import (
+	"iter"
+	"net/url"
+	"strconv"
+
+	"github.com/terraforge-gg/terraforge/internal/dto"
+)
+
+// Page is one page of a list endpoint. Pass its NextCursor in ListParams to get the next page,
+// it is empty on the last one.
+type Page[T any] = dto.ListResponse[T]
+
+// ListParams selects a page of a list endpoint. A zero Limit uses the API's default and an
+// empty Cursor gets the first page.
+type ListParams struct {
+	Limit  int64
+	Cursor string
+}
+
+func (p ListParams) values() url.Values {
+	values := url.Values{}
+
+	if p.Limit > 0 {
+		values.Set("limit", strconv.FormatInt(p.Limit, 10))
+	}
+
+	if p.Cursor != "" {
+		values.Set("cursor", p.Cursor)
+	}
+
+	return values
+}
+
+// Iterates over every item of a list, fetching a page at a time by following next cursors.
+// Iteration stops after the first error, which is yielded with a zero item.
+func iterate[T any](cursor string, fetch func(cursor string) ([]T, string, error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for {
+			items, next, err := fetch(cursor)
+
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+
+			if next == "" || len(items) == 0 {
+				return
+			}
+
+			cursor = next
+		}
+	}
+}
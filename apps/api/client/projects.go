@@ -0,0 +1,63 @@
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+func (c *Client) CreateProject(ctx context.Context, req CreateProjectRequest) (*Project, error) {
+	var project Project
+
+	if err := c.doJSON(ctx, http.MethodPost, "/projects", nil, req, &project); err != nil {
+		return nil, err
+	}
+
+	return &project, nil
+}
+
+// Gets a project by id or slug.
+func (c *Client) GetProject(ctx context.Context, identifier string) (*Project, error) {
+	var project Project
+
+	if err := c.doJSON(ctx, http.MethodGet, "/projects/"+pathEscape(identifier), nil, nil, &project); err != nil {
+		return nil, err
+	}
+
+	return &project, nil
+}
+
+func (c *Client) UpdateProject(ctx context.Context, identifier string, req UpdateProjectRequest) (*Project, error) {
+	var project Project
+
+	if err := c.doJSON(ctx, http.MethodPatch, "/projects/"+pathEscape(identifier), nil, req, &project); err != nil {
+		return nil, err
+	}
+
+	return &project, nil
+}
+
+func (c *Client) DeleteProject(ctx context.Context, identifier string) error {
+	return c.doJSON(ctx, http.MethodDelete, "/projects/"+pathEscape(identifier), nil, nil, nil)
+}
+
+// Returns a single page of a project's members, in the order they joined.
+func (c *Client) GetProjectMembers(ctx context.Context, identifier string, params ListParams) (*Page[ProjectMember], error) {
+	var page Page[ProjectMember]
+
+	if err := c.doJSON(ctx, http.MethodGet, "/projects/"+pathEscape(identifier)+"/members", params.values(), nil, &page); err != nil {
+		return nil, err
+	}
+
+	return &page, nil
+}
+
+// Adds a user to a project, which only the project owner can do.
+func (c *Client) AddProjectMember(ctx context.Context, identifier string, req AddProjectMemberRequest) (*ProjectMember, error) {
+	var member ProjectMember
+
+	if err := c.doJSON(ctx, http.MethodPost, "/projects/"+pathEscape(identifier)+"/members", nil, req, &member); err != nil {
+		return nil, err
+	}
+
+	return &member, nil
+}
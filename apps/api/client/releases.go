@@ -0,0 +1,167 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// Returns a single page of a project's releases, newest first.
+func (c *Client) GetReleases(ctx context.Context, projectIdentifier string, params ListParams) (*Page[Release], error) {
+	var page Page[Release]
+
+	if err := c.doJSON(ctx, http.MethodGet, "/projects/"+pathEscape(projectIdentifier)+"/releases", params.values(), nil, &page); err != nil {
+		return nil, err
+	}
+
+	return &page, nil
+}
+
+// Iterates over every release of a project, see AllProjects.
+func (c *Client) AllReleases(ctx context.Context, projectIdentifier string, params ListParams) iter.Seq2[Release, error] {
+	return iterate(params.Cursor, func(cursor string) ([]Release, string, error) {
+		page, err := c.GetReleases(ctx, projectIdentifier, ListParams{Limit: params.Limit, Cursor: cursor})
+
+		if err != nil {
+			return nil, "", err
+		}
+
+		return page.Data, page.NextCursor, nil
+	})
+}
+
+func (c *Client) GetRelease(ctx context.Context, projectIdentifier string, releaseId string) (*Release, error) {
+	var release Release
+
+	if err := c.doJSON(ctx, http.MethodGet, "/projects/"+pathEscape(projectIdentifier)+"/releases/"+pathEscape(releaseId), nil, nil, &release); err != nil {
+		return nil, err
+	}
+
+	return &release, nil
+}
+
+// Returns a presigned url the release file can be PUT to before calling CreateRelease with it.
+func (c *Client) GetReleaseUploadUrl(ctx context.Context, projectIdentifier string, fileSize int64) (string, error) {
+	var uploadUrl string
+
+	query := url.Values{"fileSize": {strconv.FormatInt(fileSize, 10)}}
+
+	if err := c.doJSON(ctx, http.MethodGet, "/projects/"+pathEscape(projectIdentifier)+"/releases/upload-url", query, nil, &uploadUrl); err != nil {
+		return "", err
+	}
+
+	return uploadUrl, nil
+}
+
+// Creates a release from a file already uploaded through GetReleaseUploadUrl.
+func (c *Client) CreateRelease(ctx context.Context, projectIdentifier string, req CreateReleaseRequest) (*Release, error) {
+	var release Release
+
+	if err := c.doJSON(ctx, http.MethodPost, "/projects/"+pathEscape(projectIdentifier)+"/releases", nil, req, &release); err != nil {
+		return nil, err
+	}
+
+	return &release, nil
+}
+
+// Creates a release and uploads its .tmod file in one request. The file is streamed, so
+// a rate limited publish is only retried when file is also an io.Seeker.
+func (c *Client) PublishRelease(ctx context.Context, projectIdentifier string, req PublishReleaseRequest, fileName string, file io.Reader) (*Release, error) {
+	metadata, err := json.Marshal(req)
+
+	if err != nil {
+		return nil, fmt.Errorf("encode request: %w", err)
+	}
+
+	seeker, replayable := file.(io.Seeker)
+
+	// The previous attempt's pipe and a channel closed once its writer stopped reading file
+	var prev *io.PipeReader
+	var prevDone chan struct{}
+
+	res, err := c.do(ctx, http.MethodPost, "/projects/"+pathEscape(projectIdentifier)+"/releases/publish", nil, func() (io.Reader, string) {
+		if prev != nil {
+			if !replayable {
+				return failingReader{fmt.Errorf("publish release: file cannot be replayed after a rate limited attempt")}, ""
+			}
+
+			// The writer may still be copying file, it has to stop before file is rewound
+			prev.CloseWithError(errAttemptDiscarded)
+			<-prevDone
+
+			if _, err := seeker.Seek(0, io.SeekStart); err != nil {
+				return failingReader{err}, ""
+			}
+		}
+
+		pr, pw := io.Pipe()
+		writer := multipart.NewWriter(pw)
+		done := make(chan struct{})
+
+		go func() {
+			defer close(done)
+			pw.CloseWithError(writeReleaseParts(writer, metadata, fileName, file))
+		}()
+
+		prev, prevDone = pr, done
+
+		return pr, writer.FormDataContentType()
+	})
+
+	if prev != nil {
+		// Stops the writer if the request ended before the whole file was sent
+		defer func() {
+			prev.CloseWithError(errAttemptDiscarded)
+			<-prevDone
+		}()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	var release Release
+
+	if err := decodeResponse(res, &release); err != nil {
+		return nil, err
+	}
+
+	return &release, nil
+}
+
+func writeReleaseParts(writer *multipart.Writer, metadata []byte, fileName string, file io.Reader) error {
+	if err := writer.WriteField("metadata", string(metadata)); err != nil {
+		return err
+	}
+
+	part, err := writer.CreateFormFile("file", fileName)
+
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(part, file); err != nil {
+		return err
+	}
+
+	return writer.Close()
+}
+
+var errAttemptDiscarded = errors.New("publish release: attempt discarded")
+
+type failingReader struct {
+	err error
+}
+
+func (r failingReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
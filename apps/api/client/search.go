@@ -0,0 +1,112 @@
+package client
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// Largest page the search endpoints return.
+const maxSearchLimit = 100
+
+// SearchParams selects a page of search results. Cursor, the NextCursor of the previous page,
+// takes precedence over Offset, which jumps straight to a position in the results.
+type SearchParams struct {
+	Query  string
+	Limit  int64
+	Offset int64
+	Cursor string
+}
+
+func (p SearchParams) values(queryKey string) url.Values {
+	values := url.Values{}
+
+	if p.Query != "" {
+		values.Set(queryKey, p.Query)
+	}
+
+	if p.Limit > 0 {
+		values.Set("limit", strconv.FormatInt(p.Limit, 10))
+	}
+
+	if p.Cursor != "" {
+		values.Set("cursor", p.Cursor)
+	} else if p.Offset > 0 {
+		values.Set("offset", strconv.FormatInt(p.Offset, 10))
+	}
+
+	return values
+}
+
+// Returns a single page of projects matching params.
+func (c *Client) SearchProjects(ctx context.Context, params SearchParams) (*ProjectSearchResult, error) {
+	var result ProjectSearchResult
+
+	if err := c.doJSON(ctx, http.MethodGet, "/projects", params.values("query"), nil, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// Returns a single page of users matching params.
+func (c *Client) SearchUsers(ctx context.Context, params SearchParams) (*UserSearchResult, error) {
+	var result UserSearchResult
+
+	if err := c.doJSON(ctx, http.MethodGet, "/users", params.values("q"), nil, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// Iterates over every project matching params, fetching pages of params.Limit as it goes.
+// Iteration stops after the first error, which is yielded with a zero Project.
+//
+//	for project, err := range c.AllProjects(ctx, client.SearchParams{Query: "boss"}) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(project.Name)
+//	}
+func (c *Client) AllProjects(ctx context.Context, params SearchParams) iter.Seq2[Project, error] {
+	params = withSearchPageLimit(params)
+
+	return iterate(params.Cursor, func(cursor string) ([]Project, string, error) {
+		params.Cursor = cursor
+		result, err := c.SearchProjects(ctx, params)
+
+		if err != nil {
+			return nil, "", err
+		}
+
+		return result.Data, result.NextCursor, nil
+	})
+}
+
+// Iterates over every user matching params, see AllProjects.
+func (c *Client) AllUsers(ctx context.Context, params SearchParams) iter.Seq2[User, error] {
+	params = withSearchPageLimit(params)
+
+	return iterate(params.Cursor, func(cursor string) ([]User, string, error) {
+		params.Cursor = cursor
+		result, err := c.SearchUsers(ctx, params)
+
+		if err != nil {
+			return nil, "", err
+		}
+
+		return result.Data, result.NextCursor, nil
+	})
+}
+
+// Iterating fetches the largest pages allowed unless a limit was given.
+func withSearchPageLimit(params SearchParams) SearchParams {
+	if params.Limit <= 0 || params.Limit > maxSearchLimit {
+		params.Limit = maxSearchLimit
+	}
+
+	return params
+}
@@ -0,0 +1,32 @@
+package client
+
+import "github.com/terraforge-gg/terraforge/internal/dto"
+
+// Request and response types are aliases of the API's own DTOs, so the client and the server
+// cannot drift apart.
+
+type ProblemDetails = dto.ProblemDetails
+
+type (
+	Project                 = dto.ProjectResponse
+	ProjectMember           = dto.ProjectMemberResponse
+	ProjectSearchResult     = dto.ProjectSearchResponse
+	CreateProjectRequest    = dto.CreateProjectRequest
+	UpdateProjectRequest    = dto.UpdateProjectRequest
+	AddProjectMemberRequest = dto.AddProjectMemberRequest
+)
+
+type (
+	Release                  = dto.ProjectReleaseResponse
+	ReleaseDependency        = dto.ProjectReleaseDependencyResponse
+	CreateReleaseRequest     = dto.CreateProjectReleaseRequest
+	PublishReleaseRequest    = dto.PublishProjectReleaseRequest
+	ReleaseDependencyRequest = dto.CreateProjectReleaseRequestDependency
+)
+
+type LoaderVersion = dto.LoaderVersionResponse
+
+type (
+	User             = dto.UserResponse
+	UserSearchResult = dto.UserSearchResponse
+)
@@ -0,0 +1,17 @@
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+// Gets a single page of the projects of a user by id or username, newest first.
+func (c *Client) GetUserProjects(ctx context.Context, userIdentifier string, params ListParams) (*Page[Project], error) {
+	var page Page[Project]
+
+	if err := c.doJSON(ctx, http.MethodGet, "/users/"+pathEscape(userIdentifier)+"/projects", params.values(), nil, &page); err != nil {
+		return nil, err
+	}
+
+	return &page, nil
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/terraforge-gg/terraforge/internal/config"
+	"github.com/terraforge-gg/terraforge/internal/middleware"
+)
+
+// Parses the flags shared by the server and config check, returning the config file to load.
+func parseFlags(name string, args []string) string {
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+	flags.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	configFile := flags.String("config", os.Getenv("CONFIG_FILE"), "YAML config file, overridden by environment variables")
+	flags.Parse(args)
+
+	return *configFile
+}
+
+// Loads the configuration and the files it points to without starting anything, so a deploy
+// can be checked before it goes out. Returns the exit code.
+func runConfigCheck(args []string) int {
+	configFile := parseFlags("config check", args)
+
+	cfg, err := config.Load(configFile)
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	var errs []error
+
+	if policies, err := middleware.LoadRateLimitPolicies(cfg.RateLimitPoliciesFile); err != nil {
+		errs = append(errs, fmt.Errorf("RATE_LIMIT_POLICIES_FILE: %w", err))
+	} else if err := policies.CheckQuotas(middleware.RateLimitQuotas(cfg.RateLimitTiers)); err != nil {
+		errs = append(errs, fmt.Errorf("RATE_LIMIT_TIER_*: %w", err))
+	}
+
+	if _, err := middleware.LoadTrustedProxies(cfg.TrustedProxies, cfg.TrustedProxiesFile); err != nil {
+		errs = append(errs, fmt.Errorf("TRUSTED_PROXIES: %w", err))
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		fmt.Fprintf(os.Stderr, "invalid configuration:\n%v\n", err)
+		return 1
+	}
+
+	fmt.Println("Configuration is valid.")
+	return 0
+}
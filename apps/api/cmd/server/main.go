@@ -1,54 +1,157 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
+	"syscall"
+	"time"
 
+	"github.com/labstack/echo/v5"
 	"github.com/terraforge-gg/terraforge/internal/config"
 	"github.com/terraforge-gg/terraforge/internal/database"
 	"github.com/terraforge-gg/terraforge/internal/logger"
+	"github.com/terraforge-gg/terraforge/internal/metrics"
 	"github.com/terraforge-gg/terraforge/internal/server"
+	"github.com/terraforge-gg/terraforge/internal/tracing"
 )
 
+const usage = `Usage: server [-config file]
+       server config check [-config file]
+
+Settings come from the YAML file given by -config or CONFIG_FILE, overridden by environment
+variables, which are also read from a .env file.
+`
+
 func main() {
-	cfg := config.Load()
+	args := os.Args[1:]
+
+	if len(args) >= 2 && args[0] == "config" && args[1] == "check" {
+		os.Exit(runConfigCheck(args[2:]))
+	}
+
+	configFile := parseFlags("server", args)
 	logger := logger.New()
 
+	if err := run(logger, configFile); err != nil {
+		logger.Error("Server stopped with an error", "error", err)
+		os.Exit(1)
+	}
+}
+
+// Serves until a signal arrives or the server fails. Returned rather than exiting, so the
+// deferred cleanup still runs.
+func run(logger *slog.Logger, configFile string) error {
+	cfg, err := config.Load(configFile)
+
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// Containers are stopped with SIGTERM, terminals with SIGINT
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Before anything instrumented is created, so their spans go to the configured exporter
+	shutdownTracing, err := tracing.Setup(ctx, cfg)
+
+	if err != nil {
+		return fmt.Errorf("failed to set up tracing: %w", err)
+	}
+
 	db, err := database.NewPostgresConnection(cfg.DatabaseUrl)
 
 	if err != nil {
-		logger.Error("An error occurred connecting to the database", "error", err)
-		return
+		return fmt.Errorf("failed to connect to the database: %w", err)
 	}
 
 	defer db.Close()
 
-	err = database.Migrate(logger, db)
+	if err := database.Migrate(logger, db); err != nil {
+		return fmt.Errorf("failed to migrate the database: %w", err)
+	}
+
+	srv, err := server.NewServer(cfg, logger, db)
 
 	if err != nil {
-		logger.Error("An error occurred during migrate", "error", err)
-		return
+		return fmt.Errorf("failed to create server: %w", err)
 	}
 
-	e, err := server.NewServer(cfg, logger, db)
+	metricsServer := &http.Server{
+		Addr:              cfg.MetricsAddress,
+		Handler:           metrics.Handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
 
-	if err != nil {
-		logger.Error("Failed to create server", "error", err)
-		return
+	go func() {
+		if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("Metrics server failed", "error", err)
+		}
+	}()
+
+	serving := make(chan *http.Server, 1)
+	served := make(chan struct{})
+	var serveErr error
+
+	sc := echo.StartConfig{
+		Address: ":" + cfg.HostPort,
+		// Echo's own graceful shutdown would get a timeout of its own, the server is shut
+		// down below under the same deadline as everything else
+		GracefulTimeout: -1,
+		BeforeServeFunc: func(s *http.Server) error {
+			serving <- s
+			return nil
+		},
 	}
 
 	go func() {
-		err := e.Start(":" + cfg.HostPort)
+		defer close(served)
 
-		if err != nil && err != http.ErrServerClosed {
-			logger.Error("Server failed to start", "error", err)
-		}
+		serveErr = sc.Start(ctx, srv.Echo)
+
+		// A server that stopped on its own shuts the rest down too
+		stop()
 	}()
 
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, os.Interrupt)
-	<-quit
+	<-ctx.Done()
+
+	logger.Info("Shutting down")
+
+	// One deadline for the whole shutdown, starting when the signal arrives
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	// Stops accepting connections and waits for in flight requests
+	select {
+	case httpServer := <-serving:
+		if err := httpServer.Shutdown(shutdownCtx); err != nil {
+			logger.Error("In flight requests did not finish before the shutdown timeout", "error", err)
+		}
+
+		<-served
+	case <-served:
+	}
+
+	if err := metricsServer.Shutdown(shutdownCtx); err != nil {
+		logger.Error("Failed to stop metrics server", "error", err)
+	}
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		logger.Error("Server did not shut down cleanly", "error", err)
+	}
+
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		logger.Error("Failed to flush traces", "error", err)
+	}
+
+	if serveErr != nil {
+		return fmt.Errorf("server failed: %w", serveErr)
+	}
 
 	logger.Info("Server gracefully stopped")
+	return nil
 }
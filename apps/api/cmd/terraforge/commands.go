@@ -0,0 +1,407 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/terraforge-gg/terraforge/client"
+	"github.com/terraforge-gg/terraforge/internal/config"
+	"github.com/terraforge-gg/terraforge/internal/lib/tmod"
+	"github.com/terraforge-gg/terraforge/internal/service"
+	"github.com/terraforge-gg/terraforge/internal/validation"
+)
+
+func newFlagSet(name string, usage string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: terraforge %s\n\nFlags:\n", usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+func runLogin(ctx context.Context, args []string) error {
+	fs := newFlagSet("login", "login [flags]")
+	token := fs.String("token", "", "personal access token, read from stdin when omitted")
+	apiUrl := fs.String("api-url", "", "API base url (default "+defaultApiUrl+")")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	creds, err := loadCredentials()
+
+	if err != nil {
+		return err
+	}
+
+	if *apiUrl != "" {
+		creds.ApiUrl = *apiUrl
+	}
+
+	creds.Token = *token
+
+	if creds.Token == "" {
+		fmt.Fprint(os.Stderr, "Token: ")
+
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+
+		if err != nil && line == "" {
+			return fmt.Errorf("read token: %w", err)
+		}
+
+		creds.Token = strings.TrimSpace(line)
+	}
+
+	if creds.Token == "" {
+		return errors.New("no token given")
+	}
+
+	path, err := saveCredentials(creds)
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Saved token for %s to %s\n", creds.ApiUrl, path)
+	return nil
+}
+
+func runLogout() error {
+	path, err := credentialsPath()
+
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	fmt.Println("Logged out")
+	return nil
+}
+
+func runCreateProject(ctx context.Context, args []string) error {
+	fs := newFlagSet("projects create", "projects create [flags]")
+	name := fs.String("name", "", "project name (required)")
+	slug := fs.String("slug", "", "url slug (required)")
+	summary := fs.String("summary", "", "short summary")
+	projectType := fs.String("type", "mod", "project type")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	req := client.CreateProjectRequest{
+		Name: *name,
+		Slug: *slug,
+		Type: *projectType,
+	}
+
+	if *summary != "" {
+		req.Summary = summary
+	}
+
+	if err := validateRequest(&req); err != nil {
+		return err
+	}
+
+	c, err := newAuthenticatedClient()
+
+	if err != nil {
+		return err
+	}
+
+	project, err := c.CreateProject(ctx, req)
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Created project %s (%s)\n", project.Slug, project.Id)
+	return nil
+}
+
+func runListReleases(ctx context.Context, args []string) error {
+	fs := newFlagSet("releases list", "releases list <project>")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("expected a project id or slug")
+	}
+
+	creds, err := loadCredentials()
+
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tLOADER\tDOWNLOADS\tCREATED")
+
+	for r, err := range newClient(creds).AllReleases(ctx, fs.Arg(0), client.ListParams{}) {
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\n", r.VersionNumber, r.Name, r.LoaderVersion.VersionLabel, r.Downloads, r.CreatedAt.Format("2006-01-02"))
+	}
+
+	return w.Flush()
+}
+
+func runListLoaderVersions(ctx context.Context, args []string) error {
+	fs := newFlagSet("loader-versions", "loader-versions")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	creds, err := loadCredentials()
+
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tVERSION\tGAME VERSION\tBUILD\tRELEASED")
+
+	for lv, err := range newClient(creds).AllLoaderVersions(ctx, client.ListParams{}) {
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", lv.Id, lv.VersionLabel, lv.GameVersion, lv.BuildType, lv.ReleasedAt.Format("2006-01-02"))
+	}
+
+	return w.Flush()
+}
+
+type releaseFlags struct {
+	name          *string
+	version       *string
+	changelogFile *string
+	loaderVersion *string
+}
+
+func addReleaseFlags(fs *flag.FlagSet) releaseFlags {
+	return releaseFlags{
+		name:          fs.String("name", "", "release name, defaults to the version"),
+		version:       fs.String("version", "", "semver version number (required)"),
+		changelogFile: fs.String("changelog", "", "path to a file containing the changelog"),
+		loaderVersion: fs.String("loader-version", "", "loader version id, detected from the .tmod when omitted"),
+	}
+}
+
+// Builds the publish metadata from the flags. The loader version id is left empty when
+// it has to be detected from the file.
+func (f releaseFlags) request() (client.PublishReleaseRequest, error) {
+	req := client.PublishReleaseRequest{
+		Name:            *f.name,
+		VersionNumber:   *f.version,
+		LoaderVersionId: *f.loaderVersion,
+	}
+
+	if req.Name == "" {
+		req.Name = req.VersionNumber
+	}
+
+	if *f.changelogFile != "" {
+		b, err := os.ReadFile(*f.changelogFile)
+
+		if err != nil {
+			return req, fmt.Errorf("read changelog: %w", err)
+		}
+
+		changelog := string(b)
+		req.Changelog = &changelog
+	}
+
+	return req, nil
+}
+
+func runValidate(ctx context.Context, args []string) error {
+	fs := newFlagSet("validate", "validate [flags] <file.tmod>")
+	flags := addReleaseFlags(fs)
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("expected a .tmod file")
+	}
+
+	header, err := validateReleaseFile(fs.Arg(0))
+
+	if err != nil {
+		return err
+	}
+
+	req, err := flags.request()
+
+	if err != nil {
+		return err
+	}
+
+	if req.LoaderVersionId == "" {
+		// Resolved from the header on publish, a placeholder keeps the required check quiet
+		req.LoaderVersionId = header.LoaderVersion
+	}
+
+	if err := validateRequest(&req); err != nil {
+		return err
+	}
+
+	fmt.Printf("%s is valid (tModLoader %s)\n", fs.Arg(0), header.LoaderVersion)
+	return nil
+}
+
+func runPublish(ctx context.Context, args []string) error {
+	fs := newFlagSet("publish", "publish [flags] <project> <file.tmod>")
+	flags := addReleaseFlags(fs)
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return errors.New("expected a project id or slug and a .tmod file")
+	}
+
+	projectIdentifier, path := fs.Arg(0), fs.Arg(1)
+
+	header, err := validateReleaseFile(path)
+
+	if err != nil {
+		return err
+	}
+
+	req, err := flags.request()
+
+	if err != nil {
+		return err
+	}
+
+	c, err := newAuthenticatedClient()
+
+	if err != nil {
+		return err
+	}
+
+	if req.LoaderVersionId == "" {
+		req.LoaderVersionId, err = findLoaderVersionId(ctx, c, header.LoaderVersion)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := validateRequest(&req); err != nil {
+		return err
+	}
+
+	file, err := os.Open(path)
+
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	release, err := c.PublishRelease(ctx, projectIdentifier, req, filepath.Base(path), file)
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Published %s %s (%s)\n", projectIdentifier, release.VersionNumber, release.FileUrl)
+	return nil
+}
+
+// Checks the file is a .tmod within the size limit and returns its header.
+func validateReleaseFile(path string) (*tmod.Header, error) {
+	if filepath.Ext(path) != ".tmod" {
+		return nil, fmt.Errorf("%s: expected a .tmod file", path)
+	}
+
+	file, err := os.Open(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	info, err := file.Stat()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if info.Size() > service.MaxReleaseFileSize {
+		return nil, fmt.Errorf("%s: file is %d bytes, the limit is %d", path, info.Size(), service.MaxReleaseFileSize)
+	}
+
+	header, err := tmod.ReadHeader(file)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return header, nil
+}
+
+func findLoaderVersionId(ctx context.Context, c *client.Client, label string) (string, error) {
+	label = strings.TrimPrefix(label, "v")
+
+	for lv, err := range c.AllLoaderVersions(ctx, client.ListParams{}) {
+		if err != nil {
+			return "", err
+		}
+
+		if lv.VersionLabel == label {
+			return lv.Id, nil
+		}
+	}
+
+	return "", fmt.Errorf("tModLoader %s is not a known loader version, pass --loader-version", label)
+}
+
+// Runs the API's own validation rules so mistakes are caught before anything is uploaded.
+func validateRequest(req any) error {
+	v := &validation.Validator{Validator: validation.NewValidator(&config.Config{})}
+
+	err := v.Validate(req)
+
+	var valErr *validation.ValidationError
+
+	if !errors.As(err, &valErr) {
+		return err
+	}
+
+	messages := make([]string, 0, len(valErr.Errors))
+
+	for field, message := range valErr.Errors {
+		messages = append(messages, field+": "+message)
+	}
+
+	slices.Sort(messages)
+
+	return errors.New("invalid request:\n  " + strings.Join(messages, "\n  "))
+}
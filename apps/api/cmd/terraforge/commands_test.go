@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/terraforge-gg/terraforge/internal/lib/tmod"
+)
+
+// Writes a minimal .tmod: the magic bytes, a length-prefixed loader version and some payload.
+func writeTestTmodFile(t *testing.T, name string, loaderVersion string) string {
+	t.Helper()
+
+	b := []byte(tmod.Magic)
+	b = append(b, byte(len(loaderVersion)))
+	b = append(b, loaderVersion...)
+	b = append(b, "fake mod file content"...)
+
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, b, 0o600))
+
+	return path
+}
+
+func TestValidateReleaseFile(t *testing.T) {
+	// Arrange
+	path := writeTestTmodFile(t, "example.tmod", "v2024.5.3.0")
+
+	// Act
+	header, err := validateReleaseFile(path)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "v2024.5.3.0", header.LoaderVersion)
+}
+
+func TestValidateReleaseFile_WrongExtension(t *testing.T) {
+	// Arrange
+	path := writeTestTmodFile(t, "example.zip", "v2024.5.3.0")
+
+	// Act
+	_, err := validateReleaseFile(path)
+
+	// Assert
+	assert.ErrorContains(t, err, "expected a .tmod file")
+}
+
+func TestValidateReleaseFile_InvalidHeader(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "example.tmod")
+	require.NoError(t, os.WriteFile(path, []byte("PK\x03\x04"), 0o600))
+
+	// Act
+	_, err := validateReleaseFile(path)
+
+	// Assert
+	assert.ErrorIs(t, err, tmod.ErrInvalidFile)
+}
+
+func TestValidateReleaseFile_Missing(t *testing.T) {
+	// Act
+	_, err := validateReleaseFile(filepath.Join(t.TempDir(), "missing.tmod"))
+
+	// Assert
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestRunValidate(t *testing.T) {
+	// Arrange
+	path := writeTestTmodFile(t, "example.tmod", "v2024.5.3.0")
+
+	// Act
+	err := runValidate(context.Background(), []string{"--version", "1.2.0", path})
+
+	// Assert
+	assert.NoError(t, err)
+}
+
+func TestRunValidate_InvalidVersion(t *testing.T) {
+	// Arrange
+	path := writeTestTmodFile(t, "example.tmod", "v2024.5.3.0")
+
+	// Act
+	err := runValidate(context.Background(), []string{"--version", "latest", path})
+
+	// Assert
+	assert.ErrorContains(t, err, "invalid request")
+	assert.ErrorContains(t, err, "versionNumber")
+}
+
+func TestRunValidate_MissingChangelog(t *testing.T) {
+	// Arrange
+	path := writeTestTmodFile(t, "example.tmod", "v2024.5.3.0")
+
+	// Act
+	err := runValidate(context.Background(), []string{"--version", "1.2.0", "--changelog", filepath.Join(t.TempDir(), "CHANGELOG.md"), path})
+
+	// Assert
+	assert.ErrorContains(t, err, "read changelog")
+}
+
+func TestRunValidate_NoFile(t *testing.T) {
+	// Act
+	err := runValidate(context.Background(), []string{"--version", "1.2.0"})
+
+	// Assert
+	assert.ErrorContains(t, err, "expected a .tmod file")
+}
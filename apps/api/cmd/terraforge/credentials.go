@@ -0,0 +1,106 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/terraforge-gg/terraforge/client"
+)
+
+const defaultApiUrl = "https://api.terraforge.gg"
+
+type credentials struct {
+	ApiUrl string `json:"apiUrl"`
+	Token  string `json:"token"`
+}
+
+func credentialsPath() (string, error) {
+	dir, err := os.UserConfigDir()
+
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "terraforge", "credentials.json"), nil
+}
+
+// Loads the saved login, with TERRAFORGE_API_URL and TERRAFORGE_TOKEN taking precedence.
+func loadCredentials() (*credentials, error) {
+	creds := &credentials{ApiUrl: defaultApiUrl}
+
+	path, err := credentialsPath()
+
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := os.ReadFile(path)
+
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	if err == nil {
+		if err := json.Unmarshal(b, creds); err != nil {
+			return nil, fmt.Errorf("read %s: %w", path, err)
+		}
+	}
+
+	if apiUrl := os.Getenv("TERRAFORGE_API_URL"); apiUrl != "" {
+		creds.ApiUrl = apiUrl
+	}
+
+	if token := os.Getenv("TERRAFORGE_TOKEN"); token != "" {
+		creds.Token = token
+	}
+
+	return creds, nil
+}
+
+func saveCredentials(creds *credentials) (string, error) {
+	path, err := credentialsPath()
+
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return "", err
+	}
+
+	b, err := json.MarshalIndent(creds, "", "  ")
+
+	if err != nil {
+		return "", err
+	}
+
+	// The token grants write access to the user's projects, keep it private. WriteFile only
+	// applies the mode to new files, so an existing file is tightened as well.
+	if err := os.WriteFile(path, b, 0o600); err != nil {
+		return "", err
+	}
+
+	return path, os.Chmod(path, 0o600)
+}
+
+func newClient(creds *credentials) *client.Client {
+	return client.New(creds.ApiUrl, client.WithToken(creds.Token), client.WithUserAgent("terraforge-cli"))
+}
+
+// Loads the saved login and fails when there is no token, for commands that write.
+func newAuthenticatedClient() (*client.Client, error) {
+	creds, err := loadCredentials()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if creds.Token == "" {
+		return nil, errors.New("not logged in, run 'terraforge login' or set TERRAFORGE_TOKEN")
+	}
+
+	return newClient(creds), nil
+}
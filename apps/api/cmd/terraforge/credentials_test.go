@@ -0,0 +1,139 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Points os.UserConfigDir at a temporary directory and clears the env overrides.
+func setupConfigDir(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("HOME", dir)
+	t.Setenv("AppData", dir)
+	t.Setenv("TERRAFORGE_API_URL", "")
+	t.Setenv("TERRAFORGE_TOKEN", "")
+
+	return dir
+}
+
+func TestLoadCredentials_Defaults(t *testing.T) {
+	// Arrange
+	setupConfigDir(t)
+
+	// Act
+	creds, err := loadCredentials()
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, defaultApiUrl, creds.ApiUrl)
+	assert.Empty(t, creds.Token)
+}
+
+func TestLoadCredentials_Saved(t *testing.T) {
+	// Arrange
+	setupConfigDir(t)
+
+	_, err := saveCredentials(&credentials{ApiUrl: "http://localhost:8080", Token: "tf_saved"})
+	require.NoError(t, err)
+
+	// Act
+	creds, err := loadCredentials()
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:8080", creds.ApiUrl)
+	assert.Equal(t, "tf_saved", creds.Token)
+}
+
+func TestLoadCredentials_EnvOverridesSaved(t *testing.T) {
+	// Arrange
+	setupConfigDir(t)
+
+	_, err := saveCredentials(&credentials{ApiUrl: "http://localhost:8080", Token: "tf_saved"})
+	require.NoError(t, err)
+
+	t.Setenv("TERRAFORGE_API_URL", "https://staging.terraforge.gg")
+	t.Setenv("TERRAFORGE_TOKEN", "tf_env")
+
+	// Act
+	creds, err := loadCredentials()
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "https://staging.terraforge.gg", creds.ApiUrl)
+	assert.Equal(t, "tf_env", creds.Token)
+}
+
+func TestLoadCredentials_EnvTokenWithoutSavedLogin(t *testing.T) {
+	// Arrange
+	setupConfigDir(t)
+	t.Setenv("TERRAFORGE_TOKEN", "tf_env")
+
+	// Act
+	creds, err := loadCredentials()
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, defaultApiUrl, creds.ApiUrl)
+	assert.Equal(t, "tf_env", creds.Token)
+}
+
+func TestLoadCredentials_InvalidFile(t *testing.T) {
+	// Arrange
+	setupConfigDir(t)
+
+	path, err := credentialsPath()
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0o600))
+
+	// Act
+	_, err = loadCredentials()
+
+	// Assert
+	assert.ErrorContains(t, err, path)
+}
+
+func TestSaveCredentials_TightensExistingFile(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file modes are not enforced on windows")
+	}
+
+	// Arrange
+	setupConfigDir(t)
+
+	path, err := credentialsPath()
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+	require.NoError(t, os.WriteFile(path, []byte("{}"), 0o644))
+
+	// Act
+	saved, err := saveCredentials(&credentials{ApiUrl: defaultApiUrl, Token: "tf_saved"})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, path, saved)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+}
+
+func TestNewAuthenticatedClient_NotLoggedIn(t *testing.T) {
+	// Arrange
+	setupConfigDir(t)
+
+	// Act
+	_, err := newAuthenticatedClient()
+
+	// Assert
+	assert.ErrorContains(t, err, "not logged in")
+}
@@ -0,0 +1,69 @@
+// Command terraforge publishes and manages Terraforge projects from the command line.
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+)
+
+const usage = `Usage: terraforge <command> [flags]
+
+Commands:
+  login            Save an API token for later commands
+  logout           Remove the saved API token
+  projects create  Create a project
+  releases list    List the releases of a project
+  loader-versions  List tModLoader versions
+  validate         Check a .tmod file and release metadata without uploading
+  publish          Validate and upload a .tmod file as a new release
+
+Run 'terraforge <command> -h' for the flags of a command.
+The TERRAFORGE_TOKEN and TERRAFORGE_API_URL environment variables override the saved login.
+`
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := run(ctx, os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, usage)
+		return nil
+	}
+
+	switch args[0] {
+	case "login":
+		return runLogin(ctx, args[1:])
+	case "logout":
+		return runLogout()
+	case "projects":
+		if len(args) > 1 && args[1] == "create" {
+			return runCreateProject(ctx, args[2:])
+		}
+	case "releases":
+		if len(args) > 1 && args[1] == "list" {
+			return runListReleases(ctx, args[2:])
+		}
+	case "loader-versions":
+		return runListLoaderVersions(ctx, args[1:])
+	case "validate":
+		return runValidate(ctx, args[1:])
+	case "publish":
+		return runPublish(ctx, args[1:])
+	case "help", "-h", "--help":
+		fmt.Fprint(os.Stdout, usage)
+		return nil
+	}
+
+	fmt.Fprint(os.Stderr, usage)
+	return fmt.Errorf("unknown command %q", strings.Join(args[:min(len(args), 2)], " "))
+}
@@ -10,6 +10,11 @@ import (
 	"github.com/lestrrat-go/jwx/v3/jwk"
 )
 
+const (
+	TestIssuer   = "test-issuer"
+	TestAudience = "test-audience"
+)
+
 type TestAuth struct {
 	Key  *ecdsa.PrivateKey
 	JWKS jwk.Set
@@ -35,17 +40,46 @@ func NewTestAuth() (*TestAuth, error) {
 	return &TestAuth{Key: key, JWKS: jwks}, nil
 }
 
+// Options matching the claims of generated tokens.
+func (ta *TestAuth) ValidatorOptions() ValidatorOptions {
+	return ValidatorOptions{
+		Issuer:   TestIssuer,
+		Audience: TestAudience,
+	}
+}
+
 func (ta *TestAuth) GenerateToken(userId string, username string, email string) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
-		"id":       userId,
-		"name":     username,
-		"username": username,
-		"email":    email,
-		"sub":      userId,
-		"iat":      time.Now().Unix(),
-		"exp":      time.Now().Add(time.Hour).Unix(),
-		"iss":      "test-issuer",
-	})
+	return ta.GenerateTokenWithClaims(userId, username, email, nil)
+}
+
+// Generates a token with extra claims such as "role", overriding the defaults. A nil value
+// removes the claim.
+func (ta *TestAuth) GenerateTokenWithClaims(userId string, username string, email string, claims map[string]any) (string, error) {
+	mapClaims := jwt.MapClaims{
+		"id":            userId,
+		"name":          username,
+		"username":      username,
+		"email":         email,
+		"emailVerified": true,
+		"sub":           userId,
+		"sid":           "session-" + userId,
+		"token_type":    AccessTokenType,
+		"iat":           time.Now().Unix(),
+		"exp":           time.Now().Add(time.Hour).Unix(),
+		"iss":           TestIssuer,
+		"aud":           TestAudience,
+	}
+
+	for k, v := range claims {
+		if v == nil {
+			delete(mapClaims, k)
+			continue
+		}
+
+		mapClaims[k] = v
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, mapClaims)
 	token.Header["kid"] = "test-key-id"
 	return token.SignedString(ta.Key)
 }
@@ -2,22 +2,52 @@ package auth
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/lestrrat-go/httprc/v3"
 	"github.com/lestrrat-go/jwx/v3/jwk"
+	"github.com/lestrrat-go/jwx/v3/jws"
 	"github.com/lestrrat-go/jwx/v3/jwt"
 )
 
+// Value of the "token_type" claim on access tokens issued by the auth app.
+const AccessTokenType = "access"
+
+// Claims every access token must carry, on top of the issuer and audience.
+var requiredClaims = []string{
+	jwt.SubjectKey,
+	jwt.IssuedAtKey,
+	jwt.ExpirationKey,
+	"id",
+	"sid",
+	"token_type",
+}
+
+var ErrUnknownKeyId = errors.New("unknown key id")
+
+type ValidatorOptions struct {
+	Issuer   string
+	Audience string
+	// Minimum time between JWKS refreshes triggered by tokens signed with an unknown key
+	RefreshInterval time.Duration
+}
+
 type Validator struct {
-	keySet jwk.Set
-	cache  *jwk.Cache
-	ctx    context.Context
-	cancel context.CancelFunc
+	keySet  jwk.Set
+	cache   *jwk.Cache
+	jwksURL string
+	opts    ValidatorOptions
+	ctx     context.Context
+	cancel  context.CancelFunc
+
+	refreshMu   sync.Mutex
+	lastRefresh time.Time
 }
 
-func NewValidator(jwksURL string) (*Validator, error) {
+func NewValidator(jwksURL string, opts ValidatorOptions) (*Validator, error) {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	c, err := jwk.NewCache(ctx, httprc.NewClient())
@@ -44,10 +74,13 @@ func NewValidator(jwksURL string) (*Validator, error) {
 	}
 
 	return &Validator{
-		keySet: cachedSet,
-		cache:  c,
-		ctx:    ctx,
-		cancel: cancel,
+		keySet:      cachedSet,
+		cache:       c,
+		jwksURL:     jwksURL,
+		opts:        opts,
+		ctx:         ctx,
+		cancel:      cancel,
+		lastRefresh: time.Now(),
 	}, nil
 }
 
@@ -56,11 +89,24 @@ func (v *Validator) Close() {
 }
 
 func (v *Validator) ValidateToken(tokenString string) (jwt.Token, error) {
-	token, err := jwt.Parse([]byte(tokenString),
+	if err := v.ensureKey(tokenString); err != nil {
+		return nil, fmt.Errorf("invalid token: %w", err)
+	}
+
+	options := []jwt.ParseOption{
 		jwt.WithKeySet(v.keySet),
 		jwt.WithValidate(true),
-		jwt.WithAcceptableSkew(30*time.Second),
-	)
+		jwt.WithAcceptableSkew(30 * time.Second),
+		jwt.WithIssuer(v.opts.Issuer),
+		jwt.WithAudience(v.opts.Audience),
+		jwt.WithClaimValue("token_type", AccessTokenType),
+	}
+
+	for _, claim := range requiredClaims {
+		options = append(options, jwt.WithRequiredClaim(claim))
+	}
+
+	token, err := jwt.Parse([]byte(tokenString), options...)
 	if err != nil {
 		return nil, fmt.Errorf("invalid token: %w", err)
 	}
@@ -68,11 +114,70 @@ func (v *Validator) ValidateToken(tokenString string) (jwt.Token, error) {
 	return token, nil
 }
 
-func NewValidatorFromJWKS(keySet jwk.Set) *Validator {
+// Makes sure the key the token was signed with is in the key set. The auth app rotates
+// keys, so an unknown key id triggers a JWKS refresh, at most once per RefreshInterval
+// so garbage tokens can't be used to hammer the auth app.
+func (v *Validator) ensureKey(tokenString string) error {
+	msg, err := jws.Parse([]byte(tokenString))
+
+	if err != nil {
+		return err
+	}
+
+	signatures := msg.Signatures()
+
+	if len(signatures) != 1 {
+		return errors.New("expected a single signature")
+	}
+
+	kid, ok := signatures[0].ProtectedHeaders().KeyID()
+
+	if !ok || kid == "" {
+		return ErrUnknownKeyId
+	}
+
+	if _, ok := v.keySet.LookupKeyID(kid); ok {
+		return nil
+	}
+
+	if v.cache == nil {
+		return ErrUnknownKeyId
+	}
+
+	v.refreshMu.Lock()
+	defer v.refreshMu.Unlock()
+
+	// Another request may have refreshed while this one waited for the lock
+	if _, ok := v.keySet.LookupKeyID(kid); ok {
+		return nil
+	}
+
+	if time.Since(v.lastRefresh) < v.opts.RefreshInterval {
+		return ErrUnknownKeyId
+	}
+
+	v.lastRefresh = time.Now()
+
+	ctx, cancel := context.WithTimeout(v.ctx, 10*time.Second)
+	defer cancel()
+
+	if _, err := v.cache.Refresh(ctx, v.jwksURL); err != nil {
+		return fmt.Errorf("failed to refresh JWKS: %w", err)
+	}
+
+	if _, ok := v.keySet.LookupKeyID(kid); !ok {
+		return ErrUnknownKeyId
+	}
+
+	return nil
+}
+
+func NewValidatorFromJWKS(keySet jwk.Set, opts ValidatorOptions) *Validator {
 	ctx, cancel := context.WithCancel(context.Background())
 	return &Validator{
 		keySet: keySet,
 		cache:  nil,
+		opts:   opts,
 		ctx:    ctx,
 		cancel: cancel,
 	}
@@ -0,0 +1,164 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/lestrrat-go/jwx/v3/jwk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidator_ValidToken(t *testing.T) {
+	ta, err := NewTestAuth()
+	require.NoError(t, err)
+	v := NewValidatorFromJWKS(ta.JWKS, ta.ValidatorOptions())
+
+	token, err := ta.GenerateToken("1", "testuser1", "testuser1@example.com")
+	require.NoError(t, err)
+
+	parsed, err := v.ValidateToken(token)
+
+	require.NoError(t, err)
+	var sid string
+	require.NoError(t, parsed.Get("sid", &sid))
+	assert.Equal(t, "session-1", sid)
+}
+
+func TestValidator_RejectsInvalidClaims(t *testing.T) {
+	ta, err := NewTestAuth()
+	require.NoError(t, err)
+	v := NewValidatorFromJWKS(ta.JWKS, ta.ValidatorOptions())
+
+	cases := map[string]map[string]any{
+		"wrong issuer":       {"iss": "https://evil.example.com"},
+		"wrong audience":     {"aud": "another-service"},
+		"wrong token type":   {"token_type": "refresh"},
+		"missing session id": {"sid": nil},
+		"missing user id":    {"id": nil},
+		"missing expiry":     {"exp": nil},
+		"expired":            {"exp": time.Now().Add(-time.Hour).Unix()},
+	}
+
+	for name, claims := range cases {
+		t.Run(name, func(t *testing.T) {
+			token, err := ta.GenerateTokenWithClaims("1", "testuser1", "testuser1@example.com", claims)
+			require.NoError(t, err)
+
+			_, err = v.ValidateToken(token)
+
+			assert.Error(t, err)
+		})
+	}
+}
+
+// Serves a key set that can be swapped out, counting fetches.
+type testJWKSServer struct {
+	mu      sync.Mutex
+	set     jwk.Set
+	fetches atomic.Int32
+}
+
+func (s *testJWKSServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.fetches.Add(1)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(s.set)
+}
+
+func (s *testJWKSServer) addKey(t *testing.T, kid string) *ecdsa.PrivateKey {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	pubKey, err := jwk.Import(key.Public())
+	require.NoError(t, err)
+	require.NoError(t, pubKey.Set("kid", kid))
+	require.NoError(t, pubKey.Set("alg", "ES256"))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	require.NoError(t, s.set.AddKey(pubKey))
+
+	return key
+}
+
+func signTestToken(t *testing.T, key *ecdsa.PrivateKey, kid string) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"id":         "1",
+		"sub":        "1",
+		"sid":        "session-1",
+		"token_type": AccessTokenType,
+		"iat":        time.Now().Unix(),
+		"exp":        time.Now().Add(time.Hour).Unix(),
+		"iss":        TestIssuer,
+		"aud":        TestAudience,
+	})
+	token.Header["kid"] = kid
+
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+
+	return signed
+}
+
+func TestValidator_RefreshesOnUnknownKeyId(t *testing.T) {
+	jwks := &testJWKSServer{set: jwk.NewSet()}
+	jwks.addKey(t, "key-1")
+	server := httptest.NewServer(jwks)
+	t.Cleanup(server.Close)
+
+	v, err := NewValidator(server.URL, ValidatorOptions{
+		Issuer:   TestIssuer,
+		Audience: TestAudience,
+	})
+	require.NoError(t, err)
+	t.Cleanup(v.Close)
+
+	initialFetches := jwks.fetches.Load()
+	rotatedKey := jwks.addKey(t, "key-2")
+
+	_, err = v.ValidateToken(signTestToken(t, rotatedKey, "key-2"))
+
+	require.NoError(t, err)
+	assert.Equal(t, initialFetches+1, jwks.fetches.Load())
+}
+
+func TestValidator_RateLimitsRefreshes(t *testing.T) {
+	jwks := &testJWKSServer{set: jwk.NewSet()}
+	jwks.addKey(t, "key-1")
+	server := httptest.NewServer(jwks)
+	t.Cleanup(server.Close)
+
+	v, err := NewValidator(server.URL, ValidatorOptions{
+		Issuer:          TestIssuer,
+		Audience:        TestAudience,
+		RefreshInterval: time.Hour,
+	})
+	require.NoError(t, err)
+	t.Cleanup(v.Close)
+
+	// Within the interval of the initial fetch, so no refresh is allowed yet
+	initialFetches := jwks.fetches.Load()
+	rotatedKey := jwks.addKey(t, "key-2")
+
+	for range 5 {
+		_, err = v.ValidateToken(signTestToken(t, rotatedKey, "key-2"))
+		assert.ErrorIs(t, err, ErrUnknownKeyId)
+	}
+
+	assert.Equal(t, initialFetches, jwks.fetches.Load())
+}
@@ -0,0 +1,79 @@
+package background
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// Group runs the work done outside of requests so shutdown can wait for it. Tasks are short,
+// such as indexing a document after a request, and are allowed to finish. Workers loop until
+// they are told to stop, such as the user search sync.
+type Group struct {
+	wg      sync.WaitGroup
+	running atomic.Int64
+	// Cancelled when shutdown starts
+	workers     context.Context
+	stopWorkers context.CancelFunc
+	// Cancelled once shutdown gives up waiting
+	tasks       context.Context
+	cancelTasks context.CancelFunc
+}
+
+func NewGroup() *Group {
+	g := &Group{}
+	g.workers, g.stopWorkers = context.WithCancel(context.Background())
+	g.tasks, g.cancelTasks = context.WithCancel(context.Background())
+
+	return g
+}
+
+// Go runs task in the background. Its context keeps the values of ctx, such as the request
+// it was started for, but is only cancelled when shutdown runs out of time.
+func (g *Group) Go(ctx context.Context, task func(ctx context.Context)) {
+	g.running.Add(1)
+	g.wg.Go(func() {
+		defer g.running.Add(-1)
+
+		taskCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		defer cancel()
+
+		stop := context.AfterFunc(g.tasks, cancel)
+		defer stop()
+
+		task(taskCtx)
+	})
+}
+
+// Running reports how many tasks have been started and not yet returned.
+func (g *Group) Running() int64 {
+	return g.running.Load()
+}
+
+// Start runs worker in the background. Its context is cancelled as soon as shutdown starts.
+func (g *Group) Start(worker func(ctx context.Context)) {
+	g.wg.Go(func() {
+		worker(g.workers)
+	})
+}
+
+// Shutdown stops the workers and waits for them and every running task to return. When ctx
+// is done first the remaining tasks are cancelled and ctx's error is returned.
+func (g *Group) Shutdown(ctx context.Context) error {
+	g.stopWorkers()
+	defer g.cancelTasks()
+
+	done := make(chan struct{})
+
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
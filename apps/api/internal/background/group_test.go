@@ -0,0 +1,86 @@
+package background
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testKey struct{}
+
+func TestGroup_ShutdownWaitsForTasks(t *testing.T) {
+	g := NewGroup()
+	var finished atomic.Bool
+	var value atomic.Value
+
+	// Tasks outlive the request that started them but keep its values
+	requestCtx, cancelRequest := context.WithCancel(context.WithValue(context.Background(), testKey{}, "request-1"))
+	g.Go(requestCtx, func(ctx context.Context) {
+		time.Sleep(50 * time.Millisecond)
+		finished.Store(ctx.Err() == nil)
+		value.Store(ctx.Value(testKey{}))
+	})
+	cancelRequest()
+
+	err := g.Shutdown(context.Background())
+
+	assert.NoError(t, err)
+	assert.True(t, finished.Load())
+	assert.Equal(t, "request-1", value.Load())
+}
+
+func TestGroup_ShutdownStopsWorkers(t *testing.T) {
+	g := NewGroup()
+	var stopped atomic.Bool
+
+	g.Start(func(ctx context.Context) {
+		<-ctx.Done()
+		stopped.Store(true)
+	})
+
+	err := g.Shutdown(context.Background())
+
+	assert.NoError(t, err)
+	assert.True(t, stopped.Load())
+}
+
+func TestGroup_ShutdownDeadlineCancelsTasks(t *testing.T) {
+	g := NewGroup()
+	cancelled := make(chan struct{})
+
+	g.Go(context.Background(), func(ctx context.Context) {
+		<-ctx.Done()
+		close(cancelled)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := g.Shutdown(ctx)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("task was not cancelled")
+	}
+}
+
+func TestGroup_Running(t *testing.T) {
+	g := NewGroup()
+	release := make(chan struct{})
+
+	g.Go(context.Background(), func(ctx context.Context) { <-release })
+	g.Go(context.Background(), func(ctx context.Context) { <-release })
+	// Workers are not counted, they run for as long as the server does
+	g.Start(func(ctx context.Context) { <-ctx.Done() })
+
+	assert.EqualValues(t, 2, g.Running())
+
+	close(release)
+	assert.NoError(t, g.Shutdown(context.Background()))
+	assert.EqualValues(t, 0, g.Running())
+}
@@ -0,0 +1,72 @@
+package cache
+
+import (
+	"encoding/json"
+	"math/rand/v2"
+	"time"
+)
+
+// Entries stay in Redis for their soft TTL plus this multiple of it, so stale data
+// can be served while a single caller refreshes it.
+const staleWindowFactor = 1
+
+// Expirations are spread by up to ±10% so keys written together do not expire together.
+const jitterFraction = 0.1
+
+type entry struct {
+	Value         json.RawMessage `json:"value"`
+	SoftExpiresAt int64           `json:"softExpiresAt"`
+}
+
+// Wraps value with its soft expiry and returns the encoded entry and the hard TTL to store it with.
+func newEntry(value any, ttl time.Duration) ([]byte, time.Duration, error) {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	softTTL := jitter(ttl)
+	hardTTL := softTTL + jitter(ttl*staleWindowFactor)
+
+	e, err := json.Marshal(entry{
+		Value:         b,
+		SoftExpiresAt: time.Now().Add(softTTL).UnixMilli(),
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return e, hardTTL, nil
+}
+
+// Decodes an entry into out. Returns ErrCacheStale when the entry is past its soft TTL.
+func readEntry(raw string, out any) error {
+	var e entry
+	if err := json.Unmarshal([]byte(raw), &e); err != nil {
+		return err
+	}
+
+	// Written by a version that stored bare values; let it be reloaded.
+	if len(e.Value) == 0 {
+		return ErrCacheMiss
+	}
+
+	if err := json.Unmarshal(e.Value, out); err != nil {
+		return err
+	}
+
+	if time.Now().UnixMilli() >= e.SoftExpiresAt {
+		return ErrCacheStale
+	}
+
+	return nil
+}
+
+func jitter(ttl time.Duration) time.Duration {
+	if ttl <= 0 {
+		return ttl
+	}
+
+	spread := float64(ttl) * jitterFraction
+	return ttl + time.Duration((rand.Float64()*2-1)*spread)
+}
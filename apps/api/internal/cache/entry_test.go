@@ -0,0 +1,45 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEntry_FreshRoundTrip(t *testing.T) {
+	raw, hardTTL, err := newEntry(map[string]string{"id": "1"}, time.Minute)
+	require.NoError(t, err)
+
+	assert.Greater(t, hardTTL, time.Minute)
+
+	var out map[string]string
+	require.NoError(t, readEntry(string(raw), &out))
+	assert.Equal(t, "1", out["id"])
+}
+
+func TestEntry_StaleReturnsValue(t *testing.T) {
+	raw := `{"value":{"id":"1"},"softExpiresAt":1}`
+
+	var out map[string]string
+	err := readEntry(raw, &out)
+
+	assert.ErrorIs(t, err, ErrCacheStale)
+	assert.Equal(t, "1", out["id"])
+}
+
+func TestEntry_LegacyValueIsMiss(t *testing.T) {
+	var out map[string]string
+	err := readEntry(`{"id":"1"}`, &out)
+
+	assert.ErrorIs(t, err, ErrCacheMiss)
+}
+
+func TestJitter_StaysWithinSpread(t *testing.T) {
+	for range 100 {
+		d := jitter(time.Minute)
+		assert.GreaterOrEqual(t, d, 54*time.Second)
+		assert.LessOrEqual(t, d, 66*time.Second)
+	}
+}
@@ -1,7 +1,37 @@
 package cache
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/redis/go-redis/v9"
+	redis_client_wrapper "github.com/terraforge-gg/terraforge/internal/lib/redis"
+	"github.com/terraforge-gg/terraforge/internal/metrics"
+)
 
 var (
 	ErrCacheMiss = errors.New("cache miss")
+	// ErrCacheStale is returned alongside a value that is past its soft TTL.
+	// The value is still safe to serve while it is refreshed in the background.
+	ErrCacheStale = errors.New("cache stale")
 )
+
+// Redis being unavailable is reported as a miss so callers fall back to the database.
+func isMiss(err error) bool {
+	return errors.Is(err, redis.Nil) || errors.Is(err, redis_client_wrapper.ErrCircuitOpen)
+}
+
+// Counts a lookup by its result so hit ratios can be graphed per cache.
+func recordLookup(cache string, err error) {
+	result := "error"
+
+	switch {
+	case err == nil:
+		result = "hit"
+	case errors.Is(err, ErrCacheStale):
+		result = "stale"
+	case errors.Is(err, ErrCacheMiss):
+		result = "miss"
+	}
+
+	metrics.CacheRequests.WithLabelValues(cache, result).Inc()
+}
@@ -0,0 +1,61 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const invalidationChannel = "cache:invalidate"
+
+type invalidationMessage struct {
+	Origin    string `json:"origin"`
+	ProjectId string `json:"projectId"`
+}
+
+// Drops the local entries for a project and tells every other instance to do the same.
+func (l *LocalCache) publishInvalidation(ctx context.Context, client *redis.Client, projectId string) error {
+	l.Invalidate(projectId)
+
+	b, err := json.Marshal(invalidationMessage{Origin: l.id, ProjectId: projectId})
+	if err != nil {
+		return err
+	}
+
+	return client.Publish(ctx, invalidationChannel, b).Err()
+}
+
+// Listen applies invalidations published by other instances until ctx is cancelled.
+// go-redis resubscribes on reconnect, but messages sent while disconnected are lost,
+// which the short local TTL bounds.
+func (l *LocalCache) Listen(ctx context.Context, client *redis.Client, logger *slog.Logger) {
+	pubsub := client.Subscribe(ctx, invalidationChannel)
+	defer pubsub.Close()
+
+	ch := pubsub.Channel()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+
+			var m invalidationMessage
+			if err := json.Unmarshal([]byte(msg.Payload), &m); err != nil {
+				logger.Warn("Invalid cache invalidation message", "error", err)
+				continue
+			}
+
+			if m.Origin == l.id {
+				continue
+			}
+
+			l.Invalidate(m.ProjectId)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	redis_client_wrapper "github.com/terraforge-gg/terraforge/internal/lib/redis"
+	"github.com/terraforge-gg/terraforge/internal/models"
+)
+
+type LoaderVersionCache interface {
+	GetLoaderVersions(ctx context.Context) ([]models.LoaderVersion, error)
+	SetLoaderVersions(ctx context.Context, loaderVersions []models.LoaderVersion, ttl time.Duration) error
+	DeleteLoaderVersions(ctx context.Context) error
+}
+
+type loaderVersionCache struct {
+	Wrapper *redis_client_wrapper.RedisClient
+}
+
+func NewLoaderVersionCache(redisWrapper *redis_client_wrapper.RedisClient) LoaderVersionCache {
+	return &loaderVersionCache{
+		Wrapper: redisWrapper,
+	}
+}
+
+func (c *loaderVersionCache) GetLoaderVersions(ctx context.Context) ([]models.LoaderVersion, error) {
+	val, err := c.Wrapper.Client.Get(ctx, loaderVersionsKey).Result()
+	if isMiss(err) {
+		return nil, ErrCacheMiss
+	}
+	if err != nil {
+		return nil, fmt.Errorf("redis get: %w", err)
+	}
+
+	var loaderVersions []models.LoaderVersion
+	if err := readEntry(val, &loaderVersions); err != nil {
+		if errors.Is(err, ErrCacheStale) {
+			return loaderVersions, err
+		}
+		if errors.Is(err, ErrCacheMiss) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("unmarshal loader versions: %w", err)
+	}
+	return loaderVersions, nil
+}
+
+func (c *loaderVersionCache) SetLoaderVersions(ctx context.Context, loaderVersions []models.LoaderVersion, ttl time.Duration) error {
+	b, hardTTL, err := newEntry(loaderVersions, ttl)
+	if err != nil {
+		return fmt.Errorf("marshal loader versions: %w", err)
+	}
+
+	return c.Wrapper.Client.Set(ctx, loaderVersionsKey, b, hardTTL).Err()
+}
+
+func (c *loaderVersionCache) DeleteLoaderVersions(ctx context.Context) error {
+	if err := c.Wrapper.Client.Del(ctx, loaderVersionsKey).Err(); err != nil {
+		return fmt.Errorf("redis del: %w", err)
+	}
+
+	return nil
+}
+
+const loaderVersionsKey = "loader_versions"
@@ -0,0 +1,124 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/terraforge-gg/terraforge/internal/utils"
+)
+
+// LocalCache is a bounded in-process LRU that sits in front of Redis for hot keys.
+// Entries are tagged with the project they belong to so a single invalidation message
+// can drop every key derived from that project.
+type LocalCache struct {
+	mu    sync.Mutex
+	size  int
+	ttl   time.Duration
+	ll    *list.List
+	items map[string]*list.Element
+	tags  map[string]map[string]struct{}
+	// Identifies this instance on the invalidation channel so it can ignore its own messages.
+	id string
+}
+
+type localItem struct {
+	key       string
+	tag       string
+	value     string
+	expiresAt time.Time
+}
+
+// Returns nil when size is not positive so callers can treat the local tier as disabled.
+func NewLocalCache(size int, ttl time.Duration) *LocalCache {
+	if size <= 0 || ttl <= 0 {
+		return nil
+	}
+
+	return &LocalCache{
+		size:  size,
+		ttl:   ttl,
+		ll:    list.New(),
+		items: make(map[string]*list.Element),
+		tags:  make(map[string]map[string]struct{}),
+		id:    utils.NewUUID(),
+	}
+}
+
+func (l *LocalCache) Get(key string) (string, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	el, ok := l.items[key]
+	if !ok {
+		return "", false
+	}
+
+	item := el.Value.(*localItem)
+	if time.Now().After(item.expiresAt) {
+		l.remove(el)
+		return "", false
+	}
+
+	l.ll.MoveToFront(el)
+	return item.value, true
+}
+
+func (l *LocalCache) Set(key string, tag string, value string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if el, ok := l.items[key]; ok {
+		l.remove(el)
+	}
+
+	el := l.ll.PushFront(&localItem{
+		key:       key,
+		tag:       tag,
+		value:     value,
+		expiresAt: time.Now().Add(l.ttl),
+	})
+	l.items[key] = el
+
+	if l.tags[tag] == nil {
+		l.tags[tag] = make(map[string]struct{})
+	}
+	l.tags[tag][key] = struct{}{}
+
+	for l.ll.Len() > l.size {
+		l.remove(l.ll.Back())
+	}
+}
+
+// Drops every key stored under tag.
+func (l *LocalCache) Invalidate(tag string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for key := range l.tags[tag] {
+		if el, ok := l.items[key]; ok {
+			l.remove(el)
+		}
+	}
+}
+
+func (l *LocalCache) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.ll.Len()
+}
+
+func (l *LocalCache) remove(el *list.Element) {
+	item := el.Value.(*localItem)
+
+	l.ll.Remove(el)
+	delete(l.items, item.key)
+
+	if keys, ok := l.tags[item.tag]; ok {
+		delete(keys, item.key)
+		if len(keys) == 0 {
+			delete(l.tags, item.tag)
+		}
+	}
+}
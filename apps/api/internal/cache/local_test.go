@@ -0,0 +1,55 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalCache_DisabledWhenSizeIsZero(t *testing.T) {
+	assert.Nil(t, NewLocalCache(0, time.Second))
+}
+
+func TestLocalCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	l := NewLocalCache(2, time.Minute)
+
+	l.Set("a", "p1", "1")
+	l.Set("b", "p2", "2")
+	_, _ = l.Get("a")
+	l.Set("c", "p3", "3")
+
+	_, ok := l.Get("b")
+	assert.False(t, ok)
+
+	val, ok := l.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, "1", val)
+	assert.Equal(t, 2, l.Len())
+}
+
+func TestLocalCache_ExpiresEntries(t *testing.T) {
+	l := NewLocalCache(2, time.Millisecond)
+
+	l.Set("a", "p1", "1")
+	time.Sleep(5 * time.Millisecond)
+
+	_, ok := l.Get("a")
+	assert.False(t, ok)
+	assert.Equal(t, 0, l.Len())
+}
+
+func TestLocalCache_InvalidateDropsEveryKeyForProject(t *testing.T) {
+	l := NewLocalCache(10, time.Minute)
+
+	l.Set(projectKey("p1"), "p1", "{}")
+	l.Set(slugKey("slug"), "p1", "p1")
+	l.Set(projectMembersKey("p1"), "p1", "[]")
+	l.Set(projectKey("p2"), "p2", "{}")
+
+	l.Invalidate("p1")
+
+	assert.Equal(t, 1, l.Len())
+	_, ok := l.Get(projectKey("p2"))
+	assert.True(t, ok)
+}
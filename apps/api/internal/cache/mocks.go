@@ -5,14 +5,15 @@ import (
 	"time"
 
 	"github.com/terraforge-gg/terraforge/internal/models"
+	"github.com/terraforge-gg/terraforge/internal/pagination"
 )
 
 type MockProjectCache struct {
 	GetProjectFunc        func(ctx context.Context, identifier string) (*models.Project, error)
 	SetProjectFunc        func(ctx context.Context, project *models.Project, ttl time.Duration) error
 	DeleteProjectFunc     func(ctx context.Context, id string) error
-	GetProjectMembersFunc func(ctx context.Context, identifier string) ([]models.ProjectMember, error)
-	SetProjectMembersFunc func(ctx context.Context, project *models.Project, members []models.ProjectMember, ttl time.Duration) error
+	GetProjectMembersFunc func(ctx context.Context, identifier string) (pagination.Page[models.ProjectMember], error)
+	SetProjectMembersFunc func(ctx context.Context, project *models.Project, members pagination.Page[models.ProjectMember], ttl time.Duration) error
 }
 
 func NewMockProjectCache() *MockProjectCache {
@@ -40,16 +41,94 @@ func (m *MockProjectCache) DeleteProject(ctx context.Context, id string) error {
 	return nil
 }
 
-func (m *MockProjectCache) GetProjectMembers(ctx context.Context, identifier string) ([]models.ProjectMember, error) {
+func (m *MockProjectCache) GetProjectMembers(ctx context.Context, identifier string) (pagination.Page[models.ProjectMember], error) {
 	if m.GetProjectMembersFunc != nil {
 		return m.GetProjectMembersFunc(ctx, identifier)
 	}
-	return nil, ErrCacheMiss
+	return pagination.Page[models.ProjectMember]{}, ErrCacheMiss
 }
 
-func (m *MockProjectCache) SetProjectMembers(ctx context.Context, project *models.Project, members []models.ProjectMember, ttl time.Duration) error {
+func (m *MockProjectCache) SetProjectMembers(ctx context.Context, project *models.Project, members pagination.Page[models.ProjectMember], ttl time.Duration) error {
 	if m.SetProjectMembersFunc != nil {
 		return m.SetProjectMembersFunc(ctx, project, members, ttl)
 	}
 	return nil
 }
+
+type MockReleaseCache struct {
+	GetReleasesFunc   func(ctx context.Context, projectId string) (pagination.Page[models.ProjectRelease], error)
+	SetReleasesFunc   func(ctx context.Context, projectId string, releases pagination.Page[models.ProjectRelease], ttl time.Duration) error
+	GetReleaseFunc    func(ctx context.Context, releaseId string) (*models.ProjectRelease, error)
+	SetReleaseFunc    func(ctx context.Context, release *models.ProjectRelease, ttl time.Duration) error
+	DeleteReleaseFunc func(ctx context.Context, projectId string, releaseId string) error
+}
+
+func NewMockReleaseCache() *MockReleaseCache {
+	return &MockReleaseCache{}
+}
+
+func (m *MockReleaseCache) GetReleases(ctx context.Context, projectId string) (pagination.Page[models.ProjectRelease], error) {
+	if m.GetReleasesFunc != nil {
+		return m.GetReleasesFunc(ctx, projectId)
+	}
+	return pagination.Page[models.ProjectRelease]{}, ErrCacheMiss
+}
+
+func (m *MockReleaseCache) SetReleases(ctx context.Context, projectId string, releases pagination.Page[models.ProjectRelease], ttl time.Duration) error {
+	if m.SetReleasesFunc != nil {
+		return m.SetReleasesFunc(ctx, projectId, releases, ttl)
+	}
+	return nil
+}
+
+func (m *MockReleaseCache) GetRelease(ctx context.Context, releaseId string) (*models.ProjectRelease, error) {
+	if m.GetReleaseFunc != nil {
+		return m.GetReleaseFunc(ctx, releaseId)
+	}
+	return nil, ErrCacheMiss
+}
+
+func (m *MockReleaseCache) SetRelease(ctx context.Context, release *models.ProjectRelease, ttl time.Duration) error {
+	if m.SetReleaseFunc != nil {
+		return m.SetReleaseFunc(ctx, release, ttl)
+	}
+	return nil
+}
+
+func (m *MockReleaseCache) DeleteRelease(ctx context.Context, projectId string, releaseId string) error {
+	if m.DeleteReleaseFunc != nil {
+		return m.DeleteReleaseFunc(ctx, projectId, releaseId)
+	}
+	return nil
+}
+
+type MockLoaderVersionCache struct {
+	GetLoaderVersionsFunc    func(ctx context.Context) ([]models.LoaderVersion, error)
+	SetLoaderVersionsFunc    func(ctx context.Context, loaderVersions []models.LoaderVersion, ttl time.Duration) error
+	DeleteLoaderVersionsFunc func(ctx context.Context) error
+}
+
+func NewMockLoaderVersionCache() *MockLoaderVersionCache {
+	return &MockLoaderVersionCache{}
+}
+
+func (m *MockLoaderVersionCache) GetLoaderVersions(ctx context.Context) ([]models.LoaderVersion, error) {
+	if m.GetLoaderVersionsFunc != nil {
+		return m.GetLoaderVersionsFunc(ctx)
+	}
+	return nil, ErrCacheMiss
+}
+
+func (m *MockLoaderVersionCache) SetLoaderVersions(ctx context.Context, loaderVersions []models.LoaderVersion, ttl time.Duration) error {
+	if m.SetLoaderVersionsFunc != nil {
+		return m.SetLoaderVersionsFunc(ctx, loaderVersions, ttl)
+	}
+	return nil
+}
+
+func (m *MockLoaderVersionCache) DeleteLoaderVersions(ctx context.Context) error {
+	if m.DeleteLoaderVersionsFunc != nil {
+		return m.DeleteLoaderVersionsFunc(ctx)
+	}
+	return nil
+}
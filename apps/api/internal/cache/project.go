@@ -2,7 +2,6 @@ package cache
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
@@ -10,36 +9,48 @@ import (
 	"github.com/redis/go-redis/v9"
 	redis_client_wrapper "github.com/terraforge-gg/terraforge/internal/lib/redis"
 	"github.com/terraforge-gg/terraforge/internal/models"
+	"github.com/terraforge-gg/terraforge/internal/pagination"
 )
 
 type ProjectCache interface {
 	GetProject(ctx context.Context, identifier string) (*models.Project, error)
 	SetProject(ctx context.Context, project *models.Project, ttl time.Duration) error
 	DeleteProject(ctx context.Context, id string) error
-	GetProjectMembers(ctx context.Context, identifier string) ([]models.ProjectMember, error)
-	SetProjectMembers(ctx context.Context, project *models.Project, projectMembers []models.ProjectMember, ttl time.Duration) error
+	// Only the first page of a project's members is cached
+	GetProjectMembers(ctx context.Context, identifier string) (pagination.Page[models.ProjectMember], error)
+	SetProjectMembers(ctx context.Context, project *models.Project, projectMembers pagination.Page[models.ProjectMember], ttl time.Duration) error
 }
 
 type cache struct {
 	Wrapper *redis_client_wrapper.RedisClient
+	// Optional in-process tier in front of Redis, nil when disabled.
+	local *LocalCache
 }
 
-func NewProjectCache(redisWrapper *redis_client_wrapper.RedisClient) ProjectCache {
+func NewProjectCache(redisWrapper *redis_client_wrapper.RedisClient, local *LocalCache) ProjectCache {
 	return &cache{
 		Wrapper: redisWrapper,
+		local:   local,
 	}
 }
 
 func (c *cache) GetProject(ctx context.Context, identifier string) (*models.Project, error) {
+	project, err := c.getProject(ctx, identifier)
+	recordLookup("project", err)
+
+	return project, err
+}
+
+func (c *cache) getProject(ctx context.Context, identifier string) (*models.Project, error) {
 	// First try the identifier directly as an ID key
-	if project, err := c.getProjectById(ctx, identifier); err == nil {
-		return project, nil
+	if project, err := c.getProjectById(ctx, identifier); err == nil || errors.Is(err, ErrCacheStale) {
+		return project, err
 	}
 
 	// Otherwise treat it as a slug — resolve to an ID first
-	id, err := c.Wrapper.Client.Get(ctx, slugKey(identifier)).Result()
+	id, err := c.get(ctx, slugKey(identifier), "")
 
-	if errors.Is(err, redis.Nil) {
+	if isMiss(err) {
 		return nil, ErrCacheMiss
 	}
 
@@ -56,48 +67,61 @@ func (c *cache) GetProject(ctx context.Context, identifier string) (*models.Proj
 	return p, err
 }
 
-func (c *cache) GetProjectMembers(ctx context.Context, identifier string) ([]models.ProjectMember, error) {
+func (c *cache) GetProjectMembers(ctx context.Context, identifier string) (pagination.Page[models.ProjectMember], error) {
+	projectMembers, err := c.getProjectMembers(ctx, identifier)
+	recordLookup("project_members", err)
+
+	return projectMembers, err
+}
+
+func (c *cache) getProjectMembers(ctx context.Context, identifier string) (pagination.Page[models.ProjectMember], error) {
 	// First try the identifier directly as an ID key
-	if project, err := c.getProjectMembersByProjectId(ctx, identifier); err == nil {
-		return project, nil
+	if project, err := c.getProjectMembersByProjectId(ctx, identifier); err == nil || errors.Is(err, ErrCacheStale) {
+		return project, err
 	}
 
 	// Otherwise treat it as a slug — resolve to an ID first
-	id, err := c.Wrapper.Client.Get(ctx, projectMembersSlugKey(identifier)).Result()
-	if errors.Is(err, redis.Nil) {
-		return nil, ErrCacheMiss
+	id, err := c.get(ctx, projectMembersSlugKey(identifier), "")
+	if isMiss(err) {
+		return pagination.Page[models.ProjectMember]{}, ErrCacheMiss
 	}
 	if err != nil {
-		return nil, fmt.Errorf("redis slug lookup: %w", err)
+		return pagination.Page[models.ProjectMember]{}, fmt.Errorf("redis slug lookup: %w", err)
 	}
 
 	pm, err := c.getProjectMembersByProjectId(ctx, id)
 
 	if errors.Is(err, ErrCacheMiss) {
-		return nil, ErrCacheMiss
+		return pagination.Page[models.ProjectMember]{}, ErrCacheMiss
 	}
 
 	return pm, err
 }
 
 func (c *cache) SetProject(ctx context.Context, project *models.Project, ttl time.Duration) error {
-	b, err := json.Marshal(project)
+	b, hardTTL, err := newEntry(project, ttl)
 	if err != nil {
 		return fmt.Errorf("marshal project: %w", err)
 	}
 
 	pipe := c.Wrapper.Client.Pipeline()
 	// Single source of truth — project data lives under its ID
-	pipe.Set(ctx, projectKey(project.Id), b, ttl)
+	pipe.Set(ctx, projectKey(project.Id), b, hardTTL)
 	// Slug is just a pointer to the ID
-	pipe.Set(ctx, slugKey(project.Slug), project.Id, ttl)
-	_, err = pipe.Exec(ctx)
-	return err
+	pipe.Set(ctx, slugKey(project.Slug), project.Id, hardTTL)
+	registerKeys(ctx, pipe, project.Id, projectKey(project.Id), slugKey(project.Slug))
+	if _, err = pipe.Exec(ctx); err != nil {
+		return err
+	}
+
+	c.setLocal(project.Id, projectKey(project.Id), string(b), slugKey(project.Slug), project.Id)
+
+	return nil
 }
 
 func (c *cache) getProjectById(ctx context.Context, id string) (*models.Project, error) {
-	val, err := c.Wrapper.Client.Get(ctx, projectKey(id)).Result()
-	if errors.Is(err, redis.Nil) {
+	val, err := c.get(ctx, projectKey(id), id)
+	if isMiss(err) {
 		return nil, ErrCacheMiss
 	}
 	if err != nil {
@@ -105,51 +129,149 @@ func (c *cache) getProjectById(ctx context.Context, id string) (*models.Project,
 	}
 
 	var project models.Project
-	if err := json.Unmarshal([]byte(val), &project); err != nil {
+	if err := readEntry(val, &project); err != nil {
+		if errors.Is(err, ErrCacheStale) {
+			return &project, err
+		}
+		if errors.Is(err, ErrCacheMiss) {
+			return nil, err
+		}
 		return nil, fmt.Errorf("unmarshal project: %w", err)
 	}
 	return &project, nil
 }
 
-func (c *cache) getProjectMembersByProjectId(ctx context.Context, id string) ([]models.ProjectMember, error) {
-	val, err := c.Wrapper.Client.Get(ctx, projectMembersKey(id)).Result()
-	if errors.Is(err, redis.Nil) {
-		return nil, ErrCacheMiss
+func (c *cache) getProjectMembersByProjectId(ctx context.Context, id string) (pagination.Page[models.ProjectMember], error) {
+	val, err := c.get(ctx, projectMembersKey(id), id)
+	if isMiss(err) {
+		return pagination.Page[models.ProjectMember]{}, ErrCacheMiss
 	}
 	if err != nil {
-		return nil, fmt.Errorf("redis get: %w", err)
+		return pagination.Page[models.ProjectMember]{}, fmt.Errorf("redis get: %w", err)
 	}
 
-	var projectMembers []models.ProjectMember
-	if err := json.Unmarshal([]byte(val), &projectMembers); err != nil {
-		return nil, fmt.Errorf("unmarshal project: %w", err)
+	var projectMembers pagination.Page[models.ProjectMember]
+	if err := readEntry(val, &projectMembers); err != nil {
+		if errors.Is(err, ErrCacheStale) {
+			return projectMembers, err
+		}
+		if errors.Is(err, ErrCacheMiss) {
+			return pagination.Page[models.ProjectMember]{}, err
+		}
+		return pagination.Page[models.ProjectMember]{}, fmt.Errorf("unmarshal project: %w", err)
 	}
 	return projectMembers, nil
 }
 
-func (c *cache) SetProjectMembers(ctx context.Context, project *models.Project, projectMembers []models.ProjectMember, ttl time.Duration) error {
-	b, err := json.Marshal(projectMembers)
+func (c *cache) SetProjectMembers(ctx context.Context, project *models.Project, projectMembers pagination.Page[models.ProjectMember], ttl time.Duration) error {
+	b, hardTTL, err := newEntry(projectMembers, ttl)
 	if err != nil {
 		return fmt.Errorf("marshal project members: %w", err)
 	}
 
 	pipe := c.Wrapper.Client.Pipeline()
 	// Single source of truth — project data lives under its ID
-	pipe.Set(ctx, projectMembersKey(project.Id), b, ttl)
+	pipe.Set(ctx, projectMembersKey(project.Id), b, hardTTL)
 	// Slug is just a pointer to the ID
-	pipe.Set(ctx, projectMembersSlugKey(project.Slug), project.Id, ttl)
-	_, err = pipe.Exec(ctx)
-	return err
+	pipe.Set(ctx, projectMembersSlugKey(project.Slug), project.Id, hardTTL)
+	registerKeys(ctx, pipe, project.Id, projectMembersKey(project.Id), projectMembersSlugKey(project.Slug))
+	if _, err = pipe.Exec(ctx); err != nil {
+		return err
+	}
+
+	c.setLocal(project.Id, projectMembersKey(project.Id), string(b), projectMembersSlugKey(project.Slug), project.Id)
+
+	return nil
 }
 
+// Removes every key derived from the project, including slug keys left behind by renames.
+// Must be called after any change to the project or its members, and is what tells the other
+// instances to drop their local entries. Sets only fill the cache, so they never do.
 func (c *cache) DeleteProject(ctx context.Context, id string) error {
-	if err := c.Wrapper.Client.Del(ctx, projectKey(id)).Err(); err != nil {
+	err := deleteProjectKeysScript.Run(ctx, c.Wrapper.Client, []string{
+		projectKeysKey(id),
+		projectKey(id),
+		projectMembersKey(id),
+	}).Err()
+
+	if err != nil {
 		return fmt.Errorf("redis del: %w", err)
 	}
 
+	return c.invalidateLocal(ctx, id)
+}
+
+// Reads key from the local tier before falling back to Redis. Values read from Redis are
+// kept locally under the given project id, or under the value itself for slug pointers.
+func (c *cache) get(ctx context.Context, key string, projectId string) (string, error) {
+	if c.local != nil {
+		if val, ok := c.local.Get(key); ok {
+			return val, nil
+		}
+	}
+
+	val, err := c.Wrapper.Client.Get(ctx, key).Result()
+	if err != nil {
+		return "", err
+	}
+
+	if c.local != nil {
+		if projectId == "" {
+			projectId = val
+		}
+		c.local.Set(key, projectId, val)
+	}
+
+	return val, nil
+}
+
+// Fills the local tier with the key value pairs just written to Redis for a project.
+func (c *cache) setLocal(projectId string, keyValues ...string) {
+	if c.local == nil {
+		return
+	}
+
+	for i := 0; i+1 < len(keyValues); i += 2 {
+		c.local.Set(keyValues[i], projectId, keyValues[i+1])
+	}
+}
+
+func (c *cache) invalidateLocal(ctx context.Context, projectId string) error {
+	if c.local == nil {
+		return nil
+	}
+
+	if err := c.local.publishInvalidation(ctx, c.Wrapper.Client, projectId); err != nil {
+		return fmt.Errorf("redis publish: %w", err)
+	}
+
 	return nil
 }
 
+// Every key written for a project is recorded in a set so it can be removed in one step,
+// even after the slug it was written under has changed.
+const projectKeysTTL = 24 * time.Hour
+
+// Deletes the registered keys, the id keys and the registry itself atomically.
+// Registered keys are not declared in KEYS, so this assumes a single Redis node.
+var deleteProjectKeysScript = redis.NewScript(`
+local keys = redis.call("SMEMBERS", KEYS[1])
+for i = 1, #keys, 500 do
+	redis.call("DEL", unpack(keys, i, math.min(i + 499, #keys)))
+end
+return redis.call("DEL", KEYS[1], KEYS[2], KEYS[3])
+`)
+
+func registerKeys(ctx context.Context, pipe redis.Pipeliner, projectId string, keys ...string) {
+	members := make([]any, len(keys))
+	for i, key := range keys {
+		members[i] = key
+	}
+
+	pipe.SAdd(ctx, projectKeysKey(projectId), members...)
+	pipe.Expire(ctx, projectKeysKey(projectId), projectKeysTTL)
+}
+
 func projectKey(id string) string {
 	return "project:" + id
 }
@@ -162,6 +284,10 @@ func projectMembersSlugKey(slug string) string {
 	return "project:slug:" + slug + ":members"
 }
 
+func projectKeysKey(id string) string {
+	return "project:" + id + ":keys"
+}
+
 func slugKey(slug string) string {
 	return "project:slug:" + slug
 }
@@ -0,0 +1,134 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	redis_client_wrapper "github.com/terraforge-gg/terraforge/internal/lib/redis"
+	"github.com/terraforge-gg/terraforge/internal/models"
+	"github.com/terraforge-gg/terraforge/internal/pagination"
+)
+
+func newTestProjectCache(t *testing.T) (*cache, context.Context) {
+	tr, err := redis_client_wrapper.NewTestRedisWithCleanup(t)
+	require.NoError(t, err)
+
+	return NewProjectCache(tr.Client, nil).(*cache), context.Background()
+}
+
+func assertKeysGone(t *testing.T, c *cache, ctx context.Context, keys ...string) {
+	t.Helper()
+
+	n, err := c.Wrapper.Client.Exists(ctx, keys...).Result()
+	require.NoError(t, err)
+	assert.Zero(t, n, "expected keys to be deleted: %v", keys)
+}
+
+func TestProjectCache_DeleteRemovesAllDerivedKeys(t *testing.T) {
+	c, ctx := newTestProjectCache(t)
+	project := &models.Project{Id: "p1", Slug: "calamity"}
+
+	require.NoError(t, c.SetProject(ctx, project, time.Minute))
+	require.NoError(t, c.SetProjectMembers(ctx, project, pagination.Page[models.ProjectMember]{Items: []models.ProjectMember{{UserId: "u1"}}}, time.Minute))
+
+	require.NoError(t, c.DeleteProject(ctx, project.Id))
+
+	assertKeysGone(t, c, ctx,
+		projectKey(project.Id),
+		slugKey(project.Slug),
+		projectMembersKey(project.Id),
+		projectMembersSlugKey(project.Slug),
+		projectKeysKey(project.Id))
+
+	_, err := c.GetProject(ctx, project.Slug)
+	assert.ErrorIs(t, err, ErrCacheMiss)
+}
+
+func TestProjectCache_DeleteRemovesSlugHistory(t *testing.T) {
+	c, ctx := newTestProjectCache(t)
+	project := &models.Project{Id: "p1", Slug: "old-slug"}
+
+	require.NoError(t, c.SetProject(ctx, project, time.Minute))
+	require.NoError(t, c.SetProjectMembers(ctx, project, pagination.Page[models.ProjectMember]{}, time.Minute))
+
+	// Rename, as UpdateProject does: invalidate then cache under the new slug
+	require.NoError(t, c.DeleteProject(ctx, project.Id))
+	project.Slug = "new-slug"
+	require.NoError(t, c.SetProject(ctx, project, time.Minute))
+
+	_, err := c.GetProject(ctx, "old-slug")
+	assert.ErrorIs(t, err, ErrCacheMiss)
+
+	cached, err := c.GetProject(ctx, "new-slug")
+	require.NoError(t, err)
+	assert.Equal(t, project.Id, cached.Id)
+
+	require.NoError(t, c.DeleteProject(ctx, project.Id))
+
+	assertKeysGone(t, c, ctx,
+		slugKey("old-slug"),
+		slugKey("new-slug"),
+		projectMembersSlugKey("old-slug"),
+		projectKeysKey(project.Id))
+}
+
+func TestProjectCache_DeleteWithoutRegistry(t *testing.T) {
+	c, ctx := newTestProjectCache(t)
+
+	// Entries written before the registry existed are still removed by id
+	require.NoError(t, c.Wrapper.Client.Set(ctx, projectKey("p1"), "{}", time.Minute).Err())
+	require.NoError(t, c.Wrapper.Client.Set(ctx, projectMembersKey("p1"), "[]", time.Minute).Err())
+
+	require.NoError(t, c.DeleteProject(ctx, "p1"))
+
+	assertKeysGone(t, c, ctx, projectKey("p1"), projectMembersKey("p1"))
+}
+
+func TestProjectCache_DeleteDropsLocalEntries(t *testing.T) {
+	tr, err := redis_client_wrapper.NewTestRedisWithCleanup(t)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	c := NewProjectCache(tr.Client, NewLocalCache(10, time.Minute)).(*cache)
+	project := &models.Project{Id: "p1", Slug: "calamity"}
+
+	require.NoError(t, c.SetProject(ctx, project, time.Minute))
+	_, err = c.GetProject(ctx, project.Slug)
+	require.NoError(t, err)
+	assert.Equal(t, 2, c.local.Len())
+
+	require.NoError(t, c.DeleteProject(ctx, project.Id))
+
+	assert.Equal(t, 0, c.local.Len())
+	_, err = c.GetProject(ctx, project.Slug)
+	assert.ErrorIs(t, err, ErrCacheMiss)
+}
+
+func TestProjectCache_SetFillsLocalWithoutInvalidating(t *testing.T) {
+	tr, err := redis_client_wrapper.NewTestRedisWithCleanup(t)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	c := NewProjectCache(tr.Client, NewLocalCache(10, time.Minute)).(*cache)
+	project := &models.Project{Id: "p1", Slug: "calamity"}
+
+	pubsub := tr.Client.Client.Subscribe(ctx, invalidationChannel)
+	defer pubsub.Close()
+	_, err = pubsub.Receive(ctx)
+	require.NoError(t, err)
+
+	require.NoError(t, c.SetProject(ctx, project, time.Minute))
+	require.NoError(t, c.SetProjectMembers(ctx, project, pagination.Page[models.ProjectMember]{}, time.Minute))
+
+	assert.Equal(t, 4, c.local.Len())
+
+	// Other instances keep their local entries, only updates and deletes invalidate them
+	select {
+	case msg := <-pubsub.Channel():
+		t.Fatalf("unexpected invalidation: %s", msg.Payload)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
@@ -0,0 +1,125 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	redis_client_wrapper "github.com/terraforge-gg/terraforge/internal/lib/redis"
+	"github.com/terraforge-gg/terraforge/internal/models"
+	"github.com/terraforge-gg/terraforge/internal/pagination"
+)
+
+type ReleaseCache interface {
+	// Only the first page of a project's releases is cached, the one most requests are for
+	GetReleases(ctx context.Context, projectId string) (pagination.Page[models.ProjectRelease], error)
+	SetReleases(ctx context.Context, projectId string, releases pagination.Page[models.ProjectRelease], ttl time.Duration) error
+	GetRelease(ctx context.Context, releaseId string) (*models.ProjectRelease, error)
+	SetRelease(ctx context.Context, release *models.ProjectRelease, ttl time.Duration) error
+	DeleteRelease(ctx context.Context, projectId string, releaseId string) error
+}
+
+type releaseCache struct {
+	Wrapper *redis_client_wrapper.RedisClient
+}
+
+func NewReleaseCache(redisWrapper *redis_client_wrapper.RedisClient) ReleaseCache {
+	return &releaseCache{
+		Wrapper: redisWrapper,
+	}
+}
+
+func (c *releaseCache) GetReleases(ctx context.Context, projectId string) (pagination.Page[models.ProjectRelease], error) {
+	val, err := c.Wrapper.Client.Get(ctx, projectReleasesKey(projectId)).Result()
+	if isMiss(err) {
+		return pagination.Page[models.ProjectRelease]{}, ErrCacheMiss
+	}
+	if err != nil {
+		return pagination.Page[models.ProjectRelease]{}, fmt.Errorf("redis get: %w", err)
+	}
+
+	var releases pagination.Page[models.ProjectRelease]
+	if err := readEntry(val, &releases); err != nil {
+		if errors.Is(err, ErrCacheStale) {
+			return releases, err
+		}
+		if errors.Is(err, ErrCacheMiss) {
+			return pagination.Page[models.ProjectRelease]{}, err
+		}
+		return pagination.Page[models.ProjectRelease]{}, fmt.Errorf("unmarshal releases: %w", err)
+	}
+	return releases, nil
+}
+
+func (c *releaseCache) SetReleases(ctx context.Context, projectId string, releases pagination.Page[models.ProjectRelease], ttl time.Duration) error {
+	b, hardTTL, err := newEntry(releases, ttl)
+	if err != nil {
+		return fmt.Errorf("marshal releases: %w", err)
+	}
+
+	pipe := c.Wrapper.Client.Pipeline()
+	pipe.Set(ctx, projectReleasesKey(projectId), b, hardTTL)
+	// Registered with the project so deleting the project drops its releases too
+	registerKeys(ctx, pipe, projectId, projectReleasesKey(projectId))
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+func (c *releaseCache) GetRelease(ctx context.Context, releaseId string) (*models.ProjectRelease, error) {
+	val, err := c.Wrapper.Client.Get(ctx, releaseKey(releaseId)).Result()
+	if isMiss(err) {
+		return nil, ErrCacheMiss
+	}
+	if err != nil {
+		return nil, fmt.Errorf("redis get: %w", err)
+	}
+
+	var release models.ProjectRelease
+	if err := readEntry(val, &release); err != nil {
+		if errors.Is(err, ErrCacheStale) {
+			return &release, err
+		}
+		if errors.Is(err, ErrCacheMiss) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("unmarshal release: %w", err)
+	}
+	return &release, nil
+}
+
+func (c *releaseCache) SetRelease(ctx context.Context, release *models.ProjectRelease, ttl time.Duration) error {
+	b, hardTTL, err := newEntry(release, ttl)
+	if err != nil {
+		return fmt.Errorf("marshal release: %w", err)
+	}
+
+	pipe := c.Wrapper.Client.Pipeline()
+	pipe.Set(ctx, releaseKey(release.Id), b, hardTTL)
+	registerKeys(ctx, pipe, release.ProjectId, releaseKey(release.Id))
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// Removes the release and the project's release list, which includes it.
+// releaseId may be empty when a release was created and only the list is out of date.
+func (c *releaseCache) DeleteRelease(ctx context.Context, projectId string, releaseId string) error {
+	keys := []string{projectReleasesKey(projectId)}
+	if releaseId != "" {
+		keys = append(keys, releaseKey(releaseId))
+	}
+
+	if err := c.Wrapper.Client.Del(ctx, keys...).Err(); err != nil {
+		return fmt.Errorf("redis del: %w", err)
+	}
+
+	return nil
+}
+
+func projectReleasesKey(projectId string) string {
+	return "project:" + projectId + ":releases"
+}
+
+func releaseKey(id string) string {
+	return "release:" + id
+}
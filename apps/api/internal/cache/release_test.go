@@ -0,0 +1,40 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/terraforge-gg/terraforge/internal/models"
+	"github.com/terraforge-gg/terraforge/internal/pagination"
+)
+
+func TestReleaseCache_DeleteReleaseRemovesList(t *testing.T) {
+	c, ctx := newTestProjectCache(t)
+	releases := NewReleaseCache(c.Wrapper)
+	release := &models.ProjectRelease{Id: "r1", ProjectId: "p1"}
+
+	require.NoError(t, releases.SetReleases(ctx, "p1", pagination.Page[models.ProjectRelease]{Items: []models.ProjectRelease{*release}}, time.Minute))
+	require.NoError(t, releases.SetRelease(ctx, release, time.Minute))
+
+	require.NoError(t, releases.DeleteRelease(ctx, "p1", "r1"))
+
+	_, err := releases.GetReleases(ctx, "p1")
+	assert.ErrorIs(t, err, ErrCacheMiss)
+	_, err = releases.GetRelease(ctx, "r1")
+	assert.ErrorIs(t, err, ErrCacheMiss)
+}
+
+func TestReleaseCache_DeleteProjectRemovesReleases(t *testing.T) {
+	c, ctx := newTestProjectCache(t)
+	releases := NewReleaseCache(c.Wrapper)
+	release := &models.ProjectRelease{Id: "r1", ProjectId: "p1"}
+
+	require.NoError(t, releases.SetReleases(ctx, "p1", pagination.Page[models.ProjectRelease]{Items: []models.ProjectRelease{*release}}, time.Minute))
+	require.NoError(t, releases.SetRelease(ctx, release, time.Minute))
+
+	require.NoError(t, c.DeleteProject(ctx, "p1"))
+
+	assertKeysGone(t, c, ctx, projectReleasesKey("p1"), releaseKey("r1"))
+}
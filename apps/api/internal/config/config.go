@@ -1,49 +1,419 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net"
+	"net/url"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
-	Env                  string
-	HostPort             string
-	FrontendUrl          string
-	AuthUrl              string
-	DatabaseUrl          string
-	SeedDb               bool
-	CfRegion             string
-	CfAccountId          string
-	CfAccessKeyId        string
-	CfSecretAccessKey    string
-	CdnUrl               string
-	R2Bucket             string
-	MeiliSearchHostUrl   string
-	MeiliSearchMasterKey string
-	RedisUrl             string
-	RedisPassword        string
-}
-
-func Load() *Config {
-	_ = godotenv.Load()
+	Env      string `yaml:"env"`
+	HostPort string `yaml:"hostPort"`
+	// How long in flight requests get to finish on shutdown, and then background work
+	ShutdownTimeout time.Duration `yaml:"shutdownTimeout"`
+	// Serves /metrics apart from the API so it can be kept off the public network
+	MetricsAddress string `yaml:"metricsAddress"`
+	// Where spans are sent, "otlp" or "stdout". Tracing is off when empty
+	TracingExporter string `yaml:"tracingExporter"`
+	// OTLP over HTTP collector, such as http://localhost:4318
+	TracingEndpoint string `yaml:"tracingEndpoint"`
+	// Share of traces kept, between 0 and 1. Traces started by a caller follow its decision
+	TracingSampleRate         float64       `yaml:"tracingSampleRate"`
+	FrontendUrl               string        `yaml:"frontendUrl"`
+	AuthUrl                   string        `yaml:"authUrl"`
+	JwtIssuer                 string        `yaml:"jwtIssuer"`
+	JwtAudience               string        `yaml:"jwtAudience"`
+	JwksRefreshInterval       time.Duration `yaml:"jwksRefreshInterval"`
+	RequireVerifiedEmail      bool          `yaml:"requireVerifiedEmail"`
+	DatabaseUrl               string        `yaml:"databaseUrl"`
+	SeedDb                    bool          `yaml:"seedDb"`
+	CfRegion                  string        `yaml:"cfRegion"`
+	CfAccountId               string        `yaml:"cfAccountId"`
+	CfAccessKeyId             string        `yaml:"cfAccessKeyId"`
+	CfSecretAccessKey         string        `yaml:"cfSecretAccessKey"`
+	CdnUrl                    string        `yaml:"cdnUrl"`
+	R2Bucket                  string        `yaml:"r2Bucket"`
+	MeiliSearchHostUrl        string        `yaml:"meiliSearchHostUrl"`
+	MeiliSearchMasterKey      string        `yaml:"meiliSearchMasterKey"`
+	RedisUrl                  string        `yaml:"redisUrl"`
+	RedisPassword             string        `yaml:"redisPassword"`
+	SearchAnalyticsSampleRate float64       `yaml:"searchAnalyticsSampleRate"`
+	LocalCacheSize            int           `yaml:"localCacheSize"`
+	LocalCacheTTL             time.Duration `yaml:"localCacheTTL"`
+	// Requests per minute by rate limit tier, then by limiter namespace
+	RateLimitTiers map[string]map[string]int `yaml:"rateLimitTiers"`
+	// Requests per minute a single user may make in total, 0 disables the ceiling
+	RateLimitUserCeiling       int      `yaml:"rateLimitUserCeiling"`
+	RateLimitTrustedPartnerIds []string `yaml:"rateLimitTrustedPartnerIds"`
+	// YAML file with the rate limit policies, the built in defaults are used when empty
+	RateLimitPoliciesFile           string        `yaml:"rateLimitPoliciesFile"`
+	RateLimitPoliciesReloadInterval time.Duration `yaml:"rateLimitPoliciesReloadInterval"`
+	// Proxies whose X-Forwarded-For header is believed, as CIDRs or IPs
+	TrustedProxies []string `yaml:"trustedProxies"`
+	// File with more trusted proxy ranges, one per line, such as Cloudflare's
+	TrustedProxiesFile string `yaml:"trustedProxiesFile"`
+}
+
+// Environment variable of each rate limit tier's quotas, such as "general=100,write=30,search=60".
+var rateLimitTierEnv = map[string]string{
+	"anonymous":          "RATE_LIMIT_TIER_ANONYMOUS",
+	"user":               "RATE_LIMIT_TIER_USER",
+	"verified_publisher": "RATE_LIMIT_TIER_VERIFIED_PUBLISHER",
+	"trusted_partner":    "RATE_LIMIT_TIER_TRUSTED_PARTNER",
+}
 
+// Defaults returns the configuration used for anything not set by the config file or environment.
+func Defaults() *Config {
 	return &Config{
-		Env:                  os.Getenv("ENV"),
-		HostPort:             os.Getenv("HOST_PORT"),
-		FrontendUrl:          os.Getenv("FRONTEND_URL"),
-		AuthUrl:              os.Getenv("AUTH_URL"),
-		DatabaseUrl:          os.Getenv("DATABASE_URL"),
-		SeedDb:               os.Getenv("SEED_DB") == "true",
-		CfRegion:             os.Getenv("CF_REGION"),
-		CfAccountId:          os.Getenv("CF_ACCOUNT_ID"),
-		CfAccessKeyId:        os.Getenv("CF_ACCESS_KEY_ID"),
-		CfSecretAccessKey:    os.Getenv("CF_SECRET_ACCESS_KEY"),
-		CdnUrl:               os.Getenv("CDN_URL"),
-		R2Bucket:             os.Getenv("R2_BUCKET"),
-		MeiliSearchHostUrl:   os.Getenv("MEILISEARCH_HOST_URL"),
-		MeiliSearchMasterKey: os.Getenv("MEILISEARCH_MASTER_KEY"),
-		RedisUrl:             os.Getenv("REDIS_URL"),
-		RedisPassword:        os.Getenv("REDIS_PASSWORD"),
+		HostPort:                  "7000",
+		ShutdownTimeout:           10 * time.Second,
+		MetricsAddress:            ":9090",
+		TracingEndpoint:           "http://localhost:4318",
+		TracingSampleRate:         1,
+		JwksRefreshInterval:       time.Minute,
+		RequireVerifiedEmail:      true,
+		SearchAnalyticsSampleRate: 1,
+		LocalCacheTTL:             5 * time.Second,
+		RateLimitTiers: map[string]map[string]int{
+			"anonymous":          {"general": 100, "write": 30, "search": 60},
+			"user":               {"general": 300, "write": 60, "search": 120},
+			"verified_publisher": {"general": 600, "write": 120, "search": 240},
+			"trusted_partner":    {"general": 3000, "write": 600, "search": 1200},
+		},
+		RateLimitUserCeiling:            1000,
+		RateLimitPoliciesReloadInterval: 30 * time.Second,
+	}
+}
+
+// Load builds the configuration from the defaults, then the YAML file at filename when one is
+// given, then environment variables, which may also come from a .env file. Every invalid or
+// missing setting is reported at once.
+func Load(filename string) (*Config, error) {
+	_ = godotenv.Load()
+
+	cfg := Defaults()
+
+	if filename != "" {
+		if err := cfg.readFile(filename); err != nil {
+			return nil, err
+		}
+	}
+
+	env := &envReader{}
+	env.string(&cfg.Env, "ENV")
+	env.string(&cfg.HostPort, "HOST_PORT")
+	env.duration(&cfg.ShutdownTimeout, "SHUTDOWN_TIMEOUT")
+	env.string(&cfg.MetricsAddress, "METRICS_ADDRESS")
+	env.string(&cfg.TracingExporter, "TRACING_EXPORTER")
+	env.string(&cfg.TracingEndpoint, "TRACING_ENDPOINT")
+	env.float(&cfg.TracingSampleRate, "TRACING_SAMPLE_RATE")
+	env.string(&cfg.FrontendUrl, "FRONTEND_URL")
+	env.string(&cfg.AuthUrl, "AUTH_URL")
+	env.string(&cfg.JwtIssuer, "JWT_ISSUER")
+	env.string(&cfg.JwtAudience, "JWT_AUDIENCE")
+	env.duration(&cfg.JwksRefreshInterval, "JWKS_REFRESH_INTERVAL")
+	env.bool(&cfg.RequireVerifiedEmail, "REQUIRE_VERIFIED_EMAIL")
+	env.string(&cfg.DatabaseUrl, "DATABASE_URL")
+	env.bool(&cfg.SeedDb, "SEED_DB")
+	env.string(&cfg.CfRegion, "CF_REGION")
+	env.string(&cfg.CfAccountId, "CF_ACCOUNT_ID")
+	env.string(&cfg.CfAccessKeyId, "CF_ACCESS_KEY_ID")
+	env.string(&cfg.CfSecretAccessKey, "CF_SECRET_ACCESS_KEY")
+	env.string(&cfg.CdnUrl, "CDN_URL")
+	env.string(&cfg.R2Bucket, "R2_BUCKET")
+	env.string(&cfg.MeiliSearchHostUrl, "MEILISEARCH_HOST_URL")
+	env.string(&cfg.MeiliSearchMasterKey, "MEILISEARCH_MASTER_KEY")
+	env.string(&cfg.RedisUrl, "REDIS_URL")
+	env.string(&cfg.RedisPassword, "REDIS_PASSWORD")
+	env.float(&cfg.SearchAnalyticsSampleRate, "SEARCH_ANALYTICS_SAMPLE_RATE")
+	env.int(&cfg.LocalCacheSize, "LOCAL_CACHE_SIZE")
+	env.duration(&cfg.LocalCacheTTL, "LOCAL_CACHE_TTL")
+	for _, tier := range slices.Sorted(maps.Keys(rateLimitTierEnv)) {
+		env.quotas(cfg.RateLimitTiers, tier, rateLimitTierEnv[tier])
+	}
+	env.int(&cfg.RateLimitUserCeiling, "RATE_LIMIT_USER_CEILING")
+	env.list(&cfg.RateLimitTrustedPartnerIds, "RATE_LIMIT_TRUSTED_PARTNER_IDS")
+	env.string(&cfg.RateLimitPoliciesFile, "RATE_LIMIT_POLICIES_FILE")
+	env.duration(&cfg.RateLimitPoliciesReloadInterval, "RATE_LIMIT_POLICIES_RELOAD_INTERVAL")
+	env.list(&cfg.TrustedProxies, "TRUSTED_PROXIES")
+	env.string(&cfg.TrustedProxiesFile, "TRUSTED_PROXIES_FILE")
+
+	// The auth app issues tokens for itself unless told otherwise
+	if cfg.JwtIssuer == "" {
+		cfg.JwtIssuer = cfg.AuthUrl
+	}
+
+	if cfg.JwtAudience == "" {
+		cfg.JwtAudience = cfg.AuthUrl
+	}
+
+	if err := errors.Join(append(env.errs, cfg.Validate())...); err != nil {
+		return nil, fmt.Errorf("invalid configuration:\n%w", err)
+	}
+
+	return cfg, nil
+}
+
+func (cfg *Config) readFile(filename string) error {
+	f, err := os.Open(filename)
+
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	decoder := yaml.NewDecoder(f)
+	// Misspelt keys would otherwise be silently ignored
+	decoder.KnownFields(true)
+
+	if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("parse %s: %w", filename, err)
+	}
+
+	return nil
+}
+
+// Validate reports every missing or out of range setting, named by its environment variable.
+func (cfg *Config) Validate() error {
+	var errs []error
+
+	required := map[string]string{
+		"ENV":                  cfg.Env,
+		"DATABASE_URL":         cfg.DatabaseUrl,
+		"CF_REGION":            cfg.CfRegion,
+		"R2_BUCKET":            cfg.R2Bucket,
+		"REDIS_URL":            cfg.RedisUrl,
+		"FRONTEND_URL":         cfg.FrontendUrl,
+		"AUTH_URL":             cfg.AuthUrl,
+		"CDN_URL":              cfg.CdnUrl,
+		"MEILISEARCH_HOST_URL": cfg.MeiliSearchHostUrl,
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(required)) {
+		if required[key] == "" {
+			errs = append(errs, fmt.Errorf("%s is required", key))
+		}
+	}
+
+	// R2 is only reached through the account's endpoint, dev uses LocalStack
+	if cfg.Env != "" && cfg.Env != "dev" && cfg.Env != "test" && cfg.CfAccountId == "" {
+		errs = append(errs, errors.New("CF_ACCOUNT_ID is required outside of dev"))
+	}
+
+	if port, err := strconv.Atoi(cfg.HostPort); err != nil || port < 1 || port > 65535 {
+		errs = append(errs, fmt.Errorf("HOST_PORT must be a port number, got %q", cfg.HostPort))
+	}
+
+	if _, port, err := net.SplitHostPort(cfg.MetricsAddress); err != nil || port == cfg.HostPort {
+		errs = append(errs, fmt.Errorf("METRICS_ADDRESS must be a host:port apart from HOST_PORT, got %q", cfg.MetricsAddress))
+	}
+
+	switch cfg.TracingExporter {
+	case "", "stdout":
+	case "otlp":
+		if !isHttpUrl(cfg.TracingEndpoint) {
+			errs = append(errs, fmt.Errorf("TRACING_ENDPOINT must be an http or https url, got %q", cfg.TracingEndpoint))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("TRACING_EXPORTER must be otlp, stdout or empty, got %q", cfg.TracingExporter))
+	}
+
+	if cfg.TracingSampleRate < 0 || cfg.TracingSampleRate > 1 {
+		errs = append(errs, fmt.Errorf("TRACING_SAMPLE_RATE must be between 0 and 1, got %v", cfg.TracingSampleRate))
+	}
+
+	urls := map[string]string{
+		"FRONTEND_URL":         cfg.FrontendUrl,
+		"AUTH_URL":             cfg.AuthUrl,
+		"CDN_URL":              cfg.CdnUrl,
+		"MEILISEARCH_HOST_URL": cfg.MeiliSearchHostUrl,
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(urls)) {
+		if urls[key] != "" && !isHttpUrl(urls[key]) {
+			errs = append(errs, fmt.Errorf("%s must be an http or https url, got %q", key, urls[key]))
+		}
+	}
+
+	durations := map[string]time.Duration{
+		"SHUTDOWN_TIMEOUT":                    cfg.ShutdownTimeout,
+		"JWKS_REFRESH_INTERVAL":               cfg.JwksRefreshInterval,
+		"LOCAL_CACHE_TTL":                     cfg.LocalCacheTTL,
+		"RATE_LIMIT_POLICIES_RELOAD_INTERVAL": cfg.RateLimitPoliciesReloadInterval,
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(durations)) {
+		if durations[key] <= 0 {
+			errs = append(errs, fmt.Errorf("%s must be positive, got %s", key, durations[key]))
+		}
+	}
+
+	if cfg.SearchAnalyticsSampleRate < 0 || cfg.SearchAnalyticsSampleRate > 1 {
+		errs = append(errs, fmt.Errorf("SEARCH_ANALYTICS_SAMPLE_RATE must be between 0 and 1, got %v", cfg.SearchAnalyticsSampleRate))
+	}
+
+	if cfg.LocalCacheSize < 0 {
+		errs = append(errs, fmt.Errorf("LOCAL_CACHE_SIZE cannot be negative, got %d", cfg.LocalCacheSize))
+	}
+
+	if cfg.RateLimitUserCeiling < 0 {
+		errs = append(errs, fmt.Errorf("RATE_LIMIT_USER_CEILING cannot be negative, got %d", cfg.RateLimitUserCeiling))
+	}
+
+	for _, tier := range slices.Sorted(maps.Keys(cfg.RateLimitTiers)) {
+		key, ok := rateLimitTierEnv[tier]
+
+		if !ok {
+			errs = append(errs, fmt.Errorf("unknown rate limit tier %q", tier))
+			continue
+		}
+
+		for namespace, requests := range cfg.RateLimitTiers[tier] {
+			if requests < 0 {
+				errs = append(errs, fmt.Errorf("%s: %s cannot be negative, got %d", key, namespace, requests))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func isHttpUrl(value string) bool {
+	u, err := url.Parse(value)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// Overrides settings with the environment variables that are set and not empty, collecting
+// the values that fail to parse.
+type envReader struct {
+	errs []error
+}
+
+func (r *envReader) lookup(key string) (string, bool) {
+	value := strings.TrimSpace(os.Getenv(key))
+	return value, value != ""
+}
+
+func (r *envReader) string(dst *string, key string) {
+	if value, ok := r.lookup(key); ok {
+		*dst = value
 	}
 }
+
+func (r *envReader) list(dst *[]string, key string) {
+	if value, ok := r.lookup(key); ok {
+		*dst = splitList(value)
+	}
+}
+
+func (r *envReader) bool(dst *bool, key string) {
+	if value, ok := r.lookup(key); ok {
+		b, err := strconv.ParseBool(value)
+
+		if err != nil {
+			r.errs = append(r.errs, fmt.Errorf("%s must be true or false, got %q", key, value))
+			return
+		}
+
+		*dst = b
+	}
+}
+
+func (r *envReader) int(dst *int, key string) {
+	if value, ok := r.lookup(key); ok {
+		n, err := strconv.Atoi(value)
+
+		if err != nil {
+			r.errs = append(r.errs, fmt.Errorf("%s must be a whole number, got %q", key, value))
+			return
+		}
+
+		*dst = n
+	}
+}
+
+func (r *envReader) float(dst *float64, key string) {
+	if value, ok := r.lookup(key); ok {
+		f, err := strconv.ParseFloat(value, 64)
+
+		if err != nil {
+			r.errs = append(r.errs, fmt.Errorf("%s must be a number, got %q", key, value))
+			return
+		}
+
+		*dst = f
+	}
+}
+
+func (r *envReader) duration(dst *time.Duration, key string) {
+	if value, ok := r.lookup(key); ok {
+		d, err := time.ParseDuration(value)
+
+		if err != nil {
+			r.errs = append(r.errs, fmt.Errorf("%s must be a duration such as 30s or 5m, got %q", key, value))
+			return
+		}
+
+		*dst = d
+	}
+}
+
+func (r *envReader) quotas(tiers map[string]map[string]int, tier string, key string) {
+	if value, ok := r.lookup(key); ok {
+		quotas, err := parseQuotas(value)
+
+		if err != nil {
+			r.errs = append(r.errs, fmt.Errorf("%s: %w", key, err))
+			return
+		}
+
+		tiers[tier] = quotas
+	}
+}
+
+func splitList(value string) []string {
+	var items []string
+
+	for item := range strings.SplitSeq(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// Parses "general=100,write=30" into requests by namespace.
+func parseQuotas(value string) (map[string]int, error) {
+	quotas := make(map[string]int)
+
+	for _, item := range splitList(value) {
+		namespace, requests, ok := strings.Cut(item, "=")
+
+		if !ok {
+			return nil, fmt.Errorf("expected namespace=requests, got %q", item)
+		}
+
+		n, err := strconv.Atoi(strings.TrimSpace(requests))
+
+		if err != nil {
+			return nil, fmt.Errorf("requests for %s must be a whole number, got %q", strings.TrimSpace(namespace), requests)
+		}
+
+		quotas[strings.TrimSpace(namespace)] = n
+	}
+
+	return quotas, nil
+}
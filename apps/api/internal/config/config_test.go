@@ -0,0 +1,103 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Sets the environment every valid config needs.
+func setRequiredEnv(t *testing.T) {
+	t.Helper()
+
+	t.Setenv("ENV", "test")
+	t.Setenv("DATABASE_URL", "postgres://localhost:5432/terraforge")
+	t.Setenv("CF_REGION", "auto")
+	t.Setenv("R2_BUCKET", "terraforge")
+	t.Setenv("REDIS_URL", "localhost:6379")
+	t.Setenv("FRONTEND_URL", "http://localhost:3000")
+	t.Setenv("AUTH_URL", "http://localhost:5000")
+	t.Setenv("CDN_URL", "http://localhost:4566")
+	t.Setenv("MEILISEARCH_HOST_URL", "http://localhost:7700")
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	setRequiredEnv(t)
+
+	cfg, err := Load("")
+
+	require.NoError(t, err)
+	assert.Equal(t, "7000", cfg.HostPort)
+	assert.Equal(t, 10*time.Second, cfg.ShutdownTimeout)
+	assert.True(t, cfg.RequireVerifiedEmail)
+	assert.Equal(t, "http://localhost:5000", cfg.JwtIssuer)
+	assert.Equal(t, 100, cfg.RateLimitTiers["anonymous"]["general"])
+}
+
+func TestLoad_FileOverriddenByEnv(t *testing.T) {
+	setRequiredEnv(t)
+	filename := filepath.Join(t.TempDir(), "config.yml")
+	require.NoError(t, os.WriteFile(filename, []byte(`
+hostPort: "8000"
+shutdownTimeout: 30s
+localCacheSize: 500
+rateLimitTiers:
+  user:
+    general: 50
+`), 0o644))
+	t.Setenv("HOST_PORT", "9000")
+
+	cfg, err := Load(filename)
+
+	require.NoError(t, err)
+	assert.Equal(t, "9000", cfg.HostPort)
+	assert.Equal(t, 30*time.Second, cfg.ShutdownTimeout)
+	assert.Equal(t, 500, cfg.LocalCacheSize)
+	assert.Equal(t, map[string]int{"general": 50}, cfg.RateLimitTiers["user"])
+	// Tiers the file leaves out keep their defaults
+	assert.Equal(t, 30, cfg.RateLimitTiers["anonymous"]["write"])
+}
+
+func TestLoad_UnknownFileKey(t *testing.T) {
+	setRequiredEnv(t)
+	filename := filepath.Join(t.TempDir(), "config.yml")
+	require.NoError(t, os.WriteFile(filename, []byte("hostport: \"8000\"\n"), 0o644))
+
+	_, err := Load(filename)
+
+	assert.ErrorContains(t, err, "hostport")
+}
+
+func TestLoad_ReportsEveryProblem(t *testing.T) {
+	setRequiredEnv(t)
+	t.Setenv("ENV", "production")
+	t.Setenv("DATABASE_URL", "")
+	t.Setenv("HOST_PORT", "http")
+	t.Setenv("CDN_URL", "cdn.example.com")
+	t.Setenv("SHUTDOWN_TIMEOUT", "10")
+	t.Setenv("SEED_DB", "yes")
+	t.Setenv("SEARCH_ANALYTICS_SAMPLE_RATE", "2")
+	t.Setenv("RATE_LIMIT_TIER_USER", "general")
+	t.Setenv("TRACING_EXPORTER", "jaeger")
+
+	_, err := Load("")
+
+	require.Error(t, err)
+	for _, message := range []string{
+		"DATABASE_URL is required",
+		"CF_ACCOUNT_ID is required outside of dev",
+		`HOST_PORT must be a port number, got "http"`,
+		`CDN_URL must be an http or https url, got "cdn.example.com"`,
+		`SHUTDOWN_TIMEOUT must be a duration such as 30s or 5m, got "10"`,
+		`SEED_DB must be true or false, got "yes"`,
+		"SEARCH_ANALYTICS_SAMPLE_RATE must be between 0 and 1, got 2",
+		`RATE_LIMIT_TIER_USER: expected namespace=requests, got "general"`,
+		`TRACING_EXPORTER must be otlp, stdout or empty, got "jaeger"`,
+	} {
+		assert.ErrorContains(t, err, message)
+	}
+}
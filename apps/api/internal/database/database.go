@@ -4,11 +4,21 @@ import (
 	"context"
 	"database/sql"
 
+	"github.com/XSAM/otelsql"
 	_ "github.com/lib/pq"
+	semconv "go.opentelemetry.io/otel/semconv/v1.39.0"
 )
 
+// Queries, execs and transactions are traced. Spans for reading rows and resetting pooled
+// connections would only add noise.
 func NewPostgresConnection(databaseUrl string) (*sql.DB, error) {
-	db, err := sql.Open("postgres", databaseUrl)
+	db, err := otelsql.Open("postgres", databaseUrl,
+		otelsql.WithAttributes(semconv.DBSystemNamePostgreSQL),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{
+			OmitRows:             true,
+			OmitConnResetSession: true,
+		}),
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,52 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE "search_index" AS ENUM ('projects', 'users');
+
+CREATE TABLE "search_query" (
+    "id" TEXT PRIMARY KEY NOT NULL,
+    "index" search_index NOT NULL,
+    "query" TEXT NOT NULL,
+    "normalizedQuery" TEXT NOT NULL,
+    "filters" JSONB DEFAULT '{}'::jsonb NOT NULL,
+    "resultCount" INTEGER NOT NULL,
+    "limit" INTEGER NOT NULL,
+    "offset" INTEGER NOT NULL,
+    "userId" TEXT REFERENCES "user" ("id") ON DELETE SET NULL,
+    "createdAt" TIMESTAMP DEFAULT now() NOT NULL
+);
+
+CREATE INDEX "search_query_createdAt_idx" ON "search_query"("createdAt" DESC);
+
+CREATE INDEX "search_query_normalizedQuery_idx" ON "search_query"("normalizedQuery");
+
+CREATE TYPE "search_click_type" AS ENUM ('view', 'download');
+
+-- No foreign key on "searchQueryId": queries are written asynchronously and a click
+-- can arrive before its query row has been committed.
+CREATE TABLE "search_click" (
+    "id" TEXT PRIMARY KEY NOT NULL,
+    "searchQueryId" TEXT NOT NULL,
+    "projectId" TEXT NOT NULL REFERENCES "project" ("id") ON DELETE CASCADE,
+    "releaseId" TEXT REFERENCES "project_release" ("id") ON DELETE CASCADE,
+    "type" search_click_type NOT NULL,
+    "createdAt" TIMESTAMP DEFAULT now() NOT NULL
+);
+
+CREATE INDEX "search_click_searchQueryId_idx" ON "search_click"("searchQueryId");
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX "search_click_searchQueryId_idx";
+
+DROP TABLE "search_click";
+
+DROP TYPE "search_click_type";
+
+DROP INDEX "search_query_normalizedQuery_idx";
+
+DROP INDEX "search_query_createdAt_idx";
+
+DROP TABLE "search_query";
+
+DROP TYPE "search_index";
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE "personal_access_token" (
+    "id" TEXT PRIMARY KEY NOT NULL,
+    "userId" TEXT NOT NULL REFERENCES "user" ("id") ON DELETE CASCADE,
+    "name" TEXT NOT NULL,
+    -- SHA-256 of the token, the token itself is only shown once on creation
+    "tokenHash" TEXT NOT NULL,
+    "tokenPrefix" TEXT NOT NULL,
+    "scopes" TEXT[] NOT NULL,
+    -- Empty when the token may act on every project the user can access
+    "projectIds" TEXT[] DEFAULT '{}' NOT NULL,
+    "expiresAt" TIMESTAMP,
+    "lastUsedAt" TIMESTAMP,
+    "revokedAt" TIMESTAMP,
+    "createdAt" TIMESTAMP DEFAULT now() NOT NULL
+);
+
+CREATE UNIQUE INDEX "personal_access_token_tokenHash_unique_idx" ON "personal_access_token"("tokenHash");
+
+CREATE INDEX "personal_access_token_userId_idx" ON "personal_access_token"("userId");
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX "personal_access_token_userId_idx";
+
+DROP INDEX "personal_access_token_tokenHash_unique_idx";
+
+DROP TABLE "personal_access_token";
+-- +goose StatementEnd
@@ -0,0 +1,42 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Plain TEXT rather than an enum so the auth app can write the default without casts
+ALTER TABLE "user"
+    ADD COLUMN "role" TEXT DEFAULT 'user' NOT NULL CHECK ("role" IN ('user', 'moderator', 'admin')),
+    ADD COLUMN "banned" BOOLEAN DEFAULT false NOT NULL,
+    ADD COLUMN "banReason" TEXT,
+    ADD COLUMN "bannedAt" timestamptz;
+
+CREATE INDEX "user_role_idx" ON "user"("role") WHERE "role" <> 'user';
+
+CREATE TABLE "audit_log" (
+    "id" TEXT PRIMARY KEY NOT NULL,
+    -- Kept when the actor is deleted so the history stays intact
+    "actorId" TEXT REFERENCES "user" ("id") ON DELETE SET NULL,
+    "action" TEXT NOT NULL,
+    "targetType" TEXT NOT NULL,
+    "targetId" TEXT NOT NULL,
+    "metadata" JSONB DEFAULT '{}'::jsonb NOT NULL,
+    "createdAt" TIMESTAMP DEFAULT now() NOT NULL
+);
+
+CREATE INDEX "audit_log_createdAt_idx" ON "audit_log"("createdAt" DESC);
+
+CREATE INDEX "audit_log_target_idx" ON "audit_log"("targetType", "targetId");
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX "audit_log_target_idx";
+
+DROP INDEX "audit_log_createdAt_idx";
+
+DROP TABLE "audit_log";
+
+DROP INDEX "user_role_idx";
+
+ALTER TABLE "user"
+    DROP COLUMN "bannedAt",
+    DROP COLUMN "banReason",
+    DROP COLUMN "banned",
+    DROP COLUMN "role";
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Test accounts are flagged by admins and skip the verified email requirement
+ALTER TABLE "user"
+    ADD COLUMN "testAccount" BOOLEAN DEFAULT false NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE "user"
+    DROP COLUMN "testAccount";
+-- +goose StatementEnd
@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Clicks are only inserted for a recorded search, once per search, project and type
+DELETE FROM "search_click" sc
+WHERE NOT EXISTS (SELECT 1 FROM "search_query" sq WHERE sq."id" = sc."searchQueryId");
+
+DELETE FROM "search_click" sc
+USING "search_click" earlier
+WHERE earlier."searchQueryId" = sc."searchQueryId"
+    AND earlier."projectId" = sc."projectId"
+    AND earlier."type" = sc."type"
+    AND (earlier."createdAt", earlier."id") < (sc."createdAt", sc."id");
+
+ALTER TABLE "search_click"
+    ADD CONSTRAINT "search_click_searchQueryId_fkey"
+    FOREIGN KEY ("searchQueryId") REFERENCES "search_query" ("id") ON DELETE CASCADE;
+
+DROP INDEX "search_click_searchQueryId_idx";
+
+CREATE UNIQUE INDEX "search_click_searchQueryId_projectId_type_idx" ON "search_click"("searchQueryId", "projectId", "type");
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX "search_click_searchQueryId_projectId_type_idx";
+
+CREATE INDEX "search_click_searchQueryId_idx" ON "search_click"("searchQueryId");
+
+ALTER TABLE "search_click"
+    DROP CONSTRAINT "search_click_searchQueryId_fkey";
+-- +goose StatementEnd
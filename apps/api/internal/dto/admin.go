@@ -0,0 +1,92 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/terraforge-gg/terraforge/internal/models"
+	"github.com/terraforge-gg/terraforge/internal/pagination"
+)
+
+// AdminUserResponse is the administrative view of a user, including fields hidden from public profiles.
+type AdminUserResponse struct {
+	Id              string     `json:"id"`
+	Name            string     `json:"name"`
+	Username        string     `json:"username"`
+	DisplayUsername *string    `json:"displayUsername"`
+	Email           string     `json:"email"`
+	EmailVerified   bool       `json:"emailVerified"`
+	Image           *string    `json:"image"`
+	Role            string     `json:"role"`
+	Banned          bool       `json:"banned"`
+	BanReason       *string    `json:"banReason"`
+	BannedAt        *time.Time `json:"bannedAt"`
+	TestAccount     bool       `json:"testAccount"`
+	CreatedAt       time.Time  `json:"createdAt"`
+	UpdatedAt       time.Time  `json:"updatedAt"`
+}
+
+type AdminUsersResponse struct {
+	ListResponse[AdminUserResponse]
+	TotalHits int64 `json:"totalHits"`
+}
+
+type BanUserRequest struct {
+	Reason *string `json:"reason" validate:"omitempty,max=500"`
+}
+
+type UpdateTestAccountRequest struct {
+	TestAccount *bool `json:"testAccount" validate:"required"`
+}
+
+type UpdateProjectStatusRequest struct {
+	Status string  `json:"status" validate:"project_status"`
+	Reason *string `json:"reason" validate:"omitempty,max=500"`
+}
+
+type AuditLogResponse struct {
+	Id         string         `json:"id"`
+	ActorId    *string        `json:"actorId"`
+	Action     string         `json:"action"`
+	TargetType string         `json:"targetType"`
+	TargetId   string         `json:"targetId"`
+	Metadata   map[string]any `json:"metadata"`
+	CreatedAt  time.Time      `json:"createdAt"`
+}
+
+func MapToAdminUserResponse(u models.User) AdminUserResponse {
+	return AdminUserResponse{
+		Id:              u.Id,
+		Name:            u.Name,
+		Username:        u.Username,
+		DisplayUsername: u.DisplayUsername,
+		Email:           u.Email,
+		EmailVerified:   u.EmailVerified,
+		Image:           u.Image,
+		Role:            string(u.Role),
+		Banned:          u.Banned,
+		BanReason:       u.BanReason,
+		BannedAt:        u.BannedAt,
+		TestAccount:     u.TestAccount,
+		CreatedAt:       u.CreatedAt,
+		UpdatedAt:       u.UpdatedAt,
+	}
+}
+
+func MapToAdminUsersResponse(users pagination.Page[models.User], totalHits int64, limit int64) AdminUsersResponse {
+	return AdminUsersResponse{
+		ListResponse: MapToListResponse(users, limit, MapToAdminUserResponse),
+		TotalHits:    totalHits,
+	}
+}
+
+func MapToAuditLogResponse(a models.AuditLog) AuditLogResponse {
+	return AuditLogResponse{
+		Id:         a.Id,
+		ActorId:    a.ActorId,
+		Action:     string(a.Action),
+		TargetType: string(a.TargetType),
+		TargetId:   a.TargetId,
+		Metadata:   a.Metadata,
+		CreatedAt:  a.CreatedAt,
+	}
+}
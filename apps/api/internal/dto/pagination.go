@@ -0,0 +1,27 @@
+package dto
+
+import "github.com/terraforge-gg/terraforge/internal/pagination"
+
+// ListResponse is the envelope of every paginated list. NextCursor is passed as the cursor query
+// parameter to get the following page, and Next is the url of that page, which is also sent in
+// the Link header. Both are omitted on the last page.
+type ListResponse[T any] struct {
+	Data       []T    `json:"data"`
+	Limit      int64  `json:"limit"`
+	NextCursor string `json:"nextCursor,omitempty"`
+	Next       string `json:"next,omitempty"`
+}
+
+func MapToListResponse[T any, R any](page pagination.Page[T], limit int64, mapItem func(T) R) ListResponse[R] {
+	data := make([]R, len(page.Items))
+
+	for i, item := range page.Items {
+		data[i] = mapItem(item)
+	}
+
+	return ListResponse[R]{
+		Data:       data,
+		Limit:      limit,
+		NextCursor: page.NextCursor,
+	}
+}
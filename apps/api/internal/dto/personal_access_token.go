@@ -0,0 +1,54 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/terraforge-gg/terraforge/internal/models"
+)
+
+type CreatePersonalAccessTokenRequest struct {
+	Name          string   `json:"name" validate:"required,min=3,max=100"`
+	Scopes        []string `json:"scopes" validate:"required,min=1,dive,token_scope"`
+	Projects      []string `json:"projects" validate:"omitempty,max=50"`
+	ExpiresInDays *int     `json:"expiresInDays" validate:"omitempty,min=1,max=365"`
+}
+
+type PersonalAccessTokenResponse struct {
+	Id          string     `json:"id"`
+	Name        string     `json:"name"`
+	TokenPrefix string     `json:"tokenPrefix"`
+	Scopes      []string   `json:"scopes"`
+	ProjectIds  []string   `json:"projectIds"`
+	ExpiresAt   *time.Time `json:"expiresAt"`
+	LastUsedAt  *time.Time `json:"lastUsedAt"`
+	CreatedAt   time.Time  `json:"createdAt"`
+}
+
+type CreatePersonalAccessTokenResponse struct {
+	PersonalAccessTokenResponse
+	// Only returned once, when the token is created
+	Token string `json:"token"`
+}
+
+func PersonalAccessTokenToResponse(t models.PersonalAccessToken) PersonalAccessTokenResponse {
+	scopes := make([]string, len(t.Scopes))
+	for i, scope := range t.Scopes {
+		scopes[i] = string(scope)
+	}
+
+	projectIds := t.ProjectIds
+	if projectIds == nil {
+		projectIds = []string{}
+	}
+
+	return PersonalAccessTokenResponse{
+		Id:          t.Id,
+		Name:        t.Name,
+		TokenPrefix: t.TokenPrefix,
+		Scopes:      scopes,
+		ProjectIds:  projectIds,
+		ExpiresAt:   t.ExpiresAt,
+		LastUsedAt:  t.LastUsedAt,
+		CreatedAt:   t.CreatedAt,
+	}
+}
@@ -1,8 +1,89 @@
 package dto
 
+import (
+	"encoding/json"
+	"maps"
+
+	custom_errors "github.com/terraforge-gg/terraforge/internal/errors"
+)
+
+// Problem types clients can branch on. Every error has one, built from its code.
+const (
+	ProblemTypeEmailNotVerified = custom_errors.TypeBaseUrl + "email-not-verified"
+)
+
 type ProblemDetails struct {
+	Type   string            `json:"type,omitempty"`
 	Title  string            `json:"title"`
 	Status int               `json:"status"`
 	Detail string            `json:"detail,omitempty"`
+	Code   string            `json:"code,omitempty"`
 	Errors map[string]string `json:"errors,omitempty"`
+	// Extension members, written alongside the standard members as RFC 9457 describes
+	Extensions map[string]any `json:"-"`
+}
+
+func ErrorToProblemDetails(err *custom_errors.Error) ProblemDetails {
+	return ProblemDetails{
+		Type:       err.Type(),
+		Title:      err.Title(),
+		Status:     err.Status,
+		Detail:     err.Detail,
+		Code:       err.Code,
+		Extensions: err.Extensions,
+	}
+}
+
+func (p ProblemDetails) MarshalJSON() ([]byte, error) {
+	members := make(map[string]any, len(p.Extensions)+6)
+	// Copied first so an extension can never replace a standard member
+	maps.Copy(members, p.Extensions)
+
+	members["title"] = p.Title
+	members["status"] = p.Status
+
+	if p.Type != "" {
+		members["type"] = p.Type
+	}
+
+	if p.Detail != "" {
+		members["detail"] = p.Detail
+	}
+
+	if p.Code != "" {
+		members["code"] = p.Code
+	}
+
+	if len(p.Errors) > 0 {
+		members["errors"] = p.Errors
+	}
+
+	return json.Marshal(members)
+}
+
+// Members that are not standard ones are collected into Extensions, so clients see them too.
+func (p *ProblemDetails) UnmarshalJSON(b []byte) error {
+	type standard ProblemDetails
+
+	if err := json.Unmarshal(b, (*standard)(p)); err != nil {
+		return err
+	}
+
+	var members map[string]any
+
+	if err := json.Unmarshal(b, &members); err != nil {
+		return err
+	}
+
+	for _, name := range []string{"type", "title", "status", "detail", "code", "errors"} {
+		delete(members, name)
+	}
+
+	p.Extensions = nil
+
+	if len(members) > 0 {
+		p.Extensions = members
+	}
+
+	return nil
 }
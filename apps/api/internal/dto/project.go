@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/terraforge-gg/terraforge/internal/models"
+	"github.com/terraforge-gg/terraforge/internal/pagination"
 )
 
 type CreateProjectRequest struct {
@@ -61,6 +62,12 @@ func ProjectMemberToProjectMemberResponse(member models.ProjectMember) ProjectMe
 	}
 }
 
+type AddProjectMemberRequest struct {
+	// Id or username of the user to add
+	User string `json:"user" validate:"required"`
+	Role string `json:"role" validate:"project_member_role"`
+}
+
 type UpdateProjectRequest struct {
 	Name        *string `json:"name,omitempty" validate:"omitempty,min=3,max=100"`
 	Slug        *string `json:"slug,omitempty" validate:"omitempty,url_slug,min=3,max=100"`
@@ -69,24 +76,25 @@ type UpdateProjectRequest struct {
 	IconUrl     *string `json:"iconUrl,omitempty" validate:"omitempty,url"`
 }
 
+// Search results are ranked by relevance, so their cursor holds the offset of the next page.
+// Offset can also be passed directly to jump to a page.
 type ProjectSearchResponse struct {
-	Data      []ProjectResponse `json:"data"`
-	TotalHits int64             `json:"totalHits"`
-	Limit     int64             `json:"limit"`
-	Offset    int64             `json:"offset"`
+	ListResponse[ProjectResponse]
+	TotalHits int64  `json:"totalHits"`
+	Offset    int64  `json:"offset"`
+	SearchId  string `json:"searchId,omitempty"`
 }
 
 func ProjectToProjectSearchResponse(projects []models.Project, totalHits int64, limit int64, offset int64) ProjectSearchResponse {
-	data := make([]ProjectResponse, len(projects))
-
-	for i, p := range projects {
-		data[i] = ProjectToProjectResponse(p)
+	response := ProjectSearchResponse{
+		ListResponse: MapToListResponse(pagination.Page[models.Project]{Items: projects}, limit, ProjectToProjectResponse),
+		TotalHits:    totalHits,
+		Offset:       offset,
 	}
 
-	return ProjectSearchResponse{
-		Data:      data,
-		TotalHits: totalHits,
-		Limit:     limit,
-		Offset:    offset,
+	if next := offset + int64(len(projects)); len(projects) > 0 && next < totalHits {
+		response.NextCursor = pagination.Encode(pagination.OffsetKey{Offset: next})
 	}
+
+	return response
 }
@@ -21,6 +21,15 @@ type CreateProjectReleaseRequest struct {
 	Dependencies    []CreateProjectReleaseRequestDependency `json:"dependencies"`
 }
 
+// Metadata part of a multipart publish request. The file itself is sent as the next part.
+type PublishProjectReleaseRequest struct {
+	Name            string                                  `json:"name" validate:"required,min=3,max=100"`
+	VersionNumber   string                                  `json:"versionNumber" validate:"required,semver"`
+	Changelog       *string                                 `json:"changelog"`
+	LoaderVersionId string                                  `json:"loaderVersionId" validate:"required"`
+	Dependencies    []CreateProjectReleaseRequestDependency `json:"dependencies"`
+}
+
 type ProjectReleaseDependencyResponse struct {
 	Id               string    `json:"id"`
 	ReleaseId        string    `json:"-"`
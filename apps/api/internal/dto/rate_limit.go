@@ -0,0 +1,37 @@
+package dto
+
+import "time"
+
+type RateLimitResponse struct {
+	Namespace string `json:"namespace"`
+	Limit     int    `json:"limit"`
+	Remaining int64  `json:"remaining"`
+	// Length of the sliding window
+	WindowSeconds int64 `json:"windowSeconds"`
+	// Seconds until the oldest counted request leaves the window
+	ResetSeconds int64 `json:"resetSeconds"`
+}
+
+type RateLimitPolicyResponse struct {
+	Namespace string   `json:"namespace"`
+	Route     string   `json:"route,omitempty"`
+	Methods   []string `json:"methods,omitempty"`
+	Cost      int      `json:"cost"`
+}
+
+type RateLimitsResponse struct {
+	Tier        string                    `json:"tier"`
+	UserCeiling *RateLimitResponse        `json:"userCeiling"`
+	Limits      []RateLimitResponse       `json:"limits"`
+	Policies    []RateLimitPolicyResponse `json:"policies"`
+}
+
+func MapToRateLimitResponse(namespace string, limit int, window time.Duration, remaining int64, reset time.Duration) RateLimitResponse {
+	return RateLimitResponse{
+		Namespace:     namespace,
+		Limit:         limit,
+		Remaining:     remaining,
+		WindowSeconds: int64(window.Seconds()),
+		ResetSeconds:  max(int64((reset + time.Second - 1).Seconds()), 0),
+	}
+}
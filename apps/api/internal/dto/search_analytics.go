@@ -0,0 +1,58 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/terraforge-gg/terraforge/internal/models"
+)
+
+type SearchQueryStatResponse struct {
+	Query              string    `json:"query"`
+	Searches           int64     `json:"searches"`
+	AverageResultCount float64   `json:"averageResultCount"`
+	Clicks             int64     `json:"clicks"`
+	ClickThroughRate   float64   `json:"clickThroughRate"`
+	LastSearchedAt     time.Time `json:"lastSearchedAt"`
+}
+
+type SearchAnalyticsReportResponse struct {
+	Since             time.Time                 `json:"since"`
+	TopQueries        []SearchQueryStatResponse `json:"topQueries"`
+	ZeroResultQueries []SearchQueryStatResponse `json:"zeroResultQueries"`
+}
+
+func MapToSearchQueryStatResponse(s models.SearchQueryStat) SearchQueryStatResponse {
+	var clickThroughRate float64
+	if s.Searches > 0 {
+		clickThroughRate = float64(s.Clicks) / float64(s.Searches)
+	}
+
+	return SearchQueryStatResponse{
+		Query:              s.NormalizedQuery,
+		Searches:           s.Searches,
+		AverageResultCount: s.AverageResultCount,
+		Clicks:             s.Clicks,
+		ClickThroughRate:   clickThroughRate,
+		LastSearchedAt:     s.LastSearchedAt,
+	}
+}
+
+func MapToSearchAnalyticsReportResponse(r models.SearchAnalyticsReport) SearchAnalyticsReportResponse {
+	topQueries := make([]SearchQueryStatResponse, len(r.TopQueries))
+
+	for i, s := range r.TopQueries {
+		topQueries[i] = MapToSearchQueryStatResponse(s)
+	}
+
+	zeroResultQueries := make([]SearchQueryStatResponse, len(r.ZeroResultQueries))
+
+	for i, s := range r.ZeroResultQueries {
+		zeroResultQueries[i] = MapToSearchQueryStatResponse(s)
+	}
+
+	return SearchAnalyticsReportResponse{
+		Since:             r.Since,
+		TopQueries:        topQueries,
+		ZeroResultQueries: zeroResultQueries,
+	}
+}
@@ -0,0 +1,53 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/terraforge-gg/terraforge/internal/models"
+	"github.com/terraforge-gg/terraforge/internal/pagination"
+)
+
+type UserResponse struct {
+	Id              string    `json:"id"`
+	Username        string    `json:"username"`
+	DisplayUsername *string   `json:"displayUsername"`
+	Image           *string   `json:"image"`
+	ProjectCount    int64     `json:"projectCount"`
+	TotalDownloads  int64     `json:"totalDownloads"`
+	CreatedAt       time.Time `json:"createdAt"`
+}
+
+func UserToUserResponse(u models.UserWithStats) UserResponse {
+	return UserResponse{
+		Id:              u.User.Id,
+		Username:        u.User.Username,
+		DisplayUsername: u.User.DisplayUsername,
+		Image:           u.User.Image,
+		ProjectCount:    u.Stats.ProjectCount,
+		TotalDownloads:  u.Stats.TotalDownloads,
+		CreatedAt:       u.User.CreatedAt,
+	}
+}
+
+// Search results are ranked by relevance, so their cursor holds the offset of the next page.
+// Offset can also be passed directly to jump to a page.
+type UserSearchResponse struct {
+	ListResponse[UserResponse]
+	TotalHits int64  `json:"totalHits"`
+	Offset    int64  `json:"offset"`
+	SearchId  string `json:"searchId,omitempty"`
+}
+
+func UserToUserSearchResponse(users []models.UserWithStats, totalHits int64, limit int64, offset int64) UserSearchResponse {
+	response := UserSearchResponse{
+		ListResponse: MapToListResponse(pagination.Page[models.UserWithStats]{Items: users}, limit, UserToUserResponse),
+		TotalHits:    totalHits,
+		Offset:       offset,
+	}
+
+	if next := offset + int64(len(users)); len(users) > 0 && next < totalHits {
+		response.NextCursor = pagination.Encode(pagination.OffsetKey{Offset: next})
+	}
+
+	return response
+}
@@ -0,0 +1,16 @@
+package errors
+
+import "net/http"
+
+var (
+	ErrAuthenticationRequired = New("authentication-required", http.StatusUnauthorized, "authentication required", "You must be signed in to perform this action.")
+	ErrInvalidAuthorization   = New("invalid-authorization", http.StatusUnauthorized, "invalid authorization header", "Invalid authorization header format or missing token.")
+	ErrInvalidToken           = New("invalid-token", http.StatusUnauthorized, "invalid token", "Invalid token.")
+	ErrAccountBanned          = New("account-banned", http.StatusForbidden, "account banned", "This account has been banned.")
+	ErrEmailNotVerified       = New("email-not-verified", http.StatusForbidden, "email not verified", "Verify your email address to perform this action.")
+	ErrInsufficientRole       = New("insufficient-role", http.StatusForbidden, "insufficient role", "You are not authorised to perform this action.")
+	ErrTokenMissingScope      = New("token-missing-scope", http.StatusForbidden, "token missing scope", "Token is missing a required scope.")
+	ErrTokenProjectRestricted = New("token-project-restricted", http.StatusForbidden, "token restricted to other projects", "Token does not have access to this project.")
+	ErrTokenNotAllowed        = New("token-not-allowed", http.StatusForbidden, "personal access token not allowed", "Personal access tokens cannot be used for this action.")
+	ErrRateLimited            = New("rate-limited", http.StatusTooManyRequests, "rate limit exceeded", "Rate limit exceeded.")
+)
@@ -0,0 +1,87 @@
+package errors
+
+import (
+	"maps"
+	"net/http"
+	"strings"
+)
+
+// TypeBaseUrl prefixes every error code to form its problem type URI.
+const TypeBaseUrl = "https://terraforge.gg/problems/"
+
+// Error is a domain error that knows how it is reported to clients: the HTTP status, a stable
+// code clients can branch on, and extension members describing the occurrence. The sentinels
+// below are matched with errors.Is, which compares codes so copies made by With still match.
+type Error struct {
+	Code       string
+	Status     int
+	Detail     string
+	Extensions map[string]any
+	message    string
+}
+
+func New(code string, status int, message string, detail string) *Error {
+	return &Error{
+		Code:    code,
+		Status:  status,
+		Detail:  detail,
+		message: message,
+	}
+}
+
+// FromStatus is the generic error for a status, used for errors from outside the domain such as
+// unknown routes. Its code is the status text, for example "method-not-allowed".
+func FromStatus(status int) *Error {
+	text := http.StatusText(status)
+
+	if text == "" {
+		status = http.StatusInternalServerError
+		text = http.StatusText(status)
+	}
+
+	return New(strings.ReplaceAll(strings.ToLower(text), " ", "-"), status, strings.ToLower(text), "")
+}
+
+func (e *Error) Error() string {
+	return e.message
+}
+
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// StatusCode lets Echo and the middleware resolve the response status without the error handler.
+func (e *Error) StatusCode() int {
+	return e.Status
+}
+
+// Type is the problem type URI for the error's code.
+func (e *Error) Type() string {
+	return TypeBaseUrl + e.Code
+}
+
+// Title is the short summary of the problem, the same for every error with the status.
+func (e *Error) Title() string {
+	return http.StatusText(e.Status)
+}
+
+// WithDetail returns a copy explaining this occurrence of the error.
+func (e *Error) WithDetail(detail string) *Error {
+	c := *e
+	c.Detail = detail
+	return &c
+}
+
+// With returns a copy carrying an extension member, such as the id that was not found.
+func (e *Error) With(key string, value any) *Error {
+	c := *e
+	c.Extensions = maps.Clone(e.Extensions)
+
+	if c.Extensions == nil {
+		c.Extensions = make(map[string]any, 1)
+	}
+
+	c.Extensions[key] = value
+	return &c
+}
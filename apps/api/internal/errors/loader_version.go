@@ -1,7 +1,7 @@
 package errors
 
-import "errors"
+import "net/http"
 
 var (
-	ErrLoaderVersionNotFound = errors.New("loader version not found")
+	ErrLoaderVersionNotFound = New("loader-version-not-found", http.StatusNotFound, "loader version not found", "Loader version not found.")
 )
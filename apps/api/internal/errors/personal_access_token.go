@@ -0,0 +1,9 @@
+package errors
+
+import "net/http"
+
+var (
+	ErrPersonalAccessTokenNotFound        = New("token-not-found", http.StatusNotFound, "personal access token not found", "Token not found.")
+	ErrPersonalAccessTokenInvalid         = New("invalid-token", http.StatusUnauthorized, "invalid personal access token", "Invalid token.")
+	ErrPersonalAccessTokenProjectNotFound = New("token-project-not-found", http.StatusBadRequest, "personal access token project not found", "One or more projects were not found.")
+)
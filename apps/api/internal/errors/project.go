@@ -1,10 +1,12 @@
 package errors
 
-import "errors"
+import "net/http"
 
 var (
-	ErrProjectUnauthorisedAction = errors.New("unauthorized project action")
-	ErrProjectNotFound           = errors.New("project not found")
-	ErrProjectSlugUsed           = errors.New("project slug is not available")
-	ErrNoProjectsFound           = errors.New("no projects found")
+	ErrProjectUnauthorisedAction = New("project-action-forbidden", http.StatusForbidden, "unauthorized project action", "You are not authorised to perform this action.")
+	ErrProjectNotFound           = New("project-not-found", http.StatusNotFound, "project not found", "Project not found.")
+	ErrProjectSlugUsed           = New("project-slug-used", http.StatusConflict, "project slug is not available", "Project slug is not available.")
+	ErrNoProjectsFound           = New("no-projects-found", http.StatusNotFound, "no projects found", "No projects found.")
+	ErrProjectNotDeleted         = New("project-not-deleted", http.StatusBadRequest, "project is not deleted", "Project is not deleted.")
+	ErrProjectMemberExists       = New("project-member-exists", http.StatusConflict, "user is already a project member", "The user is already a member of this project.")
 )
@@ -1,15 +1,17 @@
 package errors
 
-import "errors"
+import "net/http"
 
 var (
-	ErrProjectReleaseNotFound                         = errors.New("project release not found")
-	ErrProjectReleaseDependencyNotFound               = errors.New("project release dependency not found")
-	ErrCircularProjectReleaseDependency               = errors.New("circular project release dependency")
-	ErrDuplicateProjectReleaseDependency              = errors.New("duplicate project release dependencies")
-	ErrProjectReleaseNumberAlreadyExists              = errors.New("project release number already exists")
-	ErrProjectReleaseDependencyMinVersionDoesNotExist = errors.New("project release min version number does not exist")
-	ErrProjectReleaseInvalidFileSize                  = errors.New("invalid project release file size")
-	ErrProjectReleaseFailedToParseFileUrl             = errors.New("file to parse uploaded file url")
-	ErrProjectReleaseUploadedFileNotFound             = errors.New("uploaded file not found")
+	ErrProjectReleaseNotFound                         = New("release-not-found", http.StatusNotFound, "project release not found", "Project release not found.")
+	ErrProjectReleaseDependencyNotFound               = New("release-dependency-not-found", http.StatusNotFound, "project release dependency not found", "Dependency project not found.")
+	ErrCircularProjectReleaseDependency               = New("release-dependency-circular", http.StatusBadRequest, "circular project release dependency", "A project cannot have a dependency on itself.")
+	ErrDuplicateProjectReleaseDependency              = New("release-dependency-duplicate", http.StatusBadRequest, "duplicate project release dependencies", "Duplicate dependencies cannot be added.")
+	ErrProjectReleaseNumberAlreadyExists              = New("release-version-exists", http.StatusBadRequest, "project release number already exists", "A release with this version number already exists.")
+	ErrProjectReleaseDependencyMinVersionDoesNotExist = New("release-dependency-min-version-not-found", http.StatusBadRequest, "project release min version number does not exist", "A dependency project version with a supplied min version was not found.")
+	ErrProjectReleaseInvalidFileSize                  = New("release-file-size-invalid", http.StatusBadRequest, "invalid project release file size", "Invalid project release file size.")
+	ErrProjectReleaseFileTooLarge                     = New("release-file-too-large", http.StatusRequestEntityTooLarge, "project release file too large", "The release file is too large.")
+	ErrProjectReleaseFailedToParseFileUrl             = New("release-file-url-invalid", http.StatusBadRequest, "file to parse uploaded file url", "The file url could not be parsed.")
+	ErrProjectReleaseUploadedFileNotFound             = New("release-file-not-found", http.StatusBadRequest, "uploaded file not found", "The uploaded file was not found.")
+	ErrProjectReleaseInvalidFile                      = New("release-file-invalid", http.StatusBadRequest, "uploaded file is not a valid tmod file", "The uploaded file is not a valid .tmod file.")
 )
@@ -0,0 +1,11 @@
+package errors
+
+import "net/http"
+
+var (
+	ErrInvalidRequest   = New("invalid-request", http.StatusBadRequest, "invalid request", "Invalid request.")
+	ErrValidationFailed = New("validation-failed", http.StatusBadRequest, "validation failed", "One or more fields failed validation.")
+	ErrInvalidCursor    = New("invalid-cursor", http.StatusBadRequest, "invalid cursor", "The cursor is invalid, use the next link of a previous page.")
+	ErrRequestTooLarge  = New("request-too-large", http.StatusRequestEntityTooLarge, "request too large", "The request body is too large.")
+	ErrInternal         = New("internal-server-error", http.StatusInternalServerError, "internal server error", "")
+)
@@ -1,7 +1,10 @@
 package errors
 
-import "errors"
+import "net/http"
 
 var (
-	ErrUserNotFound = errors.New("user not found")
+	ErrUserNotFound       = New("user-not-found", http.StatusNotFound, "user not found", "User not found.")
+	ErrUserCannotBanSelf  = New("user-cannot-ban-self", http.StatusBadRequest, "users cannot ban themselves", "You cannot ban yourself.")
+	ErrUserCannotBeBanned = New("user-cannot-be-banned", http.StatusForbidden, "admins cannot be banned", "Admins cannot be banned.")
+	ErrUserNotBanned      = New("user-not-banned", http.StatusBadRequest, "user is not banned", "User is not banned.")
 )
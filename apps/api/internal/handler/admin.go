@@ -0,0 +1,234 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v5"
+	"github.com/terraforge-gg/terraforge/internal/config"
+	"github.com/terraforge-gg/terraforge/internal/dto"
+	custom_errors "github.com/terraforge-gg/terraforge/internal/errors"
+	"github.com/terraforge-gg/terraforge/internal/models"
+	"github.com/terraforge-gg/terraforge/internal/repository"
+	"github.com/terraforge-gg/terraforge/internal/service"
+	"github.com/terraforge-gg/terraforge/internal/utils"
+)
+
+type AdminHandler struct {
+	cfg                    *config.Config
+	logger                 *slog.Logger
+	searchAnalyticsService service.SearchAnalyticsService
+	adminService           service.AdminService
+}
+
+func NewAdminHandler(cfg *config.Config, logger *slog.Logger, searchAnalyticsService service.SearchAnalyticsService, adminService service.AdminService) *AdminHandler {
+	return &AdminHandler{
+		cfg:                    cfg,
+		logger:                 logger,
+		searchAnalyticsService: searchAnalyticsService,
+		adminService:           adminService,
+	}
+}
+
+func (h *AdminHandler) GetSearchAnalyticsReport(c *echo.Context) error {
+	ctx := c.Request().Context()
+
+	days, err := strconv.ParseInt(c.QueryParam("days"), 10, 64)
+	if err != nil || days < 1 {
+		days = 7
+	}
+
+	const maxDays int64 = 90
+	if days > maxDays {
+		days = maxDays
+	}
+
+	limit, err := strconv.ParseInt(c.QueryParam("limit"), 10, 64)
+	if err != nil || limit < 1 {
+		limit = 50
+	}
+
+	const maxLimit int64 = 500
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+
+	since := time.Now().UTC().Add(-time.Duration(days) * 24 * time.Hour)
+
+	report, err := h.searchAnalyticsService.GetReport(ctx, since, limit)
+
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dto.MapToSearchAnalyticsReportResponse(*report))
+}
+
+func (h *AdminHandler) GetUsers(c *echo.Context) error {
+	ctx := c.Request().Context()
+
+	params := repository.FindUsersParams{
+		Query: c.QueryParam("q"),
+		Page:  getPageParams(c),
+	}
+
+	if role := models.UserRole(c.QueryParam("role")); role != "" {
+		if !role.IsValid() {
+			return custom_errors.ErrInvalidRequest.WithDetail("'" + string(role) + "' is not a valid role.")
+		}
+		params.Role = &role
+	}
+
+	if banned, err := strconv.ParseBool(c.QueryParam("banned")); err == nil {
+		params.Banned = &banned
+	}
+
+	users, totalHits, err := h.adminService.GetUsers(ctx, params)
+
+	if err != nil {
+		return err
+	}
+
+	response := dto.MapToAdminUsersResponse(users, totalHits, params.Page.Limit)
+	response.Next = setNextLink(c, users.NextCursor)
+
+	return c.JSON(http.StatusOK, response)
+}
+
+func (h *AdminHandler) BanUser(c *echo.Context) error {
+	ctx := c.Request().Context()
+	userId := c.Param("userId")
+	actorId, _ := utils.GetSessionUserId(c)
+
+	var req dto.BanUserRequest
+
+	if err := c.Bind(&req); err != nil {
+		return custom_errors.ErrInvalidRequest
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+
+	user, err := h.adminService.BanUser(ctx, service.BanUserParams{
+		ActorId: actorId,
+		UserId:  userId,
+		Reason:  req.Reason,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dto.MapToAdminUserResponse(*user))
+}
+
+func (h *AdminHandler) UnbanUser(c *echo.Context) error {
+	ctx := c.Request().Context()
+	userId := c.Param("userId")
+	actorId, _ := utils.GetSessionUserId(c)
+
+	user, err := h.adminService.UnbanUser(ctx, actorId, userId)
+
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dto.MapToAdminUserResponse(*user))
+}
+
+func (h *AdminHandler) UpdateTestAccount(c *echo.Context) error {
+	ctx := c.Request().Context()
+	userId := c.Param("userId")
+	actorId, _ := utils.GetSessionUserId(c)
+
+	var req dto.UpdateTestAccountRequest
+
+	if err := c.Bind(&req); err != nil {
+		return custom_errors.ErrInvalidRequest
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+
+	user, err := h.adminService.SetTestAccount(ctx, actorId, userId, *req.TestAccount)
+
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dto.MapToAdminUserResponse(*user))
+}
+
+func (h *AdminHandler) UpdateProjectStatus(c *echo.Context) error {
+	ctx := c.Request().Context()
+	projectId := c.Param("projectId")
+	actorId, _ := utils.GetSessionUserId(c)
+
+	var req dto.UpdateProjectStatusRequest
+
+	if err := c.Bind(&req); err != nil {
+		return custom_errors.ErrInvalidRequest
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+
+	project, err := h.adminService.SetProjectStatus(ctx, service.SetProjectStatusParams{
+		ActorId:   actorId,
+		ProjectId: projectId,
+		Status:    models.ProjectStatus(req.Status),
+		Reason:    req.Reason,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dto.ProjectToProjectResponse(*project))
+}
+
+func (h *AdminHandler) RestoreProject(c *echo.Context) error {
+	ctx := c.Request().Context()
+	projectId := c.Param("projectId")
+	actorId, _ := utils.GetSessionUserId(c)
+
+	project, err := h.adminService.RestoreProject(ctx, actorId, projectId)
+
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dto.ProjectToProjectResponse(*project))
+}
+
+func (h *AdminHandler) GetAuditLogs(c *echo.Context) error {
+	ctx := c.Request().Context()
+
+	params := repository.FindAuditLogsParams{
+		Page: getPageParams(c),
+	}
+
+	if targetType := models.AuditTargetType(c.QueryParam("targetType")); targetType != "" {
+		params.TargetType = &targetType
+	}
+
+	if targetId := c.QueryParam("targetId"); targetId != "" {
+		params.TargetId = &targetId
+	}
+
+	auditLogs, err := h.adminService.GetAuditLogs(ctx, params)
+
+	if err != nil {
+		return err
+	}
+
+	response := dto.MapToListResponse(auditLogs, params.Page.Limit, dto.MapToAuditLogResponse)
+	response.Next = setNextLink(c, auditLogs.NextCursor)
+
+	return c.JSON(http.StatusOK, response)
+}
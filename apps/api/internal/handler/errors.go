@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/labstack/echo/v5"
+	"github.com/terraforge-gg/terraforge/internal/dto"
+	custom_errors "github.com/terraforge-gg/terraforge/internal/errors"
+	"github.com/terraforge-gg/terraforge/internal/validation"
+)
+
+// NewErrorHandler renders every error returned by a handler or middleware as problem details.
+// Domain errors carry their own status and code, other errors with a status get the generic
+// problem for it, and anything else is logged and reported as an internal server error.
+func NewErrorHandler(logger *slog.Logger) echo.HTTPErrorHandler {
+	return func(c *echo.Context, err error) {
+		if resp, _ := echo.UnwrapResponse(c.Response()); resp != nil && resp.Committed {
+			return
+		}
+
+		problem := toProblemDetails(c, logger, err)
+
+		var writeErr error
+		if c.Request().Method == http.MethodHead {
+			writeErr = c.NoContent(problem.Status)
+		} else {
+			writeErr = c.JSON(problem.Status, problem)
+		}
+
+		if writeErr != nil {
+			logger.WarnContext(c.Request().Context(), "Failed to write error response", "error", writeErr)
+		}
+	}
+}
+
+func toProblemDetails(c *echo.Context, logger *slog.Logger, err error) dto.ProblemDetails {
+	var domainErr *custom_errors.Error
+	var valErr *validation.ValidationError
+	var maxBytesErr *http.MaxBytesError
+
+	switch {
+	case errors.As(err, &domainErr):
+		return dto.ErrorToProblemDetails(domainErr)
+	case errors.As(err, &valErr):
+		problem := dto.ErrorToProblemDetails(custom_errors.ErrValidationFailed)
+		problem.Errors = valErr.Errors
+		return problem
+	case errors.As(err, &maxBytesErr):
+		return dto.ErrorToProblemDetails(custom_errors.ErrRequestTooLarge.With("limit", maxBytesErr.Limit))
+	}
+
+	status := echo.StatusCode(err)
+
+	if status != 0 && status < http.StatusInternalServerError {
+		return dto.ErrorToProblemDetails(custom_errors.FromStatus(status))
+	}
+
+	logger.ErrorContext(c.Request().Context(), "Unhandled error", "error", err)
+
+	if status == 0 {
+		return dto.ErrorToProblemDetails(custom_errors.ErrInternal)
+	}
+
+	return dto.ErrorToProblemDetails(custom_errors.FromStatus(status))
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	custom_errors "github.com/terraforge-gg/terraforge/internal/errors"
+	"github.com/terraforge-gg/terraforge/internal/validation"
+)
+
+func serveError(t *testing.T, method string, path string, err error) (int, map[string]any) {
+	t.Helper()
+
+	e := echo.New()
+	e.HTTPErrorHandler = NewErrorHandler(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	e.Add(http.MethodGet, "/fail", func(c *echo.Context) error {
+		return err
+	})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+
+	return rec.Code, body
+}
+
+func TestErrorHandler_DomainError(t *testing.T) {
+	status, body := serveError(t, http.MethodGet, "/fail", custom_errors.ErrLoaderVersionNotFound.With("loaderVersionId", "1.4.4"))
+
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.Equal(t, map[string]any{
+		"type":            "https://terraforge.gg/problems/loader-version-not-found",
+		"title":           "Not Found",
+		"status":          float64(http.StatusNotFound),
+		"detail":          "Loader version not found.",
+		"code":            "loader-version-not-found",
+		"loaderVersionId": "1.4.4",
+	}, body)
+}
+
+func TestErrorHandler_WrappedDomainError(t *testing.T) {
+	err := errors.Join(errors.New("transaction rolled back"), custom_errors.ErrProjectUnauthorisedAction)
+	status, body := serveError(t, http.MethodGet, "/fail", err)
+
+	assert.Equal(t, http.StatusForbidden, status)
+	assert.Equal(t, "project-action-forbidden", body["code"])
+	assert.True(t, errors.Is(custom_errors.ErrProjectUnauthorisedAction.WithDetail("Copied."), custom_errors.ErrProjectUnauthorisedAction))
+}
+
+func TestErrorHandler_ValidationError(t *testing.T) {
+	status, body := serveError(t, http.MethodGet, "/fail", &validation.ValidationError{Errors: map[string]string{"name": "Required"}})
+
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, "validation-failed", body["code"])
+	assert.Equal(t, map[string]any{"name": "Required"}, body["errors"])
+}
+
+func TestErrorHandler_EchoError(t *testing.T) {
+	status, body := serveError(t, http.MethodGet, "/missing", nil)
+
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.Equal(t, "not-found", body["code"])
+	assert.Equal(t, "https://terraforge.gg/problems/not-found", body["type"])
+}
+
+func TestErrorHandler_UnknownError(t *testing.T) {
+	status, body := serveError(t, http.MethodGet, "/fail", errors.New("connection refused"))
+
+	assert.Equal(t, http.StatusInternalServerError, status)
+	assert.Equal(t, "internal-server-error", body["code"])
+	// The cause is logged, never sent to the client
+	assert.NotContains(t, body, "detail")
+}
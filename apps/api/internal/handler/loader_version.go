@@ -1,13 +1,11 @@
 package handler
 
 import (
-	"errors"
 	"log/slog"
 	"net/http"
 
 	"github.com/terraforge-gg/terraforge/internal/config"
 	"github.com/terraforge-gg/terraforge/internal/dto"
-	custom_errors "github.com/terraforge-gg/terraforge/internal/errors"
 	"github.com/terraforge-gg/terraforge/internal/service"
 
 	"github.com/labstack/echo/v5"
@@ -33,20 +31,7 @@ func (h *LoaderVersionHandler) GetLoaderVersionById(c *echo.Context) error {
 	LoaderVersion, err := h.loaderVersionService.GetLoaderVersionById(ctx, id)
 
 	if err != nil {
-		switch {
-		case errors.Is(err, custom_errors.ErrLoaderVersionNotFound):
-			return c.JSON(http.StatusNotFound, dto.ProblemDetails{
-				Title:  "Not Found",
-				Status: http.StatusNotFound,
-				Detail: "Loader Version not found.",
-			})
-		default:
-			h.logger.Error("Unhandled get loader version error", "Loader Version ID:", id, "Error:", err)
-			return c.JSON(http.StatusInternalServerError, dto.ProblemDetails{
-				Title:  "Internal Server Error",
-				Status: http.StatusInternalServerError,
-			})
-		}
+		return err
 	}
 
 	LoaderVersionDto := dto.MapToLoaderVersionResponse(*LoaderVersion)
@@ -56,24 +41,15 @@ func (h *LoaderVersionHandler) GetLoaderVersionById(c *echo.Context) error {
 
 func (h *LoaderVersionHandler) GetLoaderVersions(c *echo.Context) error {
 	ctx := c.Request().Context()
-	loaderVersions, err := h.loaderVersionService.GetLoaderVersions(ctx)
+	page := getPageParams(c)
+	loaderVersions, err := h.loaderVersionService.GetLoaderVersions(ctx, page)
 
 	if err != nil {
-		switch {
-		default:
-			h.logger.Error("Unhandled get loader versions error", "Error:", err)
-			return c.JSON(http.StatusInternalServerError, dto.ProblemDetails{
-				Title:  "Internal Server Error",
-				Status: http.StatusInternalServerError,
-			})
-		}
+		return err
 	}
 
-	loaderVersionsDto := make([]dto.LoaderVersionResponse, 0, len(loaderVersions))
+	response := dto.MapToListResponse(loaderVersions, page.Limit, dto.MapToLoaderVersionResponse)
+	response.Next = setNextLink(c, loaderVersions.NextCursor)
 
-	for _, lv := range loaderVersions {
-		loaderVersionsDto = append(loaderVersionsDto, dto.MapToLoaderVersionResponse(lv))
-	}
-
-	return c.JSON(http.StatusOK, loaderVersionsDto)
+	return c.JSON(http.StatusOK, response)
 }
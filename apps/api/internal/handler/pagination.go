@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/labstack/echo/v5"
+	custom_errors "github.com/terraforge-gg/terraforge/internal/errors"
+	"github.com/terraforge-gg/terraforge/internal/pagination"
+)
+
+// Reads the limit and cursor query parameters of a list endpoint. Like the other numeric query
+// parameters, a missing or invalid limit falls back to the default and a large one is capped.
+func getPageParams(c *echo.Context) pagination.Params {
+	limit, err := strconv.ParseInt(c.QueryParam("limit"), 10, 64)
+	if err != nil || limit < 1 {
+		limit = pagination.DefaultLimit
+	}
+
+	if limit > pagination.MaxLimit {
+		limit = pagination.MaxLimit
+	}
+
+	return pagination.Params{
+		Limit:  limit,
+		Cursor: c.QueryParam("cursor"),
+	}
+}
+
+// Reads where a page of search results starts: the offset in its cursor, or the offset query
+// parameter for clients jumping straight to a page.
+func getSearchOffset(c *echo.Context, page pagination.Params) (int64, error) {
+	if !page.IsFirst() {
+		var key pagination.OffsetKey
+
+		if err := pagination.Decode(page.Cursor, &key); err != nil {
+			return 0, err
+		}
+
+		if key.Offset < 0 {
+			return 0, custom_errors.ErrInvalidCursor
+		}
+
+		return key.Offset, nil
+	}
+
+	offset, err := strconv.ParseInt(c.QueryParam("offset"), 10, 64)
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	return offset, nil
+}
+
+// Links the response to the page after nextCursor, the request's url with the cursor replaced,
+// and returns that url for the response body. Does nothing on the last page.
+func setNextLink(c *echo.Context, nextCursor string) string {
+	if nextCursor == "" {
+		return ""
+	}
+
+	next := *c.Request().URL
+	query := next.Query()
+	query.Set("cursor", nextCursor)
+	// The cursor holds the position, an offset would contradict it
+	query.Del("offset")
+	next.RawQuery = query.Encode()
+
+	c.Response().Header().Add("Link", "<"+next.RequestURI()+`>; rel="next"`)
+
+	return next.RequestURI()
+}
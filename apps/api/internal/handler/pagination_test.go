@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	custom_errors "github.com/terraforge-gg/terraforge/internal/errors"
+	"github.com/terraforge-gg/terraforge/internal/pagination"
+)
+
+func newListContext(target string) (*echo.Context, *httptest.ResponseRecorder) {
+	rec := httptest.NewRecorder()
+	return echo.New().NewContext(httptest.NewRequest(http.MethodGet, target, nil), rec), rec
+}
+
+func TestGetPageParams_LimitDefaultsAndCap(t *testing.T) {
+	for query, want := range map[string]int64{
+		"":             pagination.DefaultLimit,
+		"?limit=abc":   pagination.DefaultLimit,
+		"?limit=0":     pagination.DefaultLimit,
+		"?limit=10":    10,
+		"?limit=10000": pagination.MaxLimit,
+	} {
+		c, _ := newListContext("/v1/loader-versions" + query)
+
+		assert.Equal(t, want, getPageParams(c).Limit, query)
+	}
+}
+
+func TestGetSearchOffset_PrefersCursor(t *testing.T) {
+	c, _ := newListContext("/v1/search/projects?offset=5&cursor=" + pagination.Encode(pagination.OffsetKey{Offset: 40}))
+
+	offset, err := getSearchOffset(c, getPageParams(c))
+
+	require.NoError(t, err)
+	assert.EqualValues(t, 40, offset)
+}
+
+func TestGetSearchOffset_RejectsNegativeCursor(t *testing.T) {
+	c, _ := newListContext("/v1/search/projects?cursor=" + pagination.Encode(pagination.OffsetKey{Offset: -1}))
+
+	_, err := getSearchOffset(c, getPageParams(c))
+
+	assert.ErrorIs(t, err, custom_errors.ErrInvalidCursor)
+}
+
+func TestSetNextLink(t *testing.T) {
+	c, rec := newListContext("/v1/search/projects?query=boss&limit=10&offset=20")
+
+	next := setNextLink(c, "abc")
+
+	assert.Equal(t, "/v1/search/projects?cursor=abc&limit=10&query=boss", next)
+	assert.Equal(t, `</v1/search/projects?cursor=abc&limit=10&query=boss>; rel="next"`, rec.Header().Get("Link"))
+}
+
+func TestSetNextLink_LastPage(t *testing.T) {
+	c, rec := newListContext("/v1/loader-versions")
+
+	assert.Empty(t, setNextLink(c, ""))
+	assert.Empty(t, rec.Header().Get("Link"))
+}
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v5"
+	"github.com/terraforge-gg/terraforge/internal/config"
+	"github.com/terraforge-gg/terraforge/internal/dto"
+	custom_errors "github.com/terraforge-gg/terraforge/internal/errors"
+	"github.com/terraforge-gg/terraforge/internal/models"
+	"github.com/terraforge-gg/terraforge/internal/service"
+	"github.com/terraforge-gg/terraforge/internal/utils"
+)
+
+type PersonalAccessTokenHandler struct {
+	cfg          *config.Config
+	logger       *slog.Logger
+	tokenService service.PersonalAccessTokenService
+}
+
+func NewPersonalAccessTokenHandler(cfg *config.Config, logger *slog.Logger, tokenService service.PersonalAccessTokenService) *PersonalAccessTokenHandler {
+	return &PersonalAccessTokenHandler{
+		cfg:          cfg,
+		logger:       logger,
+		tokenService: tokenService,
+	}
+}
+
+func (h *PersonalAccessTokenHandler) CreateToken(c *echo.Context) error {
+	ctx := c.Request().Context()
+	userId, ok := utils.GetSessionUserId(c)
+
+	if !ok {
+		return custom_errors.ErrAuthenticationRequired
+	}
+
+	var req dto.CreatePersonalAccessTokenRequest
+
+	if err := c.Bind(&req); err != nil {
+		return custom_errors.ErrInvalidRequest
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+
+	scopes := make([]models.TokenScope, len(req.Scopes))
+	for i, scope := range req.Scopes {
+		scopes[i] = models.TokenScope(scope)
+	}
+
+	var expiresAt *time.Time
+	if req.ExpiresInDays != nil {
+		t := time.Now().UTC().AddDate(0, 0, *req.ExpiresInDays)
+		expiresAt = &t
+	}
+
+	token, rawToken, err := h.tokenService.CreateToken(ctx, service.CreateTokenParams{
+		UserId:             userId,
+		Name:               req.Name,
+		Scopes:             scopes,
+		ProjectIdentifiers: req.Projects,
+		ExpiresAt:          expiresAt,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, dto.CreatePersonalAccessTokenResponse{
+		PersonalAccessTokenResponse: dto.PersonalAccessTokenToResponse(*token),
+		Token:                       rawToken,
+	})
+}
+
+func (h *PersonalAccessTokenHandler) GetTokens(c *echo.Context) error {
+	ctx := c.Request().Context()
+	userId, ok := utils.GetSessionUserId(c)
+
+	if !ok {
+		return custom_errors.ErrAuthenticationRequired
+	}
+
+	page := getPageParams(c)
+	tokens, err := h.tokenService.GetTokens(ctx, userId, page)
+
+	if err != nil {
+		return err
+	}
+
+	response := dto.MapToListResponse(tokens, page.Limit, dto.PersonalAccessTokenToResponse)
+	response.Next = setNextLink(c, tokens.NextCursor)
+
+	return c.JSON(http.StatusOK, response)
+}
+
+func (h *PersonalAccessTokenHandler) RevokeToken(c *echo.Context) error {
+	ctx := c.Request().Context()
+	tokenId := c.Param("tokenId")
+	userId, ok := utils.GetSessionUserId(c)
+
+	if !ok {
+		return custom_errors.ErrAuthenticationRequired
+	}
+
+	err := h.tokenService.RevokeToken(ctx, userId, tokenId)
+
+	if err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -1,10 +1,8 @@
 package handler
 
 import (
-	"errors"
 	"log/slog"
 	"net/http"
-	"strconv"
 
 	"github.com/labstack/echo/v5"
 	"github.com/terraforge-gg/terraforge/internal/config"
@@ -13,22 +11,23 @@ import (
 	"github.com/terraforge-gg/terraforge/internal/models"
 	"github.com/terraforge-gg/terraforge/internal/service"
 	"github.com/terraforge-gg/terraforge/internal/utils"
-	"github.com/terraforge-gg/terraforge/internal/validation"
 )
 
 type ProjectHandler struct {
-	cfg            *config.Config
-	logger         *slog.Logger
-	projectService service.ProjectService
-	searchService  service.SearchService
+	cfg                    *config.Config
+	logger                 *slog.Logger
+	projectService         service.ProjectService
+	searchService          service.SearchService
+	searchAnalyticsService service.SearchAnalyticsService
 }
 
-func NewProjectHandler(cfg *config.Config, logger *slog.Logger, projectService service.ProjectService, searchService service.SearchService) *ProjectHandler {
+func NewProjectHandler(cfg *config.Config, logger *slog.Logger, projectService service.ProjectService, searchService service.SearchService, searchAnalyticsService service.SearchAnalyticsService) *ProjectHandler {
 	return &ProjectHandler{
-		cfg:            cfg,
-		logger:         logger,
-		projectService: projectService,
-		searchService:  searchService,
+		cfg:                    cfg,
+		logger:                 logger,
+		projectService:         projectService,
+		searchService:          searchService,
+		searchAnalyticsService: searchAnalyticsService,
 	}
 }
 
@@ -43,22 +42,15 @@ func (h *ProjectHandler) GetProjectByIdentifier(c *echo.Context) error {
 	})
 
 	if err != nil {
-		switch {
-		case errors.Is(err, custom_errors.ErrProjectNotFound):
-			return c.JSON(http.StatusNotFound, dto.ProblemDetails{
-				Title:  "Not Found",
-				Status: http.StatusNotFound,
-				Detail: "Project not found.",
-			})
-		default:
-			h.logger.Error("Unhandled get project by identifier error.", "Identifier: ", identifier, "Error:", err)
-			return c.JSON(http.StatusInternalServerError, dto.ProblemDetails{
-				Title:  "Internal Server Error",
-				Status: http.StatusInternalServerError,
-			})
-		}
+		return err
 	}
 
+	h.searchAnalyticsService.RecordClick(ctx, service.RecordClickParams{
+		SearchQueryId: c.QueryParam("searchId"),
+		ProjectId:     project.Id,
+		Type:          models.SearchClickTypeView,
+	})
+
 	return c.JSON(http.StatusOK, dto.ProjectToProjectResponse(*project))
 }
 
@@ -67,35 +59,55 @@ func (h *ProjectHandler) GetProjectMembers(c *echo.Context) error {
 	identifier := c.Param("identifier")
 	userId, _ := utils.GetSessionUserId(c)
 
+	page := getPageParams(c)
+
 	members, err := h.projectService.GetProjectMembers(ctx, service.GetProjectMembersParams{
 		Identifier: identifier,
 		UserId:     userId,
+		Page:       page,
 	})
 
 	if err != nil {
-		switch {
-		case errors.Is(err, custom_errors.ErrProjectNotFound):
-			return c.JSON(http.StatusNotFound, dto.ProblemDetails{
-				Title:  "Not Found",
-				Status: http.StatusNotFound,
-				Detail: "Project not found.",
-			})
-		default:
-			h.logger.Error("Unhandled get project members error", "Identifier: ", identifier, "Error:", err)
-			return c.JSON(http.StatusInternalServerError, dto.ProblemDetails{
-				Title:  "Internal Server Error",
-				Status: http.StatusInternalServerError,
-			})
-		}
+		return err
 	}
 
-	response := make([]dto.ProjectMemberResponse, len(members))
+	response := dto.MapToListResponse(members, page.Limit, dto.ProjectMemberToProjectMemberResponse)
+	response.Next = setNextLink(c, members.NextCursor)
 
-	for i, m := range members {
-		response[i] = dto.ProjectMemberToProjectMemberResponse(m)
+	return c.JSON(http.StatusOK, response)
+}
+
+func (h *ProjectHandler) AddProjectMember(c *echo.Context) error {
+	ctx := c.Request().Context()
+	identifier := c.Param("identifier")
+	userId, ok := utils.GetSessionUserId(c)
+
+	if !ok {
+		return custom_errors.ErrAuthenticationRequired
 	}
 
-	return c.JSON(http.StatusOK, response)
+	var req dto.AddProjectMemberRequest
+
+	if err := c.Bind(&req); err != nil {
+		return custom_errors.ErrInvalidRequest
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+
+	member, err := h.projectService.AddProjectMember(ctx, service.AddProjectMemberParams{
+		Identifier:       identifier,
+		MemberIdentifier: req.User,
+		Role:             models.ProjectMemberRole(req.Role),
+		UserId:           userId,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, dto.ProjectMemberToProjectMemberResponse(*member))
 }
 
 func (h *ProjectHandler) CreateProject(c *echo.Context) error {
@@ -103,38 +115,17 @@ func (h *ProjectHandler) CreateProject(c *echo.Context) error {
 	userId, ok := utils.GetSessionUserId(c)
 
 	if !ok {
-		return c.JSON(http.StatusUnauthorized, dto.ProblemDetails{
-			Title:  "Unauthorized",
-			Status: http.StatusUnauthorized,
-			Detail: "You are not authorised to perform this action",
-		})
+		return custom_errors.ErrAuthenticationRequired
 	}
 
 	var req dto.CreateProjectRequest
 
 	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, dto.ProblemDetails{
-			Title:  "Bad Request",
-			Status: http.StatusBadRequest,
-			Detail: "Invalid request.",
-		})
+		return custom_errors.ErrInvalidRequest
 	}
 
 	if err := c.Validate(&req); err != nil {
-		if valErr, ok := err.(*validation.ValidationError); ok {
-			return c.JSON(http.StatusBadRequest, dto.ProblemDetails{
-				Title:  "Bad Request",
-				Status: http.StatusBadRequest,
-				Detail: "One or more fields failed validation.",
-				Errors: valErr.Errors,
-			})
-		}
-
-		h.logger.Error("Unhandled create project validation error", "Error:", err)
-		return c.JSON(http.StatusInternalServerError, dto.ProblemDetails{
-			Title:  "Internal Server Error",
-			Status: http.StatusInternalServerError,
-		})
+		return err
 	}
 
 	project, err := h.projectService.CreateUserProject(ctx, service.CreateUserProjectParams{
@@ -146,20 +137,7 @@ func (h *ProjectHandler) CreateProject(c *echo.Context) error {
 	})
 
 	if err != nil {
-		switch {
-		case errors.Is(err, custom_errors.ErrProjectSlugUsed):
-			return c.JSON(http.StatusBadRequest, dto.ProblemDetails{
-				Title:  "Bad Request",
-				Status: http.StatusBadRequest,
-				Detail: "Project slug is not available.",
-			})
-		default:
-			h.logger.Error("Unhandled create project error", "Error:", err)
-			return c.JSON(http.StatusInternalServerError, dto.ProblemDetails{
-				Title:  "Internal Server Error",
-				Status: http.StatusInternalServerError,
-			})
-		}
+		return err
 	}
 
 	return c.JSON(http.StatusCreated, dto.ProjectToProjectResponse(*project))
@@ -171,21 +149,13 @@ func (h *ProjectHandler) UpdateProject(c *echo.Context) error {
 	userId, ok := utils.GetSessionUserId(c)
 
 	if !ok {
-		return c.JSON(http.StatusUnauthorized, dto.ProblemDetails{
-			Title:  "Unauthorized",
-			Status: http.StatusUnauthorized,
-			Detail: "You are not authorised to perform this action",
-		})
+		return custom_errors.ErrAuthenticationRequired
 	}
 
 	var req dto.UpdateProjectRequest
 
 	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, dto.ProblemDetails{
-			Title:  "Bad Request",
-			Status: http.StatusBadRequest,
-			Detail: "Invalid request.",
-		})
+		return custom_errors.ErrInvalidRequest
 	}
 
 	if err := c.Validate(&req); err != nil {
@@ -193,11 +163,7 @@ func (h *ProjectHandler) UpdateProject(c *echo.Context) error {
 	}
 
 	if req.Name == nil && req.Slug == nil && req.Summary == nil && req.Description == nil && req.IconUrl == nil {
-		return c.JSON(http.StatusBadRequest, dto.ProblemDetails{
-			Title:  "Bad Request",
-			Status: http.StatusBadRequest,
-			Detail: "Invalid request.",
-		})
+		return custom_errors.ErrInvalidRequest
 	}
 
 	project, err := h.projectService.UpdateProject(ctx, service.UpdateProjectParams{
@@ -211,26 +177,7 @@ func (h *ProjectHandler) UpdateProject(c *echo.Context) error {
 	})
 
 	if err != nil {
-		switch {
-		case errors.Is(err, custom_errors.ErrProjectNotFound):
-			return c.JSON(http.StatusNotFound, dto.ProblemDetails{
-				Title:  "Not Found",
-				Status: http.StatusNotFound,
-				Detail: "Project not found.",
-			})
-		case errors.Is(err, custom_errors.ErrProjectUnauthorisedAction):
-			return c.JSON(http.StatusUnauthorized, dto.ProblemDetails{
-				Title:  "Unauthorised",
-				Status: http.StatusUnauthorized,
-				Detail: "You are not authorised to perform this action",
-			})
-		default:
-			h.logger.Error("Unhandled update project error", "Error:", err)
-			return c.JSON(http.StatusInternalServerError, dto.ProblemDetails{
-				Title:  "Internal Server Error",
-				Status: http.StatusInternalServerError,
-			})
-		}
+		return err
 	}
 
 	return c.JSON(http.StatusOK, dto.ProjectToProjectResponse(*project))
@@ -242,11 +189,7 @@ func (h *ProjectHandler) DeleteProject(c *echo.Context) error {
 	userId, ok := utils.GetSessionUserId(c)
 
 	if !ok {
-		return c.JSON(http.StatusUnauthorized, dto.ProblemDetails{
-			Title:  "Unauthorized",
-			Status: http.StatusUnauthorized,
-			Detail: "You are not authorised to perform this action",
-		})
+		return custom_errors.ErrAuthenticationRequired
 	}
 
 	err := h.projectService.DeleteProject(ctx, service.DeleteProjectParams{
@@ -255,26 +198,7 @@ func (h *ProjectHandler) DeleteProject(c *echo.Context) error {
 	})
 
 	if err != nil {
-		switch {
-		case errors.Is(err, custom_errors.ErrProjectNotFound):
-			return c.JSON(http.StatusNotFound, dto.ProblemDetails{
-				Title:  "Not Found",
-				Status: http.StatusNotFound,
-				Detail: "Project not found.",
-			})
-		case errors.Is(err, custom_errors.ErrProjectUnauthorisedAction):
-			return c.JSON(http.StatusUnauthorized, dto.ProblemDetails{
-				Title:  "Unauthorised",
-				Status: http.StatusUnauthorized,
-				Detail: "You are not authorised to perform this action",
-			})
-		default:
-			h.logger.Error("Unhandled delete project error", "Error:", err)
-			return c.JSON(http.StatusInternalServerError, dto.ProblemDetails{
-				Title:  "Internal Server Error",
-				Status: http.StatusInternalServerError,
-			})
-		}
+		return err
 	}
 
 	return c.NoContent(http.StatusOK)
@@ -283,33 +207,36 @@ func (h *ProjectHandler) DeleteProject(c *echo.Context) error {
 func (h *ProjectHandler) SearchProjects(c *echo.Context) error {
 	ctx := c.Request().Context()
 	query := c.QueryParam("query")
+	page := getPageParams(c)
+	limit := page.Limit
 
-	limit, err := strconv.ParseInt(c.QueryParam("limit"), 10, 64)
-	if err != nil || limit < 1 {
-		limit = 10
-	}
+	offset, err := getSearchOffset(c, page)
 
-	offset, err := strconv.ParseInt(c.QueryParam("offset"), 10, 64)
-	if err != nil || offset < 0 {
-		offset = 0
+	if err != nil {
+		return err
 	}
 
-	const maxLimit int64 = 100
-	if limit > maxLimit {
-		limit = maxLimit
-	}
+	projectType := string(models.ProjectTypeMod)
 
-	projects, totalHits, err := h.searchService.SearchProjects(ctx, query, "mod", limit, offset)
+	projects, totalHits, err := h.searchService.SearchProjects(ctx, query, projectType, limit, offset)
 
 	if err != nil {
-		h.logger.Error("Unhandled search project error", "Error:", err)
-		return c.JSON(http.StatusInternalServerError, dto.ProblemDetails{
-			Title:  "Internal Server Error",
-			Status: http.StatusInternalServerError,
-		})
+		return err
 	}
 
 	response := dto.ProjectToProjectSearchResponse(projects, totalHits, limit, offset)
+	response.Next = setNextLink(c, response.NextCursor)
+
+	userId, _ := utils.GetSessionUserId(c)
+	response.SearchId, _ = h.searchAnalyticsService.RecordSearch(ctx, service.RecordSearchParams{
+		Index:       models.SearchIndexProjects,
+		Query:       query,
+		Filters:     map[string]string{"type": projectType},
+		ResultCount: totalHits,
+		Limit:       limit,
+		Offset:      offset,
+		UserId:      userId,
+	})
 
 	return c.JSON(http.StatusOK, response)
 }
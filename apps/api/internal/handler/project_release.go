@@ -1,30 +1,34 @@
 package handler
 
 import (
+	"encoding/json"
 	"errors"
 	"log/slog"
 	"net/http"
+	"path/filepath"
 
 	"github.com/labstack/echo/v5"
 	"github.com/terraforge-gg/terraforge/internal/config"
 	"github.com/terraforge-gg/terraforge/internal/dto"
 	custom_errors "github.com/terraforge-gg/terraforge/internal/errors"
+	"github.com/terraforge-gg/terraforge/internal/models"
 	"github.com/terraforge-gg/terraforge/internal/service"
 	"github.com/terraforge-gg/terraforge/internal/utils"
-	"github.com/terraforge-gg/terraforge/internal/validation"
 )
 
 type ProjectReleaseHandler struct {
-	cfg                   *config.Config
-	logger                *slog.Logger
-	projectReleaseService service.ProjectReleaseService
+	cfg                    *config.Config
+	logger                 *slog.Logger
+	projectReleaseService  service.ProjectReleaseService
+	searchAnalyticsService service.SearchAnalyticsService
 }
 
-func NewProjectReleaseHandler(cfg *config.Config, logger *slog.Logger, projectReleaseService service.ProjectReleaseService) *ProjectReleaseHandler {
+func NewProjectReleaseHandler(cfg *config.Config, logger *slog.Logger, projectReleaseService service.ProjectReleaseService, searchAnalyticsService service.SearchAnalyticsService) *ProjectReleaseHandler {
 	return &ProjectReleaseHandler{
-		cfg:                   cfg,
-		logger:                logger,
-		projectReleaseService: projectReleaseService,
+		cfg:                    cfg,
+		logger:                 logger,
+		projectReleaseService:  projectReleaseService,
+		searchAnalyticsService: searchAnalyticsService,
 	}
 }
 
@@ -34,38 +38,17 @@ func (h *ProjectReleaseHandler) CreateRelease(c *echo.Context) error {
 	userId, ok := utils.GetSessionUserId(c)
 
 	if !ok {
-		return c.JSON(http.StatusUnauthorized, dto.ProblemDetails{
-			Title:  "Unauthorized",
-			Status: http.StatusUnauthorized,
-			Detail: "You are not authorised to perform this action",
-		})
+		return custom_errors.ErrAuthenticationRequired
 	}
 
 	var req dto.CreateProjectReleaseRequest
 
 	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, dto.ProblemDetails{
-			Title:  "Bad Request",
-			Status: http.StatusBadRequest,
-			Detail: "Invalid request.",
-		})
+		return custom_errors.ErrInvalidRequest
 	}
 
 	if err := c.Validate(&req); err != nil {
-		if valErr, ok := err.(*validation.ValidationError); ok {
-			return c.JSON(http.StatusBadRequest, dto.ProblemDetails{
-				Title:  "Bad Request",
-				Status: http.StatusBadRequest,
-				Detail: "One or more fields failed validation.",
-				Errors: valErr.Errors,
-			})
-		}
-
-		h.logger.Error("Unhandled create project release validation error", "Error:", err)
-		return c.JSON(http.StatusInternalServerError, dto.ProblemDetails{
-			Title:  "Internal Server Error",
-			Status: http.StatusInternalServerError,
-		})
+		return err
 	}
 
 	deps := make([]service.CreateProjectReleaseDependencyParams, len(req.Dependencies))
@@ -88,67 +71,89 @@ func (h *ProjectReleaseHandler) CreateRelease(c *echo.Context) error {
 	})
 
 	if err != nil {
-		switch {
-		case errors.Is(err, custom_errors.ErrProjectNotFound):
-			return c.JSON(http.StatusNotFound, dto.ProblemDetails{
-				Title:  "Not Found",
-				Status: http.StatusNotFound,
-				Detail: "Project not found.",
-			})
-		case errors.Is(err, custom_errors.ErrProjectUnauthorisedAction):
-			return c.JSON(http.StatusUnauthorized, dto.ProblemDetails{
-				Title:  "Unauthorised",
-				Status: http.StatusUnauthorized,
-				Detail: "You are not authorised to perform this action",
-			})
-		case errors.Is(err, custom_errors.ErrLoaderVersionNotFound):
-			return c.JSON(http.StatusNotFound, dto.ProblemDetails{
-				Title:  "Not Found",
-				Status: http.StatusBadRequest,
-				Detail: "loader version '" + req.LoaderVersionId + "' not found.",
-			})
-		case errors.Is(err, custom_errors.ErrProjectReleaseDependencyNotFound):
-			return c.JSON(http.StatusNotFound, dto.ProblemDetails{
-				Title:  "Not Found",
-				Status: http.StatusBadRequest,
-				Detail: "Dependency project not found.",
-			})
-		case errors.Is(err, custom_errors.ErrCircularProjectReleaseDependency):
-			return c.JSON(http.StatusBadRequest, dto.ProblemDetails{
-				Title:  "Bad Request",
-				Status: http.StatusBadRequest,
-				Detail: "A project cannot have a dependency on itself.",
-			})
-		case errors.Is(err, custom_errors.ErrDuplicateProjectReleaseDependency):
-			return c.JSON(http.StatusBadRequest, dto.ProblemDetails{
-				Title:  "Bad Request",
-				Status: http.StatusBadRequest,
-				Detail: "Duplicate dependencies cannot be added.",
-			})
-		case errors.Is(err, custom_errors.ErrProjectReleaseNumberAlreadyExists):
-			return c.JSON(http.StatusBadRequest, dto.ProblemDetails{
-				Title:  "Bad Request",
-				Status: http.StatusBadRequest,
-				Detail: "Project version" + " '" + req.VersionNumber + "' " + "already exists.",
-			})
-		case errors.Is(err, custom_errors.ErrProjectReleaseDependencyMinVersionDoesNotExist):
-			return c.JSON(http.StatusBadRequest, dto.ProblemDetails{
-				Title:  "Bad Request",
-				Status: http.StatusBadRequest,
-				Detail: "A dependency project version with a supplied min version was not found.",
-			})
-		default:
-			return c.JSON(http.StatusInternalServerError, dto.ProblemDetails{
-				Title:  "Internal Server Error",
-				Status: http.StatusInternalServerError,
-			})
-		}
+		return err
 	}
 	versionDto := dto.MapToProjectReleaseResponse(*version, false)
 
 	return c.JSON(http.StatusOK, versionDto)
 }
 
+// Largest publish request accepted: the release file plus room for the metadata part.
+const maxPublishRequestSize = service.MaxReleaseFileSize + 1<<20
+
+// Publishes a release in a single multipart/form-data request. The "metadata" part must come
+// first so the request can be validated before the "file" part is streamed to object storage.
+func (h *ProjectReleaseHandler) PublishRelease(c *echo.Context) error {
+	ctx := c.Request().Context()
+	identifier := c.Param("identifier")
+	userId, ok := utils.GetSessionUserId(c)
+
+	if !ok {
+		return custom_errors.ErrAuthenticationRequired
+	}
+
+	c.Request().Body = http.MaxBytesReader(c.Response(), c.Request().Body, maxPublishRequestSize)
+
+	reader, err := c.Request().MultipartReader()
+
+	if err != nil {
+		return custom_errors.ErrInvalidRequest.WithDetail("Expected a multipart/form-data request.")
+	}
+
+	metadataPart, err := reader.NextPart()
+
+	if err != nil || metadataPart.FormName() != "metadata" {
+		return custom_errors.ErrInvalidRequest.WithDetail("The first part must be 'metadata'.")
+	}
+
+	var req dto.PublishProjectReleaseRequest
+
+	if err := json.NewDecoder(metadataPart).Decode(&req); err != nil {
+		return custom_errors.ErrInvalidRequest
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+
+	filePart, err := reader.NextPart()
+
+	if err != nil || filePart.FormName() != "file" || filepath.Ext(filePart.FileName()) != ".tmod" {
+		return custom_errors.ErrInvalidRequest.WithDetail("The second part must be a .tmod 'file'.")
+	}
+
+	deps := make([]service.CreateProjectReleaseDependencyParams, len(req.Dependencies))
+
+	for i, dep := range req.Dependencies {
+		deps[i] = service.CreateProjectReleaseDependencyParams{
+			ProjectId:        dep.ProjectId,
+			MinVersionNumber: dep.MinVersionNumber,
+			Type:             dep.Type,
+		}
+	}
+
+	release, err := h.projectReleaseService.PublishRelease(ctx, identifier, userId, service.PublishReleaseParams{
+		Name:            req.Name,
+		VersionNumber:   req.VersionNumber,
+		Changelog:       req.Changelog,
+		LoaderVersionId: req.LoaderVersionId,
+		Dependencies:    deps,
+		File:            filePart,
+	})
+
+	// The body limit is hit while the file streams to object storage
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return custom_errors.ErrProjectReleaseFileTooLarge
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, dto.MapToProjectReleaseResponse(*release, false))
+}
+
 func (h *ProjectReleaseHandler) GetRelease(c *echo.Context) error {
 	ctx := c.Request().Context()
 	projectIdentifier := c.Param("identifier")
@@ -158,28 +163,16 @@ func (h *ProjectReleaseHandler) GetRelease(c *echo.Context) error {
 	version, err := h.projectReleaseService.GetReleaseByIdWithDependencies(ctx, projectIdentifier, releaseId, userId)
 
 	if err != nil {
-		switch {
-		case errors.Is(err, custom_errors.ErrProjectNotFound):
-			return c.JSON(http.StatusNotFound, dto.ProblemDetails{
-				Title:  "Not Found",
-				Status: http.StatusNotFound,
-				Detail: "Project not found.",
-			})
-		case errors.Is(err, custom_errors.ErrProjectReleaseNotFound):
-			return c.JSON(http.StatusNotFound, dto.ProblemDetails{
-				Title:  "Not Found",
-				Status: http.StatusNotFound,
-				Detail: "Project release not found.",
-			})
-		default:
-			h.logger.Error("Unhandled get release error", "Project Identifier: ", projectIdentifier, "Release Id: ", releaseId, "Error:", err)
-			return c.JSON(http.StatusInternalServerError, dto.ProblemDetails{
-				Title:  "Internal Server Error",
-				Status: http.StatusInternalServerError,
-			})
-		}
+		return err
 	}
 
+	h.searchAnalyticsService.RecordClick(ctx, service.RecordClickParams{
+		SearchQueryId: c.QueryParam("searchId"),
+		ProjectId:     version.ProjectId,
+		ReleaseId:     &version.Id,
+		Type:          models.SearchClickTypeDownload,
+	})
+
 	return c.JSON(http.StatusOK, dto.MapToProjectReleaseResponse(*version, false))
 }
 
@@ -187,33 +180,20 @@ func (h *ProjectReleaseHandler) GetReleases(c *echo.Context) error {
 	ctx := c.Request().Context()
 	identifier := c.Param("identifier")
 	userId, _ := utils.GetSessionUserId(c)
+	page := getPageParams(c)
 
-	versions, err := h.projectReleaseService.GetReleasesByProjectId(ctx, identifier, userId)
+	versions, err := h.projectReleaseService.GetReleasesByProjectId(ctx, identifier, userId, page)
 
 	if err != nil {
-		switch {
-		case errors.Is(err, custom_errors.ErrProjectNotFound):
-			return c.JSON(http.StatusNotFound, dto.ProblemDetails{
-				Title:  "Not Found",
-				Status: http.StatusNotFound,
-				Detail: "Project not found.",
-			})
-		default:
-			h.logger.Error("Unhandled get releases error", "Identifier: ", identifier, "Error:", err)
-			return c.JSON(http.StatusInternalServerError, dto.ProblemDetails{
-				Title:  "Internal Server Error",
-				Status: http.StatusInternalServerError,
-			})
-		}
+		return err
 	}
 
-	versionsResponse := make([]dto.ProjectReleaseResponse, len(versions))
-
-	for i, v := range versions {
-		versionsResponse[i] = dto.MapToProjectReleaseResponse(v, true)
-	}
+	response := dto.MapToListResponse(versions, page.Limit, func(v models.ProjectRelease) dto.ProjectReleaseResponse {
+		return dto.MapToProjectReleaseResponse(v, true)
+	})
+	response.Next = setNextLink(c, versions.NextCursor)
 
-	return c.JSON(http.StatusOK, versionsResponse)
+	return c.JSON(http.StatusOK, response)
 }
 
 func (h *ProjectReleaseHandler) GeneratePresignedPutUrl(c *echo.Context) error {
@@ -222,11 +202,7 @@ func (h *ProjectReleaseHandler) GeneratePresignedPutUrl(c *echo.Context) error {
 	userId, ok := utils.GetSessionUserId(c)
 
 	if !ok {
-		return c.JSON(http.StatusUnauthorized, dto.ProblemDetails{
-			Title:  "Unauthorized",
-			Status: http.StatusUnauthorized,
-			Detail: "You are not authorised to perform this action",
-		})
+		return custom_errors.ErrAuthenticationRequired
 	}
 
 	fileSize := c.QueryParam("fileSize")
@@ -234,31 +210,7 @@ func (h *ProjectReleaseHandler) GeneratePresignedPutUrl(c *echo.Context) error {
 	url, err := h.projectReleaseService.GenerateProjectReleasePresignedPutUrl(ctx, identifier, userId, fileSize)
 
 	if err != nil {
-		switch {
-		case errors.Is(err, custom_errors.ErrProjectNotFound):
-			return c.JSON(http.StatusNotFound, dto.ProblemDetails{
-				Title:  "Not Found",
-				Status: http.StatusNotFound,
-				Detail: "Project not found.",
-			})
-		case errors.Is(err, custom_errors.ErrProjectUnauthorisedAction):
-			return c.JSON(http.StatusUnauthorized, dto.ProblemDetails{
-				Title:  "Unauthorised",
-				Status: http.StatusUnauthorized,
-				Detail: "You are not authorised to perform this action",
-			})
-		case errors.Is(err, custom_errors.ErrProjectReleaseInvalidFileSize):
-			return c.JSON(http.StatusBadRequest, dto.ProblemDetails{
-				Title:  "Bad Request",
-				Status: http.StatusBadRequest,
-				Detail: "Invalid project version file size",
-			})
-		default:
-			return c.JSON(http.StatusInternalServerError, dto.ProblemDetails{
-				Title:  "Internal Server Error",
-				Status: http.StatusInternalServerError,
-			})
-		}
+		return err
 	}
 
 	return c.JSON(http.StatusOK, url)
@@ -0,0 +1,27 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v5"
+	"github.com/terraforge-gg/terraforge/internal/dto"
+)
+
+// Reports the caller's rate limits, implemented by the rate limit middleware.
+type RateLimitStatus interface {
+	Status(c *echo.Context) dto.RateLimitsResponse
+}
+
+type RateLimitHandler struct {
+	rateLimits RateLimitStatus
+}
+
+func NewRateLimitHandler(rateLimits RateLimitStatus) *RateLimitHandler {
+	return &RateLimitHandler{
+		rateLimits: rateLimits,
+	}
+}
+
+func (h *RateLimitHandler) GetRateLimits(c *echo.Context) error {
+	return c.JSON(http.StatusOK, h.rateLimits.Status(c))
+}
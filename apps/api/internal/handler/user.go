@@ -1,29 +1,32 @@
 package handler
 
 import (
-	"errors"
 	"log/slog"
 	"net/http"
 
 	"github.com/labstack/echo/v5"
 	"github.com/terraforge-gg/terraforge/internal/config"
 	"github.com/terraforge-gg/terraforge/internal/dto"
-	custom_errors "github.com/terraforge-gg/terraforge/internal/errors"
+	"github.com/terraforge-gg/terraforge/internal/models"
 	"github.com/terraforge-gg/terraforge/internal/service"
 	"github.com/terraforge-gg/terraforge/internal/utils"
 )
 
 type UserHandler struct {
-	cfg            *config.Config
-	logger         *slog.Logger
-	projectService service.ProjectService
+	cfg                    *config.Config
+	logger                 *slog.Logger
+	projectService         service.ProjectService
+	searchService          service.SearchService
+	searchAnalyticsService service.SearchAnalyticsService
 }
 
-func NewUserHandler(cfg *config.Config, logger *slog.Logger, projectService service.ProjectService) *UserHandler {
+func NewUserHandler(cfg *config.Config, logger *slog.Logger, projectService service.ProjectService, searchService service.SearchService, searchAnalyticsService service.SearchAnalyticsService) *UserHandler {
 	return &UserHandler{
-		cfg:            cfg,
-		logger:         logger,
-		projectService: projectService,
+		cfg:                    cfg,
+		logger:                 logger,
+		projectService:         projectService,
+		searchService:          searchService,
+		searchAnalyticsService: searchAnalyticsService,
 	}
 }
 
@@ -31,40 +34,54 @@ func (h *UserHandler) GetProjectsByUserId(c *echo.Context) error {
 	ctx := c.Request().Context()
 	userIdentifier := c.Param("userIdentifier")
 	userId, _ := utils.GetSessionUserId(c)
+	page := getPageParams(c)
 
 	projects, err := h.projectService.GetProjectsByUserIdentifier(ctx, service.GetProjectsByUserIdentifierParams{
 		UserIdentifier: userIdentifier,
 		SessionUserId:  userId,
+		Page:           page,
 	})
 
 	if err != nil {
-		switch {
-		case errors.Is(err, custom_errors.ErrUserNotFound):
-			return c.JSON(http.StatusNotFound, dto.ProblemDetails{
-				Title:  "Not Found",
-				Status: http.StatusNotFound,
-				Detail: "User not found.",
-			})
-		case errors.Is(err, custom_errors.ErrNoProjectsFound):
-			return c.JSON(http.StatusNotFound, dto.ProblemDetails{
-				Title:  "Not Found",
-				Status: http.StatusNotFound,
-				Detail: "No projects found.",
-			})
-		default:
-			h.logger.Error("Unhandled get user projects error", "User identifier: ", userIdentifier, "Error:", err)
-			return c.JSON(http.StatusInternalServerError, dto.ProblemDetails{
-				Title:  "Internal Server Error",
-				Status: http.StatusInternalServerError,
-			})
-		}
+		return err
 	}
 
-	userProjects := make([]dto.ProjectResponse, len(projects))
+	response := dto.MapToListResponse(projects, page.Limit, dto.ProjectToProjectResponse)
+	response.Next = setNextLink(c, projects.NextCursor)
 
-	for i, p := range projects {
-		userProjects[i] = dto.ProjectToProjectResponse(p)
+	return c.JSON(http.StatusOK, response)
+}
+
+func (h *UserHandler) SearchUsers(c *echo.Context) error {
+	ctx := c.Request().Context()
+	query := c.QueryParam("q")
+	page := getPageParams(c)
+	limit := page.Limit
+
+	offset, err := getSearchOffset(c, page)
+
+	if err != nil {
+		return err
+	}
+
+	users, totalHits, err := h.searchService.SearchUsers(ctx, query, limit, offset)
+
+	if err != nil {
+		return err
 	}
 
-	return c.JSON(http.StatusOK, userProjects)
+	response := dto.UserToUserSearchResponse(users, totalHits, limit, offset)
+	response.Next = setNextLink(c, response.NextCursor)
+
+	userId, _ := utils.GetSessionUserId(c)
+	response.SearchId, _ = h.searchAnalyticsService.RecordSearch(ctx, service.RecordSearchParams{
+		Index:       models.SearchIndexUsers,
+		Query:       query,
+		ResultCount: totalHits,
+		Limit:       limit,
+		Offset:      offset,
+		UserId:      userId,
+	})
+
+	return c.JSON(http.StatusOK, response)
 }
@@ -1,10 +1,14 @@
 package aws
 
 import (
+	"net/http"
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
 	"github.com/terraforge-gg/terraforge/internal/config"
+	"github.com/terraforge-gg/terraforge/internal/tracing"
 
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 )
@@ -20,6 +24,10 @@ func NewS3Client(cfg *config.Config, aws_config *aws.Config) *s3.Client {
 	return s3.NewFromConfig(*aws_config, func(o *s3.Options) {
 		o.BaseEndpoint = aws.String(endpoint)
 		o.UsePathStyle = true
+		// Spans are named after the S3 operation, such as PutObject or UploadPart
+		o.HTTPClient = tracing.HTTPClient("s3", awshttp.NewBuildableClient().GetTransport(), func(r *http.Request) string {
+			return awsmiddleware.GetOperationName(r.Context())
+		})
 	})
 }
 
@@ -2,9 +2,11 @@ package meilisearch
 
 import (
 	"context"
+	"net/http"
 
 	"github.com/meilisearch/meilisearch-go"
 	msearch "github.com/meilisearch/meilisearch-go"
+	"github.com/terraforge-gg/terraforge/internal/tracing"
 )
 
 type MeiliSearchClient struct {
@@ -12,8 +14,15 @@ type MeiliSearchClient struct {
 }
 
 func NewMeiliSearch(meiliSeachHostUrl string, apiKey string) *MeiliSearchClient {
+	// Every request goes to the one host, so keep as many idle connections as the client's default
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = transport.MaxIdleConns
+
 	return &MeiliSearchClient{
-		Client: meilisearch.New(meiliSeachHostUrl, meilisearch.WithAPIKey(apiKey)),
+		Client: meilisearch.New(meiliSeachHostUrl,
+			meilisearch.WithAPIKey(apiKey),
+			meilisearch.WithCustomClient(tracing.HTTPClient("meilisearch", transport, nil)),
+		),
 	}
 }
 
@@ -0,0 +1,202 @@
+package meilisearch
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	msearch "github.com/meilisearch/meilisearch-go"
+)
+
+//go:embed index_settings.json
+var indexSettingsFile []byte
+
+// IndexSettingsConfig is the versioned set of index settings checked into index_settings.json.
+// Bump Version whenever the file changes so deployments can be traced back to a revision.
+type IndexSettingsConfig struct {
+	Version int                      `json:"version"`
+	Indexes map[string]IndexSettings `json:"indexes"`
+}
+
+// IndexSettings lists the settings managed for a single index. The file is the source of truth,
+// so omitted or empty fields are reset to Meilisearch's defaults on the live index.
+type IndexSettings struct {
+	PrimaryKey           string                 `json:"primaryKey"`
+	SearchableAttributes []string               `json:"searchableAttributes"`
+	FilterableAttributes []string               `json:"filterableAttributes"`
+	SortableAttributes   []string               `json:"sortableAttributes"`
+	RankingRules         []string               `json:"rankingRules"`
+	DistinctAttribute    *string                `json:"distinctAttribute"`
+	StopWords            []string               `json:"stopWords"`
+	Synonyms             map[string][]string    `json:"synonyms"`
+	TypoTolerance        *msearch.TypoTolerance `json:"typoTolerance"`
+}
+
+// Settings that can be reset, named as in the Meilisearch API.
+const (
+	SettingSearchableAttributes = "searchableAttributes"
+	SettingFilterableAttributes = "filterableAttributes"
+	SettingSortableAttributes   = "sortableAttributes"
+	SettingRankingRules         = "rankingRules"
+	SettingDistinctAttribute    = "distinctAttribute"
+	SettingStopWords            = "stopWords"
+	SettingSynonyms             = "synonyms"
+	SettingTypoTolerance        = "typoTolerance"
+)
+
+// Meilisearch's defaults for the settings that are not empty by default, which an omitted
+// setting is compared against.
+var (
+	defaultSearchableAttributes = []string{"*"}
+	defaultRankingRules         = []string{"words", "typo", "proximity", "attribute", "sort", "exactness"}
+	defaultTypoTolerance        = &msearch.TypoTolerance{
+		Enabled:             true,
+		MinWordSizeForTypos: msearch.MinWordSizeForTypos{OneTypo: 5, TwoTypos: 9},
+	}
+)
+
+// SettingsChange is what has to change for an index to match its declared settings. Update
+// holds the settings to set, nil when there are none, and Reset the settings to return to
+// their defaults, which Update cannot express as the client omits empty fields.
+type SettingsChange struct {
+	Update *msearch.Settings
+	Reset  []string
+}
+
+func LoadIndexSettingsConfig() (*IndexSettingsConfig, error) {
+	return ParseIndexSettingsConfig(indexSettingsFile)
+}
+
+func ParseIndexSettingsConfig(b []byte) (*IndexSettingsConfig, error) {
+	var cfg IndexSettingsConfig
+
+	if err := json.Unmarshal(b, &cfg); err != nil {
+		return nil, fmt.Errorf("parse index settings: %w", err)
+	}
+
+	if cfg.Version < 1 {
+		return nil, fmt.Errorf("parse index settings: invalid version %d", cfg.Version)
+	}
+
+	return &cfg, nil
+}
+
+// Diff compares the declared settings with the live settings of an index and returns the
+// change containing only the fields that differ, or nil when nothing changed.
+func (d IndexSettings) Diff(live *msearch.Settings) *SettingsChange {
+	if live == nil {
+		live = &msearch.Settings{}
+	}
+
+	change := &SettingsChange{Update: &msearch.Settings{}}
+	updated := false
+
+	diff := func(name string, declared bool, same bool, update func()) {
+		switch {
+		case same:
+		case declared:
+			update()
+			updated = true
+		default:
+			change.Reset = append(change.Reset, name)
+		}
+	}
+
+	searchable := d.SearchableAttributes
+	if len(searchable) == 0 {
+		searchable = defaultSearchableAttributes
+	}
+	diff(SettingSearchableAttributes, len(d.SearchableAttributes) > 0, slices.Equal(searchable, live.SearchableAttributes), func() {
+		change.Update.SearchableAttributes = d.SearchableAttributes
+	})
+
+	rankingRules := d.RankingRules
+	if len(rankingRules) == 0 {
+		rankingRules = defaultRankingRules
+	}
+	diff(SettingRankingRules, len(d.RankingRules) > 0, slices.Equal(rankingRules, live.RankingRules), func() {
+		change.Update.RankingRules = d.RankingRules
+	})
+
+	diff(SettingFilterableAttributes, len(d.FilterableAttributes) > 0, sameSet(d.FilterableAttributes, live.FilterableAttributes), func() {
+		change.Update.FilterableAttributes = d.FilterableAttributes
+	})
+
+	diff(SettingSortableAttributes, len(d.SortableAttributes) > 0, sameSet(d.SortableAttributes, live.SortableAttributes), func() {
+		change.Update.SortableAttributes = d.SortableAttributes
+	})
+
+	diff(SettingStopWords, len(d.StopWords) > 0, sameSet(d.StopWords, live.StopWords), func() {
+		change.Update.StopWords = d.StopWords
+	})
+
+	diff(SettingSynonyms, len(d.Synonyms) > 0, sameSynonyms(d.Synonyms, live.Synonyms), func() {
+		change.Update.Synonyms = d.Synonyms
+	})
+
+	sameDistinct := d.DistinctAttribute == nil && live.DistinctAttribute == nil ||
+		d.DistinctAttribute != nil && live.DistinctAttribute != nil && *d.DistinctAttribute == *live.DistinctAttribute
+	diff(SettingDistinctAttribute, d.DistinctAttribute != nil, sameDistinct, func() {
+		change.Update.DistinctAttribute = d.DistinctAttribute
+	})
+
+	typoTolerance := d.TypoTolerance
+	if typoTolerance == nil {
+		typoTolerance = defaultTypoTolerance
+	}
+	diff(SettingTypoTolerance, d.TypoTolerance != nil, sameTypoTolerance(typoTolerance, live.TypoTolerance), func() {
+		change.Update.TypoTolerance = d.TypoTolerance
+	})
+
+	if !updated {
+		if len(change.Reset) == 0 {
+			return nil
+		}
+
+		change.Update = nil
+	}
+
+	return change
+}
+
+func sameSet(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	sortedA := slices.Clone(a)
+	sortedB := slices.Clone(b)
+	slices.Sort(sortedA)
+	slices.Sort(sortedB)
+
+	return slices.Equal(sortedA, sortedB)
+}
+
+func sameSynonyms(a map[string][]string, b map[string][]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for word, synonyms := range a {
+		liveSynonyms, ok := b[word]
+
+		if !ok || !sameSet(synonyms, liveSynonyms) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func sameTypoTolerance(a *msearch.TypoTolerance, b *msearch.TypoTolerance) bool {
+	if b == nil {
+		return false
+	}
+
+	return a.Enabled == b.Enabled &&
+		a.MinWordSizeForTypos == b.MinWordSizeForTypos &&
+		a.DisableOnNumbers == b.DisableOnNumbers &&
+		sameSet(a.DisableOnWords, b.DisableOnWords) &&
+		sameSet(a.DisableOnAttributes, b.DisableOnAttributes)
+}
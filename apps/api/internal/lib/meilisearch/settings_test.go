@@ -0,0 +1,120 @@
+package meilisearch
+
+import (
+	"testing"
+
+	msearch "github.com/meilisearch/meilisearch-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexSettingsConfigLoads(t *testing.T) {
+	cfg, err := LoadIndexSettingsConfig()
+	require.NoError(t, err)
+
+	assert.GreaterOrEqual(t, cfg.Version, 1)
+	assert.Contains(t, cfg.Indexes, "projects")
+	assert.Contains(t, cfg.Indexes, "users")
+}
+
+func TestIndexSettingsDiff_NoChanges(t *testing.T) {
+	distinct := "slug"
+	declared := IndexSettings{
+		SearchableAttributes: []string{"name", "slug"},
+		FilterableAttributes: []string{"type", "downloads"},
+		StopWords:            []string{"the", "a"},
+		Synonyms:             map[string][]string{"qol": {"quality of life"}},
+		DistinctAttribute:    &distinct,
+		TypoTolerance: &msearch.TypoTolerance{
+			Enabled:             true,
+			MinWordSizeForTypos: msearch.MinWordSizeForTypos{OneTypo: 4, TwoTypos: 8},
+		},
+	}
+
+	live := &msearch.Settings{
+		SearchableAttributes: []string{"name", "slug"},
+		RankingRules:         defaultRankingRules,
+		FilterableAttributes: []string{"downloads", "type"},
+		StopWords:            []string{"a", "the"},
+		Synonyms:             map[string][]string{"qol": {"quality of life"}},
+		DistinctAttribute:    &distinct,
+		TypoTolerance: &msearch.TypoTolerance{
+			Enabled:             true,
+			MinWordSizeForTypos: msearch.MinWordSizeForTypos{OneTypo: 4, TwoTypos: 8},
+			DisableOnWords:      []string{},
+			DisableOnAttributes: []string{},
+		},
+	}
+
+	assert.Nil(t, declared.Diff(live))
+}
+
+func TestIndexSettingsDiff_OnlyChangedFields(t *testing.T) {
+	declared := IndexSettings{
+		SearchableAttributes: []string{"name", "slug"},
+		RankingRules:         []string{"words", "typo"},
+		Synonyms:             map[string][]string{"qol": {"quality of life"}},
+	}
+
+	live := &msearch.Settings{
+		SearchableAttributes: []string{"slug", "name"},
+		RankingRules:         []string{"words", "typo"},
+		TypoTolerance:        defaultTypoTolerance,
+	}
+
+	change := declared.Diff(live)
+	require.NotNil(t, change)
+	require.NotNil(t, change.Update)
+
+	assert.Equal(t, []string{"name", "slug"}, change.Update.SearchableAttributes)
+	assert.Equal(t, map[string][]string{"qol": {"quality of life"}}, change.Update.Synonyms)
+	assert.Nil(t, change.Update.RankingRules)
+	assert.Nil(t, change.Update.TypoTolerance)
+	assert.Empty(t, change.Reset)
+}
+
+func TestIndexSettingsDiff_ResetsRemovedSettings(t *testing.T) {
+	distinct := "slug"
+	declared := IndexSettings{
+		SearchableAttributes: []string{"name"},
+		StopWords:            []string{},
+	}
+
+	live := &msearch.Settings{
+		SearchableAttributes: []string{"name"},
+		RankingRules:         defaultRankingRules,
+		FilterableAttributes: []string{"type"},
+		StopWords:            []string{"the"},
+		Synonyms:             map[string][]string{"qol": {"quality of life"}},
+		DistinctAttribute:    &distinct,
+		TypoTolerance:        defaultTypoTolerance,
+	}
+
+	change := declared.Diff(live)
+	require.NotNil(t, change)
+
+	assert.Nil(t, change.Update)
+	assert.ElementsMatch(t, []string{
+		SettingFilterableAttributes,
+		SettingStopWords,
+		SettingSynonyms,
+		SettingDistinctAttribute,
+	}, change.Reset)
+}
+
+func TestIndexSettingsDiff_OmittedSettingsAtDefaults(t *testing.T) {
+	live := &msearch.Settings{
+		SearchableAttributes: []string{"*"},
+		RankingRules:         []string{"words", "typo", "proximity", "attribute", "sort", "exactness"},
+		FilterableAttributes: []string{},
+		Synonyms:             map[string][]string{},
+		TypoTolerance: &msearch.TypoTolerance{
+			Enabled:             true,
+			MinWordSizeForTypos: msearch.MinWordSizeForTypos{OneTypo: 5, TwoTypos: 9},
+			DisableOnWords:      []string{},
+			DisableOnAttributes: []string{},
+		},
+	}
+
+	assert.Nil(t, IndexSettings{}.Diff(live))
+}
@@ -42,3 +42,32 @@ type ProjectSearchResult struct {
 	Projects  []ProjectDocument
 	TotalHits int64
 }
+
+type UserDocument struct {
+	Id              string    `json:"id"`
+	Name            string    `json:"name"`
+	Username        string    `json:"username"`
+	DisplayUsername *string   `json:"displayUsername"`
+	Image           *string   `json:"image"`
+	ProjectCount    int64     `json:"projectCount"`
+	TotalDownloads  int64     `json:"totalDownloads"`
+	CreatedAt       time.Time `json:"createdAt"`
+}
+
+func UserToDocument(user *models.UserWithStats) *UserDocument {
+	return &UserDocument{
+		Id:              user.User.Id,
+		Name:            user.User.Name,
+		Username:        user.User.Username,
+		DisplayUsername: user.User.DisplayUsername,
+		Image:           user.User.Image,
+		ProjectCount:    user.Stats.ProjectCount,
+		TotalDownloads:  user.Stats.TotalDownloads,
+		CreatedAt:       user.User.CreatedAt,
+	}
+}
+
+type UserSearchResult struct {
+	Users     []UserDocument
+	TotalHits int64
+}
@@ -0,0 +1,166 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrCircuitOpen is returned for every command while Redis is considered unavailable.
+var ErrCircuitOpen = errors.New("redis circuit open")
+
+type BreakerState string
+
+const (
+	BreakerClosed   BreakerState = "closed"
+	BreakerOpen     BreakerState = "open"
+	BreakerHalfOpen BreakerState = "half-open"
+)
+
+const (
+	breakerFailureThreshold = 5
+	breakerOpenTimeout      = 10 * time.Second
+)
+
+type BreakerStats struct {
+	State        BreakerState `json:"state"`
+	Trips        int64        `json:"trips"`
+	ShortCircuit int64        `json:"shortCircuited"`
+}
+
+// CircuitBreaker trips after consecutive connection failures so callers fail fast
+// instead of waiting on timeouts. After breakerOpenTimeout a single command is let
+// through as a probe, closing the breaker again if it succeeds.
+type CircuitBreaker struct {
+	mu           sync.Mutex
+	state        BreakerState
+	failures     int
+	openedAt     time.Time
+	probing      bool
+	trips        int64
+	shortCircuit int64
+	onChange     func(from BreakerState, to BreakerState)
+}
+
+func NewCircuitBreaker(onChange func(from BreakerState, to BreakerState)) *CircuitBreaker {
+	return &CircuitBreaker{state: BreakerClosed, onChange: onChange}
+}
+
+func (b *CircuitBreaker) Stats() BreakerStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return BreakerStats{State: b.state, Trips: b.trips, ShortCircuit: b.shortCircuit}
+}
+
+func (b *CircuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		if time.Since(b.openedAt) < breakerOpenTimeout {
+			b.shortCircuit++
+			return false
+		}
+		b.setState(BreakerHalfOpen)
+		b.probing = true
+		return true
+	case BreakerHalfOpen:
+		if b.probing {
+			b.shortCircuit++
+			return false
+		}
+		b.probing = true
+		return true
+	}
+
+	return true
+}
+
+func (b *CircuitBreaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+
+	if !isConnectionError(err) {
+		b.failures = 0
+		if b.state != BreakerClosed {
+			b.setState(BreakerClosed)
+		}
+		return
+	}
+
+	b.failures++
+
+	if b.state == BreakerHalfOpen || b.failures >= breakerFailureThreshold {
+		b.openedAt = time.Now()
+		if b.state != BreakerOpen {
+			b.trips++
+			b.setState(BreakerOpen)
+		}
+	}
+}
+
+func (b *CircuitBreaker) setState(state BreakerState) {
+	from := b.state
+	b.state = state
+
+	if b.onChange != nil {
+		b.onChange(from, state)
+	}
+}
+
+// Only failures to reach Redis count. Nil replies and error replies such as NOSCRIPT
+// mean the server is up, and a cancelled request says nothing about Redis at all.
+func isConnectionError(err error) bool {
+	if err == nil || errors.Is(err, redis.Nil) || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var redisErr redis.Error
+	if errors.As(err, &redisErr) {
+		return false
+	}
+
+	return true
+}
+
+func (b *CircuitBreaker) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network string, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+func (b *CircuitBreaker) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if !b.allow() {
+			cmd.SetErr(ErrCircuitOpen)
+			return ErrCircuitOpen
+		}
+
+		err := next(ctx, cmd)
+		b.record(err)
+		return err
+	}
+}
+
+func (b *CircuitBreaker) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		if !b.allow() {
+			for _, cmd := range cmds {
+				cmd.SetErr(ErrCircuitOpen)
+			}
+			return ErrCircuitOpen
+		}
+
+		err := next(ctx, cmds)
+		b.record(err)
+		return err
+	}
+}
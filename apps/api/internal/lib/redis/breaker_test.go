@@ -0,0 +1,74 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+var errConnRefused = errors.New("dial tcp: connection refused")
+
+func TestCircuitBreaker_TripsAfterConsecutiveFailures(t *testing.T) {
+	b := NewCircuitBreaker(nil)
+
+	for range breakerFailureThreshold {
+		assert.True(t, b.allow())
+		b.record(errConnRefused)
+	}
+
+	assert.Equal(t, BreakerOpen, b.Stats().State)
+	assert.False(t, b.allow())
+	assert.EqualValues(t, 1, b.Stats().Trips)
+	assert.EqualValues(t, 1, b.Stats().ShortCircuit)
+}
+
+func TestCircuitBreaker_IgnoresReplyErrors(t *testing.T) {
+	b := NewCircuitBreaker(nil)
+
+	for range breakerFailureThreshold * 2 {
+		b.record(redis.Nil)
+		b.record(context.Canceled)
+	}
+
+	assert.Equal(t, BreakerClosed, b.Stats().State)
+}
+
+func TestCircuitBreaker_HalfOpenProbe(t *testing.T) {
+	var transitions []BreakerState
+	b := NewCircuitBreaker(func(from BreakerState, to BreakerState) {
+		transitions = append(transitions, to)
+	})
+
+	for range breakerFailureThreshold {
+		b.record(errConnRefused)
+	}
+	b.openedAt = time.Now().Add(-breakerOpenTimeout)
+
+	// Only one probe is let through while half-open
+	assert.True(t, b.allow())
+	assert.False(t, b.allow())
+
+	b.record(nil)
+
+	assert.Equal(t, BreakerClosed, b.Stats().State)
+	assert.Equal(t, []BreakerState{BreakerOpen, BreakerHalfOpen, BreakerClosed}, transitions)
+}
+
+func TestCircuitBreaker_FailedProbeReopens(t *testing.T) {
+	b := NewCircuitBreaker(nil)
+
+	for range breakerFailureThreshold {
+		b.record(errConnRefused)
+	}
+	b.openedAt = time.Now().Add(-breakerOpenTimeout)
+
+	assert.True(t, b.allow())
+	b.record(errConnRefused)
+
+	assert.Equal(t, BreakerOpen, b.Stats().State)
+	assert.False(t, b.allow())
+}
@@ -2,32 +2,57 @@ package redis
 
 import (
 	"context"
+	"log/slog"
+	"time"
 
+	"github.com/redis/go-redis/extra/redisotel/v9"
 	"github.com/redis/go-redis/v9"
 )
 
+// Bounds how long startup waits to find out whether Redis is reachable.
+const startupPingTimeout = 5 * time.Second
+
 type RedisClient struct {
-	Client *redis.Client
+	Client  *redis.Client
+	Breaker *CircuitBreaker
 }
 
-func NewRedisClient(redisUrl string, password string) (*RedisClient, error) {
+// Returns a client even when Redis cannot be reached, which is only logged.
+func NewRedisClient(logger *slog.Logger, redisUrl string, password string) (*RedisClient, error) {
 	rdb := redis.NewClient(&redis.Options{
 		Addr:     redisUrl,
 		Password: password,
 		DB:       0,
 	})
 
-	if err := rdb.Ping(context.Background()).Err(); err != nil {
+	// Added before the breaker so commands it short circuits are traced too. Arguments are left
+	// out of spans as they hold cached values.
+	if err := redisotel.InstrumentTracing(rdb, redisotel.WithDBStatement(false)); err != nil {
 		return nil, err
 	}
 
+	breaker := NewCircuitBreaker(func(from BreakerState, to BreakerState) {
+		logger.Warn("Redis circuit breaker changed state", "from", from, "to", to)
+	})
+	rdb.AddHook(breaker)
+
+	// Redis being down must not stop the API from starting, the breaker, the local rate
+	// limiter and /ready cover for it until it is back
+	ctx, cancel := context.WithTimeout(context.Background(), startupPingTimeout)
+	defer cancel()
+
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		logger.Warn("Redis is unreachable, starting without it", "error", err)
+	}
+
 	return &RedisClient{
-		Client: rdb,
+		Client:  rdb,
+		Breaker: breaker,
 	}, nil
 }
 
-func (r *RedisClient) Close() {
-	r.Client.Close()
+func (r *RedisClient) Close() error {
+	return r.Client.Close()
 }
 
 func (r *RedisClient) Health(ctx context.Context) error {
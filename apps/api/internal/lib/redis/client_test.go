@@ -0,0 +1,17 @@
+package redis
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRedisClient_StartsWhileRedisIsDown(t *testing.T) {
+	client, err := NewRedisClient(slog.New(slog.DiscardHandler), "127.0.0.1:1", "")
+
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+	assert.Error(t, client.Health(t.Context()))
+}
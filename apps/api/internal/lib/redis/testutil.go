@@ -0,0 +1,84 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+type TestRedis struct {
+	Container testcontainers.Container
+	Client    *RedisClient
+	Addr      string
+}
+
+func NewTestRedis(t *testing.T) (*TestRedis, error) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	req := testcontainers.ContainerRequest{
+		Image:        "redis:7-alpine",
+		ExposedPorts: []string{"6379/tcp"},
+		WaitingFor:   wait.ForLog("Ready to accept connections").WithStartupTimeout(30 * time.Second),
+	}
+
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to start redis container: %w", err)
+	}
+
+	host, err := container.Host(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get container host: %w", err)
+	}
+
+	port, err := container.MappedPort(ctx, "6379/tcp")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get container port: %w", err)
+	}
+
+	addr := fmt.Sprintf("%s:%s", host, port.Port())
+
+	client, err := NewRedisClient(slog.New(slog.DiscardHandler), addr, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to redis: %w", err)
+	}
+
+	return &TestRedis{
+		Container: container,
+		Client:    client,
+		Addr:      addr,
+	}, nil
+}
+
+func (tr *TestRedis) Teardown(ctx context.Context) error {
+	tr.Client.Close()
+	return tr.Container.Terminate(ctx)
+}
+
+func NewTestRedisWithCleanup(t *testing.T) (*TestRedis, error) {
+	t.Helper()
+
+	tr, err := NewTestRedis(t)
+	if err != nil {
+		return nil, err
+	}
+
+	t.Cleanup(func() {
+		ctx := context.Background()
+		if err := tr.Teardown(ctx); err != nil {
+			t.Logf("failed to teardown test redis: %v", err)
+		}
+	})
+
+	return tr, nil
+}
@@ -0,0 +1,75 @@
+package tmod
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Magic is the four byte signature every .tmod file starts with.
+const Magic = "TMOD"
+
+// Longest tModLoader version string accepted, real versions are well below this.
+const maxVersionLength = 64
+
+var ErrInvalidFile = errors.New("not a tmod file")
+
+type Header struct {
+	// Version of tModLoader the mod was built with, e.g. 2024.5.3.0
+	LoaderVersion string
+}
+
+// ReadHeader reads the magic and tModLoader version from the start of a .tmod file.
+// Only the header is consumed, so callers can keep streaming the rest of r.
+func ReadHeader(r io.Reader) (*Header, error) {
+	magic := make([]byte, len(Magic))
+
+	if _, err := io.ReadFull(r, magic); err != nil || string(magic) != Magic {
+		return nil, ErrInvalidFile
+	}
+
+	version, err := readString(r)
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidFile, err)
+	}
+
+	return &Header{LoaderVersion: version}, nil
+}
+
+// Strings are written by .NET's BinaryWriter: a 7-bit encoded length followed by UTF-8 bytes.
+func readString(r io.Reader) (string, error) {
+	length, err := binary.ReadUvarint(byteReader{r})
+
+	if err != nil {
+		return "", err
+	}
+
+	if length == 0 || length > maxVersionLength {
+		return "", fmt.Errorf("invalid string length %d", length)
+	}
+
+	b := make([]byte, length)
+
+	if _, err := io.ReadFull(r, b); err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+// Reads one byte at a time so no more than the header is taken from the underlying reader.
+type byteReader struct {
+	r io.Reader
+}
+
+func (b byteReader) ReadByte() (byte, error) {
+	if br, ok := b.r.(io.ByteReader); ok {
+		return br.ReadByte()
+	}
+
+	var buf [1]byte
+	_, err := io.ReadFull(b.r, buf[:])
+	return buf[0], err
+}
@@ -0,0 +1,42 @@
+package tmod
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestFile(version string, body string) []byte {
+	var b bytes.Buffer
+	b.WriteString(Magic)
+	b.WriteByte(byte(len(version)))
+	b.WriteString(version)
+	b.WriteString(body)
+	return b.Bytes()
+}
+
+func TestReadHeader(t *testing.T) {
+	r := bytes.NewReader(newTestFile("2024.5.3.0", "rest"))
+
+	header, err := ReadHeader(r)
+	require.NoError(t, err)
+	assert.Equal(t, "2024.5.3.0", header.LoaderVersion)
+
+	rest, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, "rest", string(rest))
+}
+
+func TestReadHeader_InvalidMagic(t *testing.T) {
+	_, err := ReadHeader(strings.NewReader("PK\x03\x04"))
+	assert.ErrorIs(t, err, ErrInvalidFile)
+}
+
+func TestReadHeader_Truncated(t *testing.T) {
+	_, err := ReadHeader(strings.NewReader(Magic + "\x0a2024"))
+	assert.ErrorIs(t, err, ErrInvalidFile)
+}
@@ -1,12 +1,72 @@
 package logger
 
 import (
+	"context"
 	"log/slog"
 	"os"
+
+	"github.com/terraforge-gg/terraforge/internal/requestctx"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Keys every log line shares. Other attributes use camelCase keys as well, such as "projectId".
+const (
+	KeyRequest   = "request"
+	KeyRequestId = "id"
+	KeyRoute     = "route"
+	KeyUserId    = "userId"
+	KeyTraceId   = "traceId"
+	KeyError     = "error"
 )
 
 func New() *slog.Logger {
-	return slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
-		Level: slog.LevelInfo,
-	}))
+	return slog.New(NewHandler(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+		Level:       slog.LevelInfo,
+		ReplaceAttr: Redact,
+	})))
+}
+
+// Handler adds the request a line was logged for, taken from the context given to the
+// logger's Context methods such as ErrorContext, and the trace it belongs to.
+type Handler struct {
+	next slog.Handler
+}
+
+func NewHandler(next slog.Handler) *Handler {
+	return &Handler{next: next}
+}
+
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	// Grouped so the caller's own "userId", such as the user being banned, can't be mistaken for it
+	if info, ok := requestctx.FromContext(ctx); ok {
+		attrs := []any{slog.String(KeyRequestId, info.Id)}
+
+		if info.Route != "" {
+			attrs = append(attrs, slog.String(KeyRoute, info.Route))
+		}
+
+		if userId := info.UserId(); userId != "" {
+			attrs = append(attrs, slog.String(KeyUserId, userId))
+		}
+
+		r.AddAttrs(slog.Group(KeyRequest, attrs...))
+	}
+
+	if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+		r.AddAttrs(slog.String(KeyTraceId, span.TraceID().String()))
+	}
+
+	return h.next.Handle(ctx, r)
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &Handler{next: h.next.WithAttrs(attrs)}
+}
+
+func (h *Handler) WithGroup(name string) slog.Handler {
+	return &Handler{next: h.next.WithGroup(name)}
 }
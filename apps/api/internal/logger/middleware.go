@@ -0,0 +1,51 @@
+package logger
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/labstack/echo/v5"
+	"github.com/labstack/echo/v5/middleware"
+)
+
+// Middleware logs every request once it has been served. It goes first so the latency covers
+// the whole chain, and logs with the request's context so the line carries its id and user.
+func Middleware(logger *slog.Logger) echo.MiddlewareFunc {
+	return middleware.RequestLoggerWithConfig(middleware.RequestLoggerConfig{
+		LogLatency:       true,
+		LogRemoteIP:      true,
+		LogMethod:        true,
+		LogURI:           true,
+		LogUserAgent:     true,
+		LogStatus:        true,
+		LogContentLength: true,
+		LogResponseSize:  true,
+		// Resolves the error to its response here, so the status logged is the one sent
+		HandleError: true,
+		LogValuesFunc: func(c *echo.Context, v middleware.RequestLoggerValues) error {
+			level := slog.LevelInfo
+			if v.Status >= http.StatusInternalServerError {
+				level = slog.LevelError
+			}
+
+			attrs := []slog.Attr{
+				slog.String("method", v.Method),
+				slog.String("uri", v.URI),
+				slog.Int("status", v.Status),
+				slog.Duration("latency", v.Latency),
+				slog.String("bytesIn", v.ContentLength),
+				slog.Int64("bytesOut", v.ResponseSize),
+				slog.String("userAgent", v.UserAgent),
+				slog.String("remoteIp", v.RemoteIP),
+			}
+
+			if v.Error != nil {
+				attrs = append(attrs, slog.Any(KeyError, v.Error))
+			}
+
+			logger.LogAttrs(c.Request().Context(), level, "Request", attrs...)
+
+			return nil
+		},
+	})
+}
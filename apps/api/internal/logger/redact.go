@@ -0,0 +1,70 @@
+package logger
+
+import (
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"github.com/terraforge-gg/terraforge/internal/models"
+)
+
+const redacted = "[REDACTED]"
+
+// Attributes whose value is never logged, matched case insensitively.
+var sensitiveKeys = map[string]bool{
+	"email":         true,
+	"password":      true,
+	"token":         true,
+	"rawtoken":      true,
+	"accesstoken":   true,
+	"refreshtoken":  true,
+	"authorization": true,
+	"cookie":        true,
+	"secret":        true,
+	"apikey":        true,
+}
+
+var (
+	emailPattern  = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	tokenPattern  = regexp.MustCompile(regexp.QuoteMeta(models.PersonalAccessTokenPrefix) + `[A-Za-z0-9_-]+`)
+	bearerPattern = regexp.MustCompile(`(?i)bearer\s+[A-Za-z0-9._~+/=-]+`)
+	jwtPattern    = regexp.MustCompile(`eyJ[A-Za-z0-9_-]*\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+)
+
+// Redact is a slog.HandlerOptions.ReplaceAttr that hides sensitive attributes, and emails and
+// tokens that turn up in other values such as error messages or search queries.
+func Redact(_ []string, a slog.Attr) slog.Attr {
+	if sensitiveKeys[strings.ToLower(a.Key)] {
+		return slog.String(a.Key, redacted)
+	}
+
+	switch a.Value.Kind() {
+	case slog.KindString:
+		if s := a.Value.String(); needsRedacting(s) {
+			return slog.String(a.Key, redactString(s))
+		}
+	case slog.KindAny:
+		if err, ok := a.Value.Any().(error); ok && err != nil && needsRedacting(err.Error()) {
+			return slog.String(a.Key, redactString(err.Error()))
+		}
+	}
+
+	return a
+}
+
+// Cheap check so most values skip the regular expressions.
+func needsRedacting(s string) bool {
+	return strings.Contains(s, "@") ||
+		strings.Contains(s, models.PersonalAccessTokenPrefix) ||
+		strings.Contains(s, "eyJ") ||
+		strings.Contains(strings.ToLower(s), "bearer")
+}
+
+func redactString(s string) string {
+	s = emailPattern.ReplaceAllString(s, redacted)
+	s = tokenPattern.ReplaceAllString(s, models.PersonalAccessTokenPrefix+redacted)
+	s = bearerPattern.ReplaceAllString(s, "Bearer "+redacted)
+	s = jwtPattern.ReplaceAllString(s, redacted)
+
+	return s
+}
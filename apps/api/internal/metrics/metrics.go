@@ -0,0 +1,113 @@
+// Package metrics holds the API's Prometheus metrics. They are served from their own listen
+// address so they are never reachable through the public API.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v5"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "terraforge"
+
+// Registry holds every metric served by Handler. Collectors that need a live dependency, such
+// as the database pool, are registered on it when the server is built.
+var Registry = prometheus.NewRegistry()
+
+var factory = promauto.With(Registry)
+
+var (
+	HTTPRequestDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Time taken to serve HTTP requests, by route pattern and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	CacheRequests = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "cache",
+		Name:      "requests_total",
+		Help:      "Cache lookups by cache and result: hit, stale, miss or error.",
+	}, []string{"cache", "result"})
+
+	RateLimitDecisions = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "rate_limit",
+		Name:      "decisions_total",
+		Help:      "Rate limited requests by limit namespace and decision: allowed or denied.",
+	}, []string{"namespace", "decision"})
+
+	SearchDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "search",
+		Name:      "duration_seconds",
+		Help:      "Time taken by search queries, by index and outcome.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"index", "outcome"})
+
+	ObjectStoreDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "object_store",
+		Name:      "operation_duration_seconds",
+		Help:      "Time taken by object store operations, by operation and outcome.",
+		// Uploads of large release files take far longer than the default buckets reach
+		Buckets: []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"operation", "outcome"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// Outcome labels an operation by whether it returned an error.
+func Outcome(err error) string {
+	if err != nil {
+		return "error"
+	}
+
+	return "ok"
+}
+
+// ObserveSince records the time since start on h, labelled with the outcome of err.
+func ObserveSince(h *prometheus.HistogramVec, label string, start time.Time, err error) {
+	h.WithLabelValues(label, Outcome(err)).Observe(time.Since(start).Seconds())
+}
+
+// Middleware times every request. Routes are labelled by their pattern so path parameters
+// don't create a series per project.
+func Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c *echo.Context) error {
+			start := time.Now()
+			err := next(c)
+
+			route := c.Path()
+			if route == "" {
+				route = "unmatched"
+			}
+
+			_, status := echo.ResolveResponseStatus(c.Response(), err)
+
+			HTTPRequestDuration.
+				WithLabelValues(c.Request().Method, route, strconv.Itoa(status)).
+				Observe(time.Since(start).Seconds())
+
+			return err
+		}
+	}
+}
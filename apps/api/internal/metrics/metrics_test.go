@@ -0,0 +1,46 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware_LabelsByRoutePattern(t *testing.T) {
+	HTTPRequestDuration.Reset()
+	e := echo.New()
+	e.Use(Middleware())
+	e.GET("/v1/projects/:identifier", func(c *echo.Context) error {
+		if c.Param("identifier") == "missing" {
+			return echo.ErrNotFound
+		}
+
+		return c.NoContent(http.StatusOK)
+	})
+
+	for _, path := range []string{"/v1/projects/a", "/v1/projects/b", "/v1/projects/missing"} {
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, 2, testutil.CollectAndCount(HTTPRequestDuration))
+	assert.Contains(t, rec.Body.String(), `terraforge_http_request_duration_seconds_count{method="GET",route="/v1/projects/:identifier",status="200"} 2`)
+	assert.Contains(t, rec.Body.String(), `terraforge_http_request_duration_seconds_count{method="GET",route="/v1/projects/:identifier",status="404"} 1`)
+}
+
+func TestHandler_ServesRegisteredMetrics(t *testing.T) {
+	CacheRequests.WithLabelValues("project", "hit").Inc()
+	rec := httptest.NewRecorder()
+
+	Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `terraforge_cache_requests_total{cache="project",result="hit"}`)
+	assert.Contains(t, rec.Body.String(), "go_goroutines")
+}
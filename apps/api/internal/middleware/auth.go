@@ -1,68 +1,86 @@
 package middleware
 
 import (
-	"net/http"
+	"context"
 	"strings"
 
 	"github.com/labstack/echo/v5"
+	"github.com/lestrrat-go/jwx/v3/jwt"
 	"github.com/terraforge-gg/terraforge/internal/auth"
-	"github.com/terraforge-gg/terraforge/internal/dto"
+	custom_errors "github.com/terraforge-gg/terraforge/internal/errors"
+	"github.com/terraforge-gg/terraforge/internal/models"
+	"github.com/terraforge-gg/terraforge/internal/utils"
 )
 
-func JWTMiddleware(v *auth.Validator) echo.MiddlewareFunc {
+// TokenAuthenticator resolves personal access tokens, which are accepted anywhere a JWT is.
+type TokenAuthenticator interface {
+	Authenticate(ctx context.Context, rawToken string) (*models.PersonalAccessToken, error)
+}
+
+// JWTMiddleware rejects unauthenticated requests. Requests already authenticated by an
+// OptionalJWTMiddleware earlier in the chain are passed through without validating again.
+func JWTMiddleware(v *auth.Validator, tokens TokenAuthenticator) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c *echo.Context) error {
+			if _, ok := utils.GetSessionUserId(c); ok {
+				return next(c)
+			}
+
 			authHeader := c.Request().Header.Get("Authorization")
 			if authHeader == "" {
-				return c.JSON(http.StatusUnauthorized, dto.ProblemDetails{
-					Title:  "Unauthorized",
-					Status: http.StatusUnauthorized,
-					Detail: "Missing authorization header.",
-				})
+				return custom_errors.ErrAuthenticationRequired.WithDetail("Missing authorization header.")
 			}
 
 			parts := strings.SplitN(authHeader, " ", 2)
 			if len(parts) != 2 || !strings.EqualFold(parts[0], "bearer") {
-				return c.JSON(http.StatusUnauthorized, dto.ProblemDetails{
-					Title:  "Unauthorized",
-					Status: http.StatusUnauthorized,
-					Detail: "Invalid authorization header format or missing token.",
-				})
+				return custom_errors.ErrInvalidAuthorization
 			}
 
 			tokenString := parts[1]
 
+			if tokens != nil && strings.HasPrefix(tokenString, models.PersonalAccessTokenPrefix) {
+				accessToken, err := tokens.Authenticate(c.Request().Context(), tokenString)
+
+				if err != nil {
+					return custom_errors.ErrInvalidToken
+				}
+
+				setAccessTokenClaims(c, accessToken)
+
+				return next(c)
+			}
+
 			token, err := v.ValidateToken(tokenString)
 
 			if err != nil {
-				return c.JSON(http.StatusUnauthorized, dto.ProblemDetails{
-					Title:  "Unauthorized",
-					Status: http.StatusUnauthorized,
-					Detail: "Invalid token.",
-				})
+				return custom_errors.ErrInvalidToken
 			}
 
 			var id string
 			err = token.Get("id", &id)
 
 			if err != nil {
-				return c.JSON(http.StatusUnauthorized, dto.ProblemDetails{
-					Title:  "Unauthorized",
-					Status: http.StatusUnauthorized,
-					Detail: "Invalid token.",
-				})
+				return custom_errors.ErrInvalidToken
+			}
+
+			if tokenBanned(token) {
+				return custom_errors.ErrAccountBanned
 			}
 
-			c.Set("userId", id)
+			setTokenClaims(c, id, token)
 
 			return next(c)
 		}
 	}
 }
 
-func OptionalJWTMiddleware(v *auth.Validator) echo.MiddlewareFunc {
+func OptionalJWTMiddleware(v *auth.Validator, tokens TokenAuthenticator) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c *echo.Context) error {
+			if _, ok := utils.GetSessionUserId(c); ok {
+				return next(c)
+			}
+
 			authHeader := c.Request().Header.Get("Authorization")
 			if authHeader == "" {
 				return next(c)
@@ -73,18 +91,70 @@ func OptionalJWTMiddleware(v *auth.Validator) echo.MiddlewareFunc {
 				return next(c)
 			}
 
+			if tokens != nil && strings.HasPrefix(parts[1], models.PersonalAccessTokenPrefix) {
+				accessToken, err := tokens.Authenticate(c.Request().Context(), parts[1])
+				if err != nil {
+					return next(c)
+				}
+
+				setAccessTokenClaims(c, accessToken)
+				return next(c)
+			}
+
 			token, err := v.ValidateToken(parts[1])
 			if err != nil {
 				return next(c)
 			}
 
 			var id string
-			if err = token.Get("id", &id); err != nil {
+			if err = token.Get("id", &id); err != nil || tokenBanned(token) {
 				return next(c)
 			}
 
-			c.Set("userId", id)
+			setTokenClaims(c, id, token)
 			return next(c)
 		}
 	}
 }
+
+// Surfaces the session claims of a validated JWT into the request context.
+func setTokenClaims(c *echo.Context, userId string, token jwt.Token) {
+	var sessionId string
+	_ = token.Get("sid", &sessionId)
+
+	var emailVerified bool
+	_ = token.Get("emailVerified", &emailVerified)
+
+	var testAccount bool
+	_ = token.Get("testAccount", &testAccount)
+
+	c.Set("userId", userId)
+	c.Set("userRole", tokenRole(token))
+	c.Set("sessionId", sessionId)
+	c.Set("emailVerified", emailVerified)
+	c.Set("testAccount", testAccount)
+}
+
+// Surfaces a personal access token and its owner's account state into the request context.
+func setAccessTokenClaims(c *echo.Context, accessToken *models.PersonalAccessToken) {
+	c.Set("userId", accessToken.UserId)
+	c.Set("accessToken", accessToken)
+	c.Set("emailVerified", accessToken.UserEmailVerified)
+	c.Set("testAccount", accessToken.UserTestAccount)
+}
+
+// Reads the platform role claim. Tokens without one, or with an unknown role, belong to regular users.
+func tokenRole(token jwt.Token) models.UserRole {
+	var role string
+
+	if err := token.Get("role", &role); err != nil || !models.UserRole(role).IsValid() {
+		return models.UserRoleUser
+	}
+
+	return models.UserRole(role)
+}
+
+func tokenBanned(token jwt.Token) bool {
+	var banned bool
+	return token.Get("banned", &banned) == nil && banned
+}
@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/labstack/echo/v5"
+)
+
+// Parses proxy addresses in CIDR notation, or single IPs, from cidrs and from the lines of
+// filename, such as Cloudflare's published ranges. Blank lines and lines starting with # are
+// skipped. Every invalid entry is reported at once.
+func LoadTrustedProxies(cidrs []string, filename string) ([]*net.IPNet, error) {
+	entries := append([]string{}, cidrs...)
+
+	if filename != "" {
+		f, err := os.Open(filename)
+
+		if err != nil {
+			return nil, err
+		}
+
+		defer f.Close()
+
+		scanner := bufio.NewScanner(f)
+
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+
+			if line != "" && !strings.HasPrefix(line, "#") {
+				entries = append(entries, line)
+			}
+		}
+
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("read %s: %w", filename, err)
+		}
+	}
+
+	var errs []error
+	proxies := make([]*net.IPNet, 0, len(entries))
+
+	for _, entry := range entries {
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+
+			if ip == nil {
+				errs = append(errs, fmt.Errorf("invalid trusted proxy %q", entry))
+				continue
+			}
+
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+
+			proxies = append(proxies, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, ipNet, err := net.ParseCIDR(entry)
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid trusted proxy %q", entry))
+			continue
+		}
+
+		proxies = append(proxies, ipNet)
+	}
+
+	return proxies, errors.Join(errs...)
+}
+
+// Returns the extractor behind c.RealIP(). X-Forwarded-For is only believed when it was
+// added by a trusted proxy: walking back from the connecting peer, the first address that is
+// not a trusted proxy is the client. With no trusted proxies the peer address is used, so
+// clients cannot pick their own IP by sending the header.
+func NewIPExtractor(trustedProxies []*net.IPNet) echo.IPExtractor {
+	if len(trustedProxies) == 0 {
+		return echo.ExtractIPDirect()
+	}
+
+	// Echo trusts private and loopback addresses by default, only the configured ranges are trusted here
+	options := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+
+	for _, proxy := range trustedProxies {
+		options = append(options, echo.TrustIPRange(proxy))
+	}
+
+	return echo.ExtractIPFromXFFHeader(options...)
+}
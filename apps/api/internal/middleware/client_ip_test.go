@@ -0,0 +1,129 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func realIP(t *testing.T, extractor echo.IPExtractor, remoteAddr string, forwardedFor ...string) string {
+	t.Helper()
+
+	e := echo.New()
+	e.IPExtractor = extractor
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = remoteAddr
+
+	for _, value := range forwardedFor {
+		req.Header.Add(echo.HeaderXForwardedFor, value)
+	}
+
+	return e.NewContext(req, httptest.NewRecorder()).RealIP()
+}
+
+func TestIPExtractor_IgnoresHeadersWithoutTrustedProxies(t *testing.T) {
+	extractor := NewIPExtractor(nil)
+
+	assert.Equal(t, "203.0.113.7", realIP(t, extractor, "203.0.113.7:1234", "198.51.100.1"))
+	// Private peers are not trusted implicitly either
+	assert.Equal(t, "10.0.0.2", realIP(t, extractor, "10.0.0.2:1234", "198.51.100.1"))
+}
+
+func TestIPExtractor_TrustedProxies(t *testing.T) {
+	proxies, err := LoadTrustedProxies([]string{"10.0.0.0/8", "2001:db8::1"}, "")
+	require.NoError(t, err)
+	extractor := NewIPExtractor(proxies)
+
+	cases := map[string]struct {
+		remoteAddr   string
+		forwardedFor []string
+		want         string
+	}{
+		"direct client": {
+			remoteAddr: "203.0.113.7:1234",
+			want:       "203.0.113.7",
+		},
+		"spoofed header from untrusted peer": {
+			remoteAddr:   "203.0.113.7:1234",
+			forwardedFor: []string{"198.51.100.1"},
+			want:         "203.0.113.7",
+		},
+		"through trusted proxy": {
+			remoteAddr:   "10.1.2.3:1234",
+			forwardedFor: []string{"198.51.100.1"},
+			want:         "198.51.100.1",
+		},
+		"spoofed entry before the proxy's": {
+			remoteAddr:   "10.1.2.3:1234",
+			forwardedFor: []string{"192.0.2.99, 198.51.100.1"},
+			want:         "198.51.100.1",
+		},
+		"chain of trusted proxies": {
+			remoteAddr:   "10.1.2.3:1234",
+			forwardedFor: []string{"198.51.100.1", "10.9.9.9"},
+			want:         "198.51.100.1",
+		},
+		"trusted ipv6 proxy": {
+			remoteAddr:   "[2001:db8::1]:1234",
+			forwardedFor: []string{"2001:db8::beef"},
+			want:         "2001:db8::beef",
+		},
+		"untrusted address in the same ipv6 network": {
+			remoteAddr:   "[2001:db8::2]:1234",
+			forwardedFor: []string{"198.51.100.1"},
+			want:         "2001:db8::2",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, realIP(t, extractor, tc.remoteAddr, tc.forwardedFor...))
+		})
+	}
+}
+
+func TestLoadTrustedProxies_File(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "cloudflare-ips.txt")
+	require.NoError(t, os.WriteFile(filename, []byte("# Cloudflare\n173.245.48.0/20\n\n2400:cb00::/32\n"), 0o644))
+
+	proxies, err := LoadTrustedProxies([]string{"192.0.2.10"}, filename)
+
+	require.NoError(t, err)
+	require.Len(t, proxies, 3)
+	assert.True(t, proxies[1].Contains(net.ParseIP("173.245.48.1")))
+	assert.True(t, proxies[2].Contains(net.ParseIP("2400:cb00::1")))
+	assert.Equal(t, "173.245.50.1", realIP(t, NewIPExtractor(proxies), "173.245.48.5:443", "173.245.50.1"))
+}
+
+func TestLoadTrustedProxies_Invalid(t *testing.T) {
+	_, err := LoadTrustedProxies([]string{"10.0.0.0/33", "not-an-ip", "10.0.0.1"}, "")
+
+	assert.ErrorContains(t, err, `"10.0.0.0/33"`)
+	assert.ErrorContains(t, err, `"not-an-ip"`)
+
+	_, err = LoadTrustedProxies(nil, filepath.Join(t.TempDir(), "missing.txt"))
+
+	assert.Error(t, err)
+}
+
+func TestRateLimits_IgnoreSpoofedForwardedFor(t *testing.T) {
+	r := newTestRateLimits(RateLimitOptions{})
+	e := echo.New()
+	e.IPExtractor = NewIPExtractor(nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "203.0.113.7:1234"
+	req.Header.Set(echo.HeaderXForwardedFor, "198.51.100.1")
+
+	identity := r.identify(e.NewContext(req, httptest.NewRecorder()))
+
+	assert.Equal(t, "ip:203.0.113.7", identity.key)
+}
@@ -1,104 +1,399 @@
 package middleware
 
 import (
+	"context"
 	"fmt"
-	"net/http"
+	"slices"
 	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/labstack/echo/v5"
 	"github.com/redis/go-redis/v9"
 	"github.com/terraforge-gg/terraforge/internal/dto"
+	custom_errors "github.com/terraforge-gg/terraforge/internal/errors"
+	"github.com/terraforge-gg/terraforge/internal/metrics"
+	"github.com/terraforge-gg/terraforge/internal/models"
+	"github.com/terraforge-gg/terraforge/internal/utils"
 )
 
+// RateLimiterConfig describes a limit. Requests is used for tiers without a quota for the namespace.
 type RateLimiterConfig struct {
-	Requests  int
-	Window    time.Duration
-	Namespace string
+	Requests  int           `yaml:"requests"`
+	Window    time.Duration `yaml:"window"`
+	Namespace string        `yaml:"namespace"`
 }
 
 var (
 	RateLimitGeneral = RateLimiterConfig{Requests: 100, Window: time.Minute, Namespace: "general"}
 	RateLimitWrite   = RateLimiterConfig{Requests: 30, Window: time.Minute, Namespace: "write"}
 	RateLimitSearch  = RateLimiterConfig{Requests: 60, Window: time.Minute, Namespace: "search"}
+
+	rateLimitUserCeiling = RateLimiterConfig{Window: time.Minute, Namespace: "ceiling"}
+)
+
+type RateLimitTier string
+
+const (
+	RateLimitTierAnonymous         RateLimitTier = "anonymous"
+	RateLimitTierUser              RateLimitTier = "user"
+	RateLimitTierVerifiedPublisher RateLimitTier = "verified_publisher"
+	RateLimitTierTrustedPartner    RateLimitTier = "trusted_partner"
 )
 
+type RateLimitOptions struct {
+	// Requests per window by tier, then by namespace
+	Quotas map[RateLimitTier]map[string]int
+	// Requests per minute a single user may make across every limiter, session and token. 0 disables it.
+	UserCeiling       int
+	TrustedPartnerIds []string
+}
+
+// Keys the configured quotas, which are read by tier name, by tier.
+func RateLimitQuotas(tiers map[string]map[string]int) map[RateLimitTier]map[string]int {
+	quotas := make(map[RateLimitTier]map[string]int, len(tiers))
+
+	for tier, namespaces := range tiers {
+		quotas[RateLimitTier(tier)] = namespaces
+	}
+
+	return quotas
+}
+
+// RateLimits applies the rate limit policies, which can be swapped while serving requests,
+// with the quota of the caller's tier and the per user ceiling.
+type RateLimits struct {
+	rdb             *redis.Client
+	opts            RateLimitOptions
+	policies        atomic.Pointer[RateLimitPolicies]
+	fallbacks       sync.Map
+	ceilingFallback *localRateLimiter
+}
+
+func NewRateLimits(rdb *redis.Client, opts RateLimitOptions, policies *RateLimitPolicies) *RateLimits {
+	r := &RateLimits{
+		rdb:             rdb,
+		opts:            opts,
+		ceilingFallback: newLocalRateLimiter(rateLimitUserCeiling),
+	}
+
+	r.SetPolicies(policies)
+
+	return r
+}
+
+// The in memory limiter used for a limit while Redis is down. Keyed by window too, so
+// reloaded policies that change a window start counting afresh.
+func (r *RateLimits) fallback(cfg RateLimiterConfig) *localRateLimiter {
+	key := cfg.Namespace + ":" + cfg.Window.String()
+
+	if l, ok := r.fallbacks.Load(key); ok {
+		return l.(*localRateLimiter)
+	}
+
+	l, _ := r.fallbacks.LoadOrStore(key, newLocalRateLimiter(cfg))
+	return l.(*localRateLimiter)
+}
+
+// Who a request is limited as. Requests made with a personal access token are limited per
+// token, sessions per user and anonymous requests per IP.
+type rateLimitIdentity struct {
+	key    string
+	userId string
+	tier   RateLimitTier
+}
+
+func (r *RateLimits) identify(c *echo.Context) rateLimitIdentity {
+	userId, ok := utils.GetSessionUserId(c)
+
+	if !ok {
+		return rateLimitIdentity{key: "ip:" + c.RealIP(), tier: RateLimitTierAnonymous}
+	}
+
+	identity := rateLimitIdentity{key: "user:" + userId, userId: userId, tier: RateLimitTierUser}
+	token, isToken := utils.GetSessionAccessToken(c)
+
+	if isToken {
+		identity.key = "token:" + token.Id
+	}
+
+	switch {
+	case slices.Contains(r.opts.TrustedPartnerIds, userId) || utils.GetSessionUserRole(c).AtLeast(models.UserRoleModerator):
+		identity.tier = RateLimitTierTrustedPartner
+	case utils.GetSessionEmailVerified(c):
+		identity.tier = RateLimitTierVerifiedPublisher
+	}
+
+	return identity
+}
+
+func (r *RateLimits) quota(tier RateLimitTier, cfg RateLimiterConfig) int {
+	if requests, ok := r.opts.Quotas[tier][cfg.Namespace]; ok {
+		return requests
+	}
+
+	return cfg.Requests
+}
+
 const slidingWindowScript = `
 local key = KEYS[1]
 local now = tonumber(ARGV[1])
 local window = tonumber(ARGV[2])
 local limit = tonumber(ARGV[3])
+local cost = tonumber(ARGV[4])
+local requestId = ARGV[5]
 local clearBefore = now - window
 
 redis.call('ZREMRANGEBYSCORE', key, 0, clearBefore)
 local count = redis.call('ZCARD', key)
+local allowed = 0
 
-if count < limit then
-    redis.call('ZADD', key, now, now .. ':' .. math.random(1000000))
-    redis.call('EXPIRE', key, math.ceil(window / 1000000000) + 1)
-    return {1, limit - count - 1}
-else
-    local oldest = redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')
-    local resetAt = now + window
-    if #oldest > 0 then
-        resetAt = tonumber(oldest[2]) + window
+if count + cost <= limit then
+    for i = 1, cost do
+        redis.call('ZADD', key, now, requestId .. ':' .. i)
     end
-    return {0, resetAt}
+    redis.call('EXPIRE', key, math.ceil(window / 1000000000) + 1)
+    count = count + cost
+    allowed = 1
 end
+
+local oldest = redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')
+local resetAt = now + window
+if #oldest > 0 then
+    resetAt = tonumber(oldest[2]) + window
+end
+
+return {allowed, math.max(limit - count, 0), resetAt}
 `
 
 var slidingWindowRateLimiter = redis.NewScript(slidingWindowScript)
 
-func RateLimiter(rdb *redis.Client, cfg RateLimiterConfig) echo.MiddlewareFunc {
+const slidingWindowPeekScript = `
+local key = KEYS[1]
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+
+local count = redis.call('ZCOUNT', key, now - window, '+inf')
+local oldest = redis.call('ZRANGEBYSCORE', key, now - window, '+inf', 'WITHSCORES', 'LIMIT', 0, 1)
+local resetAt = now
+if #oldest > 0 then
+    resetAt = tonumber(oldest[2]) + window
+end
+
+return {math.max(limit - count, 0), resetAt}
+`
+
+var slidingWindowPeek = redis.NewScript(slidingWindowPeekScript)
+
+type rateLimitTake struct {
+	allowed   bool
+	remaining int64
+	resetAt   int64
+	// Gives the requests back, nil when none were taken
+	refund func(ctx context.Context)
+}
+
+// Takes cost requests from the window at key on behalf of requestId, which names the
+// entries so they can be refunded.
+func (r *RateLimits) take(ctx context.Context, key string, cfg RateLimiterConfig, limit int, cost int, fallback *localRateLimiter, now int64, requestId string) rateLimitTake {
+	result, err := slidingWindowRateLimiter.Run(
+		ctx, r.rdb,
+		[]string{key}, now, cfg.Window.Nanoseconds(), limit, cost, requestId,
+	).Result()
+
+	if err != nil {
+		// Redis is unavailable, keep limiting per instance rather than not at all
+		allowed, remaining, resetAt := fallback.allow(key, int64(limit), int64(cost), now)
+		take := rateLimitTake{allowed: allowed, remaining: remaining, resetAt: resetAt}
+
+		if allowed {
+			take.refund = func(ctx context.Context) {
+				fallback.refund(key, int64(cost), resetAt)
+			}
+		}
+
+		return take
+	}
+
+	vals := result.([]interface{})
+	take := rateLimitTake{allowed: vals[0].(int64) == 1, remaining: vals[1].(int64), resetAt: vals[2].(int64)}
+
+	if take.allowed {
+		take.refund = func(ctx context.Context) {
+			members := make([]any, cost)
+			for i := range members {
+				members[i] = requestId + ":" + strconv.Itoa(i+1)
+			}
+
+			// Best effort, a failed refund only leaves the requests counted until the window passes
+			_ = r.rdb.ZRem(ctx, key, members...).Err()
+		}
+	}
+
+	return take
+}
+
+// Reports the remaining requests and reset time at key without taking any.
+func (r *RateLimits) peek(ctx context.Context, key string, cfg RateLimiterConfig, limit int, fallback *localRateLimiter, now int64) (int64, int64) {
+	result, err := slidingWindowPeek.Run(
+		ctx, r.rdb,
+		[]string{key}, now, cfg.Window.Nanoseconds(), limit,
+	).Result()
+
+	if err != nil {
+		return fallback.peek(key, int64(limit), now)
+	}
+
+	vals := result.([]interface{})
+	return vals[0].(int64), vals[1].(int64)
+}
+
+func rateLimitKey(namespace string, identity string) string {
+	return "ratelimit:" + namespace + ":" + identity
+}
+
+// Middleware limits every request by the policies it matches. It must run after routing, so
+// policies can match the route pattern, and after the auth middleware so requests are
+// limited by who made them. A request denied by any limit is refunded to the limits it
+// was already taken from, so rejected requests do not use up the caller's other limits.
+func (r *RateLimits) Middleware() echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c *echo.Context) error {
-			key := buildKey(c, cfg.Namespace)
-
+			ctx := c.Request().Context()
+			policies := r.policies.Load()
+			identity := r.identify(c)
 			now := time.Now().UnixNano()
-			window := cfg.Window.Nanoseconds()
+			requestId := utils.NewUUID()
+
+			var refunds []func(ctx context.Context)
+			refundTaken := func() {
+				for _, refund := range refunds {
+					refund(ctx)
+				}
+			}
 
-			result, err := slidingWindowRateLimiter.Run(
-				c.Request().Context(), rdb,
-				[]string{key}, now, window, cfg.Requests,
-			).Result()
+			if identity.userId != "" && r.opts.UserCeiling > 0 {
+				key := rateLimitKey(rateLimitUserCeiling.Namespace, identity.userId)
+				take := r.take(ctx, key, rateLimitUserCeiling, r.opts.UserCeiling, 1, r.ceilingFallback, now, requestId)
+				recordRateLimitDecision(rateLimitUserCeiling.Namespace, take.allowed)
 
-			if err != nil {
-				return next(c)
+				if !take.allowed {
+					setRateLimitHeaders(c, r.opts.UserCeiling, 0, take.resetAt, now)
+					return tooManyRequests(c, now, take.resetAt)
+				}
+
+				refunds = append(refunds, take.refund)
 			}
 
-			vals := result.([]interface{})
+			applied := policies.match(c.Request().Method, c.Path())
+			policyHeader := make([]string, 0, len(applied))
+			var tightest *rateLimitResult
 
-			allowed := vals[0].(int64) == 1
+			for _, a := range applied {
+				limit := r.quota(identity.tier, a.limit)
+				key := rateLimitKey(a.limit.Namespace, identity.key)
+				take := r.take(ctx, key, a.limit, limit, a.cost, r.fallback(a.limit), now, requestId)
+				recordRateLimitDecision(a.limit.Namespace, take.allowed)
 
-			c.Response().Header().Set("X-RateLimit-Limit", strconv.Itoa(cfg.Requests))
-			c.Response().Header().Set("X-RateLimit-Window", cfg.Window.String())
+				policyHeader = append(policyHeader, fmt.Sprintf("%d;w=%d;name=%q", limit, int64(a.limit.Window.Seconds()), a.limit.Namespace))
 
-			if allowed {
-				remaining := vals[1].(int64)
-				c.Response().Header().Set("X-RateLimit-Remaining", strconv.FormatInt(remaining, 10))
-				return next(c)
-			}
+				if !take.allowed {
+					refundTaken()
+					c.Response().Header().Set("RateLimit-Policy", strings.Join(policyHeader, ", "))
+					setRateLimitHeaders(c, limit, take.remaining, take.resetAt, now)
+					return tooManyRequests(c, now, take.resetAt)
+				}
 
-			resetAt := vals[1].(int64)
-			retryAfter := (resetAt - now) / int64(time.Second)
-			if retryAfter < 1 {
-				retryAfter = 1
+				refunds = append(refunds, take.refund)
+
+				if tightest == nil || take.remaining < tightest.remaining {
+					tightest = &rateLimitResult{limit: limit, remaining: take.remaining, resetAt: take.resetAt}
+				}
 			}
 
-			c.Response().Header().Set("X-RateLimit-Remaining", "0")
-			c.Response().Header().Set("Retry-After", strconv.FormatInt(retryAfter, 10))
+			if tightest != nil {
+				c.Response().Header().Set("RateLimit-Policy", strings.Join(policyHeader, ", "))
+				setRateLimitHeaders(c, tightest.limit, tightest.remaining, tightest.resetAt, now)
+			}
 
-			return c.JSON(http.StatusTooManyRequests, dto.ProblemDetails{
-				Title:  "Too Many Requests",
-				Status: http.StatusTooManyRequests,
-				Detail: fmt.Sprintf("Rate limit exceeded. Try again in %d seconds.", retryAfter),
-			})
+			return next(c)
 		}
 	}
 }
 
-func buildKey(c *echo.Context, namespace string) string {
-	identifier := c.RealIP()
-	path := c.Request().URL.Path
-	return fmt.Sprintf("ratelimit:%s:%s:%s", namespace, identifier, path)
+func recordRateLimitDecision(namespace string, allowed bool) {
+	decision := "allowed"
+	if !allowed {
+		decision = "denied"
+	}
+
+	metrics.RateLimitDecisions.WithLabelValues(namespace, decision).Inc()
+}
+
+type rateLimitResult struct {
+	limit     int
+	remaining int64
+	resetAt   int64
+}
+
+// Sets the RateLimit header fields from the IETF draft, describing the limit closest to running out.
+func setRateLimitHeaders(c *echo.Context, limit int, remaining int64, resetAt int64, now int64) {
+	reset := max((resetAt-now+int64(time.Second)-1)/int64(time.Second), 0)
+
+	c.Response().Header().Set("RateLimit-Limit", strconv.Itoa(limit))
+	c.Response().Header().Set("RateLimit-Remaining", strconv.FormatInt(remaining, 10))
+	c.Response().Header().Set("RateLimit-Reset", strconv.FormatInt(reset, 10))
+}
+
+// Reports the caller's limits without taking from them, for GET /v1/rate-limits.
+func (r *RateLimits) Status(c *echo.Context) dto.RateLimitsResponse {
+	ctx := c.Request().Context()
+	policies := r.policies.Load()
+	identity := r.identify(c)
+	now := time.Now().UnixNano()
+
+	response := dto.RateLimitsResponse{
+		Tier:     string(identity.tier),
+		Limits:   make([]dto.RateLimitResponse, 0, len(policies.Limits)),
+		Policies: make([]dto.RateLimitPolicyResponse, 0, len(policies.Policies)),
+	}
+
+	if identity.userId != "" && r.opts.UserCeiling > 0 {
+		remaining, resetAt := r.peek(ctx, rateLimitKey(rateLimitUserCeiling.Namespace, identity.userId), rateLimitUserCeiling, r.opts.UserCeiling, r.ceilingFallback, now)
+		ceiling := dto.MapToRateLimitResponse(rateLimitUserCeiling.Namespace, r.opts.UserCeiling, rateLimitUserCeiling.Window, remaining, time.Duration(resetAt-now))
+		response.UserCeiling = &ceiling
+	}
+
+	for _, cfg := range policies.Limits {
+		limit := r.quota(identity.tier, cfg)
+		remaining, resetAt := r.peek(ctx, rateLimitKey(cfg.Namespace, identity.key), cfg, limit, r.fallback(cfg), now)
+		response.Limits = append(response.Limits, dto.MapToRateLimitResponse(cfg.Namespace, limit, cfg.Window, remaining, time.Duration(resetAt-now)))
+	}
+
+	for _, policy := range policies.Policies {
+		response.Policies = append(response.Policies, dto.RateLimitPolicyResponse{
+			Namespace: policy.Namespace,
+			Route:     policy.Route,
+			Methods:   policy.Methods,
+			Cost:      policy.Cost,
+		})
+	}
+
+	return response
+}
+
+func tooManyRequests(c *echo.Context, now int64, resetAt int64) error {
+	retryAfter := (resetAt - now) / int64(time.Second)
+	if retryAfter < 1 {
+		retryAfter = 1
+	}
+
+	c.Response().Header().Set("Retry-After", strconv.FormatInt(retryAfter, 10))
+
+	return custom_errors.ErrRateLimited.
+		WithDetail(fmt.Sprintf("Rate limit exceeded. Try again in %d seconds.", retryAfter)).
+		With("retryAfter", retryAfter)
 }
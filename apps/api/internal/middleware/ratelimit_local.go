@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"sync"
+)
+
+// localRateLimiter is a fixed window limiter kept in memory, used while Redis is unavailable.
+// Counts are per instance, so across several instances clients get proportionally more requests.
+type localRateLimiter struct {
+	mu        sync.Mutex
+	window    int64
+	counters  map[string]*localCounter
+	lastSweep int64
+}
+
+type localCounter struct {
+	count   int64
+	resetAt int64
+}
+
+func newLocalRateLimiter(cfg RateLimiterConfig) *localRateLimiter {
+	return &localRateLimiter{
+		window:   cfg.Window.Nanoseconds(),
+		counters: make(map[string]*localCounter),
+	}
+}
+
+// Takes cost requests from the window at key. Returns whether they were allowed, the
+// remaining requests and when the window resets. Times are unix nanoseconds.
+func (l *localRateLimiter) allow(key string, limit int64, cost int64, now int64) (bool, int64, int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+
+	counter, ok := l.counters[key]
+	if !ok || now >= counter.resetAt {
+		counter = &localCounter{resetAt: now + l.window}
+		l.counters[key] = counter
+	}
+
+	if counter.count+cost > limit {
+		return false, max(limit-counter.count, 0), counter.resetAt
+	}
+
+	counter.count += cost
+	return true, limit - counter.count, counter.resetAt
+}
+
+// Gives back cost requests taken from the window ending at resetAt. Does nothing once that
+// window has passed, as the requests no longer count.
+func (l *localRateLimiter) refund(key string, cost int64, resetAt int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if counter, ok := l.counters[key]; ok && counter.resetAt == resetAt {
+		counter.count = max(counter.count-cost, 0)
+	}
+}
+
+// Reports the remaining requests and reset time at key without taking any.
+func (l *localRateLimiter) peek(key string, limit int64, now int64) (int64, int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	counter, ok := l.counters[key]
+	if !ok || now >= counter.resetAt {
+		return limit, now
+	}
+
+	return max(limit-counter.count, 0), counter.resetAt
+}
+
+// Drops expired counters at most once per window so memory stays bounded by active clients.
+func (l *localRateLimiter) sweep(now int64) {
+	if now-l.lastSweep < l.window {
+		return
+	}
+
+	for key, counter := range l.counters {
+		if now >= counter.resetAt {
+			delete(l.counters, key)
+		}
+	}
+
+	l.lastSweep = now
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalRateLimiter_LimitsPerWindow(t *testing.T) {
+	l := newLocalRateLimiter(RateLimiterConfig{Window: time.Minute})
+	now := time.Now().UnixNano()
+
+	allowed, remaining, _ := l.allow("a", 2, 1, now)
+	assert.True(t, allowed)
+	assert.EqualValues(t, 1, remaining)
+
+	allowed, _, _ = l.allow("a", 2, 1, now)
+	assert.True(t, allowed)
+
+	allowed, _, resetAt := l.allow("a", 2, 1, now)
+	assert.False(t, allowed)
+	assert.Equal(t, now+time.Minute.Nanoseconds(), resetAt)
+
+	// Other clients have their own counters
+	allowed, _, _ = l.allow("b", 2, 1, now)
+	assert.True(t, allowed)
+
+	allowed, _, _ = l.allow("a", 2, 1, resetAt)
+	assert.True(t, allowed)
+}
+
+func TestLocalRateLimiter_SweepsExpiredCounters(t *testing.T) {
+	l := newLocalRateLimiter(RateLimiterConfig{Window: time.Second})
+	now := time.Now().UnixNano()
+
+	l.allow("a", 2, 1, now)
+	l.allow("b", 2, 1, now)
+	l.allow("c", 2, 1, now+2*time.Second.Nanoseconds())
+
+	assert.Len(t, l.counters, 1)
+}
+
+func TestLocalRateLimiter_Cost(t *testing.T) {
+	l := newLocalRateLimiter(RateLimiterConfig{Window: time.Minute})
+	now := time.Now().UnixNano()
+
+	allowed, remaining, _ := l.allow("a", 10, 5, now)
+	assert.True(t, allowed)
+	assert.EqualValues(t, 5, remaining)
+
+	// Not enough left for another expensive request, cheap ones still fit
+	allowed, remaining, _ = l.allow("a", 10, 6, now)
+	assert.False(t, allowed)
+	assert.EqualValues(t, 5, remaining)
+
+	allowed, _, _ = l.allow("a", 10, 1, now)
+	assert.True(t, allowed)
+
+	remaining, _ = l.peek("a", 10, now)
+	assert.EqualValues(t, 4, remaining)
+}
+
+func TestLocalRateLimiter_Refund(t *testing.T) {
+	l := newLocalRateLimiter(RateLimiterConfig{Window: time.Minute})
+	now := time.Now().UnixNano()
+
+	_, _, resetAt := l.allow("key", 2, 2, now)
+	l.refund("key", 1, resetAt)
+	allowed, remaining, _ := l.allow("key", 2, 1, now)
+
+	assert.True(t, allowed)
+	assert.EqualValues(t, 0, remaining)
+
+	// Refunds for a window that has passed are ignored
+	l.allow("key", 2, 1, resetAt)
+	l.refund("key", 1, resetAt)
+
+	assert.EqualValues(t, 1, l.counters["key"].count)
+}
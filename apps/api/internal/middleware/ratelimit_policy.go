@@ -0,0 +1,307 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/http"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RateLimitPolicy takes Cost requests from the Namespace limit for every request matching
+// Route and one of Methods. A request takes from every policy it matches.
+type RateLimitPolicy struct {
+	Namespace string `yaml:"namespace"`
+	// Echo route pattern, matched with path.Match so "*" stands in for a single segment.
+	// Empty matches every route.
+	Route string `yaml:"route"`
+	// Empty matches every method
+	Methods []string `yaml:"methods"`
+	// Defaults to 1 when omitted, and must be at least 1
+	Cost int `yaml:"cost"`
+}
+
+// Defaults Cost before decoding, so an omitted cost can be told apart from an explicit 0.
+func (p *RateLimitPolicy) UnmarshalYAML(value *yaml.Node) error {
+	type plain RateLimitPolicy
+	policy := plain{Cost: 1}
+
+	if err := value.Decode(&policy); err != nil {
+		return err
+	}
+
+	*p = RateLimitPolicy(policy)
+	return nil
+}
+
+func (p RateLimitPolicy) matches(method string, route string) bool {
+	if len(p.Methods) > 0 && !slices.Contains(p.Methods, method) {
+		return false
+	}
+
+	if p.Route == "" {
+		return true
+	}
+
+	ok, _ := path.Match(p.Route, route)
+	return ok
+}
+
+type RateLimitPolicies struct {
+	Limits   []RateLimiterConfig `yaml:"limits"`
+	Policies []RateLimitPolicy   `yaml:"policies"`
+}
+
+// The limits used when no policy file is configured. Writes and searches draw from their own
+// limits on top of the general one, presigned upload urls are expensive so they cost more.
+func DefaultRateLimitPolicies() *RateLimitPolicies {
+	return &RateLimitPolicies{
+		Limits: []RateLimiterConfig{RateLimitGeneral, RateLimitWrite, RateLimitSearch},
+		Policies: []RateLimitPolicy{
+			{Namespace: "general", Cost: 1},
+			{Namespace: "write", Methods: []string{http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}, Cost: 1},
+			{Namespace: "write", Route: "/v1/projects/:identifier/releases/upload-url", Methods: []string{http.MethodGet}, Cost: 5},
+			{Namespace: "search", Route: "/v1/projects", Methods: []string{http.MethodGet}, Cost: 1},
+			{Namespace: "search", Route: "/v1/users", Methods: []string{http.MethodGet}, Cost: 1},
+		},
+	}
+}
+
+func (p *RateLimitPolicies) limit(namespace string) (RateLimiterConfig, bool) {
+	for _, limit := range p.Limits {
+		if limit.Namespace == namespace {
+			return limit, true
+		}
+	}
+
+	return RateLimiterConfig{}, false
+}
+
+// A limit and the total cost of the policies taking from it.
+type appliedRateLimit struct {
+	limit RateLimiterConfig
+	cost  int
+}
+
+// Returns the limits a request takes from, in the order they are declared.
+func (p *RateLimitPolicies) match(method string, route string) []appliedRateLimit {
+	var applied []appliedRateLimit
+
+	for _, limit := range p.Limits {
+		cost := 0
+
+		for _, policy := range p.Policies {
+			if policy.Namespace == limit.Namespace && policy.matches(method, route) {
+				cost += policy.Cost
+			}
+		}
+
+		if cost > 0 {
+			applied = append(applied, appliedRateLimit{limit: limit, cost: cost})
+		}
+	}
+
+	return applied
+}
+
+// Normalises methods and reports every problem at once so a bad file can be fixed in one go.
+func (p *RateLimitPolicies) validate() error {
+	var errs []error
+	namespaces := make(map[string]bool, len(p.Limits))
+
+	for i, limit := range p.Limits {
+		if limit.Namespace == "" {
+			errs = append(errs, fmt.Errorf("limits[%d]: namespace is required", i))
+		}
+
+		if namespaces[limit.Namespace] {
+			errs = append(errs, fmt.Errorf("limits[%d]: duplicate namespace %q", i, limit.Namespace))
+		}
+
+		if limit.Requests < 1 {
+			errs = append(errs, fmt.Errorf("limits[%d]: requests must be at least 1", i))
+		}
+
+		if limit.Window <= 0 {
+			errs = append(errs, fmt.Errorf("limits[%d]: window must be positive", i))
+		}
+
+		namespaces[limit.Namespace] = true
+	}
+
+	for i := range p.Policies {
+		policy := &p.Policies[i]
+
+		if !namespaces[policy.Namespace] {
+			errs = append(errs, fmt.Errorf("policies[%d]: unknown namespace %q", i, policy.Namespace))
+		}
+
+		if _, err := path.Match(policy.Route, ""); err != nil {
+			errs = append(errs, fmt.Errorf("policies[%d]: invalid route %q: %w", i, policy.Route, err))
+		}
+
+		for j, method := range policy.Methods {
+			policy.Methods[j] = strings.ToUpper(method)
+		}
+
+		// A policy costing nothing would silently limit nothing, which is never what was meant
+		if policy.Cost < 1 {
+			errs = append(errs, fmt.Errorf("policies[%d]: cost must be at least 1", i))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	costs := p.maxCosts()
+
+	for i, limit := range p.Limits {
+		if cost := costs[limit.Namespace]; limit.Requests < cost {
+			errs = append(errs, fmt.Errorf("limits[%d]: requests %d is below the cost %d of a single request", i, limit.Requests, cost))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Reports every tier quota below the cost of a single request, which would deny that request
+// to the tier forever.
+func (p *RateLimitPolicies) CheckQuotas(quotas map[RateLimitTier]map[string]int) error {
+	var errs []error
+	costs := p.maxCosts()
+
+	for _, tier := range slices.Sorted(maps.Keys(quotas)) {
+		for _, namespace := range slices.Sorted(maps.Keys(quotas[tier])) {
+			if _, ok := p.limit(namespace); !ok {
+				continue
+			}
+
+			if requests, cost := quotas[tier][namespace], costs[namespace]; requests < cost {
+				errs = append(errs, fmt.Errorf("%s quota for %s is %d, below the cost %d of a single request", tier, namespace, requests, cost))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// The most a single request can take from each namespace. Routes are patterns, so every
+// policy's route is tried with every method a policy names, and an unlisted route stands in
+// for requests only the catch all policies match.
+func (p *RateLimitPolicies) maxCosts() map[string]int {
+	methods := []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+	routes := []string{""}
+
+	for _, policy := range p.Policies {
+		for _, method := range policy.Methods {
+			if !slices.Contains(methods, method) {
+				methods = append(methods, method)
+			}
+		}
+
+		if !slices.Contains(routes, policy.Route) {
+			routes = append(routes, policy.Route)
+		}
+	}
+
+	costs := make(map[string]int, len(p.Limits))
+
+	for _, route := range routes {
+		for _, method := range methods {
+			for _, applied := range p.match(method, route) {
+				costs[applied.limit.Namespace] = max(costs[applied.limit.Namespace], applied.cost)
+			}
+		}
+	}
+
+	return costs
+}
+
+// Reads a YAML policy file. An empty path gives the default policies.
+func LoadRateLimitPolicies(filename string) (*RateLimitPolicies, error) {
+	if filename == "" {
+		return DefaultRateLimitPolicies(), nil
+	}
+
+	b, err := os.ReadFile(filename)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var policies RateLimitPolicies
+
+	if err := yaml.Unmarshal(b, &policies); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", filename, err)
+	}
+
+	if err := policies.validate(); err != nil {
+		return nil, fmt.Errorf("invalid rate limit policies in %s: %w", filename, err)
+	}
+
+	return &policies, nil
+}
+
+// Swaps in new policies. Requests already being limited finish with the old ones.
+func (r *RateLimits) SetPolicies(policies *RateLimitPolicies) {
+	r.policies.Store(policies)
+}
+
+// Reloads the policy file whenever its modification time changes, until ctx is done.
+// A file that fails to load is logged and the current policies are kept.
+func (r *RateLimits) WatchPolicies(ctx context.Context, logger *slog.Logger, filename string, interval time.Duration) {
+	if filename == "" {
+		return
+	}
+
+	var modTime time.Time
+
+	if info, err := os.Stat(filename); err == nil {
+		modTime = info.ModTime()
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			info, err := os.Stat(filename)
+
+			if err != nil {
+				logger.Warn("Failed to stat rate limit policies.", "file", filename, "error", err)
+				continue
+			}
+
+			if info.ModTime().Equal(modTime) {
+				continue
+			}
+
+			modTime = info.ModTime()
+			policies, err := LoadRateLimitPolicies(filename)
+
+			if err == nil {
+				err = policies.CheckQuotas(r.opts.Quotas)
+			}
+
+			if err != nil {
+				logger.Error("Failed to reload rate limit policies, keeping the current ones.", "error", err)
+				continue
+			}
+
+			r.SetPolicies(policies)
+			logger.Info("Reloaded rate limit policies.", "file", filename)
+		}
+	}
+}
@@ -0,0 +1,356 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v5"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/terraforge-gg/terraforge/internal/metrics"
+	"github.com/terraforge-gg/terraforge/internal/models"
+)
+
+// Limits backed by a Redis that cannot be reached, so the local fallback does the counting.
+func newTestRateLimits(opts RateLimitOptions) *RateLimits {
+	rdb := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1, DialTimeout: 10 * time.Millisecond})
+	return NewRateLimits(rdb, opts, DefaultRateLimitPolicies())
+}
+
+func serveLimited(e *echo.Echo, mw echo.MiddlewareFunc, method string, route string, setup func(c *echo.Context)) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, route, nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetPath(route)
+
+	if setup != nil {
+		setup(c)
+	}
+
+	err := mw(func(c *echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})(c)
+
+	// Rejections are returned as errors, which Echo renders with its error handler
+	if err != nil {
+		e.HTTPErrorHandler(c, err)
+	}
+
+	return rec
+}
+
+func TestRateLimits_Identify(t *testing.T) {
+	r := newTestRateLimits(RateLimitOptions{TrustedPartnerIds: []string{"partner"}})
+	e := echo.New()
+
+	cases := map[string]struct {
+		setup func(c *echo.Context)
+		key   string
+		tier  RateLimitTier
+	}{
+		"anonymous": {
+			setup: func(c *echo.Context) {},
+			key:   "ip:192.0.2.1",
+			tier:  RateLimitTierAnonymous,
+		},
+		"user": {
+			setup: func(c *echo.Context) { c.Set("userId", "1") },
+			key:   "user:1",
+			tier:  RateLimitTierUser,
+		},
+		"verified user": {
+			setup: func(c *echo.Context) {
+				c.Set("userId", "1")
+				c.Set("emailVerified", true)
+			},
+			key:  "user:1",
+			tier: RateLimitTierVerifiedPublisher,
+		},
+		"personal access token": {
+			setup: func(c *echo.Context) {
+				c.Set("userId", "1")
+				c.Set("emailVerified", true)
+				c.Set("accessToken", &models.PersonalAccessToken{Id: "token-1", UserId: "1"})
+			},
+			key:  "token:token-1",
+			tier: RateLimitTierVerifiedPublisher,
+		},
+		"personal access token of an unverified user": {
+			setup: func(c *echo.Context) {
+				c.Set("userId", "1")
+				c.Set("accessToken", &models.PersonalAccessToken{Id: "token-1", UserId: "1"})
+			},
+			key:  "token:token-1",
+			tier: RateLimitTierUser,
+		},
+		"trusted partner": {
+			setup: func(c *echo.Context) { c.Set("userId", "partner") },
+			key:   "user:partner",
+			tier:  RateLimitTierTrustedPartner,
+		},
+		"moderator": {
+			setup: func(c *echo.Context) {
+				c.Set("userId", "1")
+				c.Set("userRole", models.UserRoleModerator)
+			},
+			key:  "user:1",
+			tier: RateLimitTierTrustedPartner,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = "192.0.2.1:1234"
+			c := e.NewContext(req, httptest.NewRecorder())
+			tc.setup(c)
+
+			identity := r.identify(c)
+
+			assert.Equal(t, tc.key, identity.key)
+			assert.Equal(t, tc.tier, identity.tier)
+		})
+	}
+}
+
+func TestRateLimits_TierQuotas(t *testing.T) {
+	r := newTestRateLimits(RateLimitOptions{
+		Quotas: map[RateLimitTier]map[string]int{
+			RateLimitTierUser: {"general": 5},
+		},
+	})
+	mw := r.Middleware()
+	e := echo.New()
+
+	anonymous := serveLimited(e, mw, http.MethodGet, "/v1/loaders", nil)
+	user := serveLimited(e, mw, http.MethodGet, "/v1/loaders", func(c *echo.Context) { c.Set("userId", "1") })
+
+	// Namespaces without a quota for the tier fall back to the limit's default
+	assert.Equal(t, "100", anonymous.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "5", user.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, `5;w=60;name="general"`, user.Header().Get("RateLimit-Policy"))
+}
+
+func TestRateLimits_TokensHaveTheirOwnLimit(t *testing.T) {
+	r := newTestRateLimits(RateLimitOptions{
+		Quotas: map[RateLimitTier]map[string]int{
+			RateLimitTierVerifiedPublisher: {"write": 1},
+		},
+	})
+	mw := r.Middleware()
+	e := echo.New()
+	withToken := func(id string) func(c *echo.Context) {
+		return func(c *echo.Context) {
+			c.Set("userId", "1")
+			c.Set("emailVerified", true)
+			c.Set("accessToken", &models.PersonalAccessToken{Id: id, UserId: "1"})
+		}
+	}
+
+	deniedBefore := testutil.ToFloat64(metrics.RateLimitDecisions.WithLabelValues("write", "denied"))
+
+	first := serveLimited(e, mw, http.MethodPost, "/v1/projects", withToken("token-1"))
+	second := serveLimited(e, mw, http.MethodPost, "/v1/projects", withToken("token-1"))
+	otherToken := serveLimited(e, mw, http.MethodPost, "/v1/projects", withToken("token-2"))
+
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, http.StatusTooManyRequests, second.Code)
+	assert.NotEmpty(t, second.Header().Get("Retry-After"))
+	assert.Equal(t, deniedBefore+1, testutil.ToFloat64(metrics.RateLimitDecisions.WithLabelValues("write", "denied")))
+	assert.Equal(t, http.StatusOK, otherToken.Code)
+}
+
+func TestRateLimits_UserCeiling(t *testing.T) {
+	r := newTestRateLimits(RateLimitOptions{UserCeiling: 2})
+	mw := r.Middleware()
+	e := echo.New()
+	asUser := func(c *echo.Context) { c.Set("userId", "1") }
+
+	// A request matching several limits only counts once towards the ceiling
+	first := serveLimited(e, mw, http.MethodPost, "/v1/projects", asUser)
+	second := serveLimited(e, mw, http.MethodGet, "/v1/loaders", asUser)
+	third := serveLimited(e, mw, http.MethodGet, "/v1/loaders", asUser)
+	otherUser := serveLimited(e, mw, http.MethodGet, "/v1/loaders", func(c *echo.Context) { c.Set("userId", "2") })
+
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, http.StatusOK, second.Code)
+	assert.Equal(t, http.StatusTooManyRequests, third.Code)
+	assert.Equal(t, http.StatusOK, otherUser.Code)
+}
+
+func TestRateLimits_PolicyCost(t *testing.T) {
+	r := newTestRateLimits(RateLimitOptions{})
+	mw := r.Middleware()
+	e := echo.New()
+	uploadUrl := "/v1/projects/:identifier/releases/upload-url"
+
+	rec := serveLimited(e, mw, http.MethodGet, uploadUrl, nil)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "25", rec.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, `100;w=60;name="general", 30;w=60;name="write"`, rec.Header().Get("RateLimit-Policy"))
+
+	status := r.Status(e.NewContext(httptest.NewRequest(http.MethodGet, "/v1/rate-limits", nil), httptest.NewRecorder()))
+
+	assert.Equal(t, "anonymous", status.Tier)
+	assert.Nil(t, status.UserCeiling)
+	require.Len(t, status.Limits, 3)
+	assert.EqualValues(t, 99, status.Limits[0].Remaining)
+	assert.EqualValues(t, 25, status.Limits[1].Remaining)
+	assert.EqualValues(t, 60, status.Limits[2].Remaining)
+}
+
+func writePolicies(t *testing.T, filename string, contents string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filename, []byte(contents), 0o644))
+}
+
+func TestLoadRateLimitPolicies(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "rate-limits.yml")
+	writePolicies(t, filename, `
+limits:
+  - namespace: general
+    requests: 10
+    window: 1m
+policies:
+  - namespace: general
+  - namespace: general
+    route: /v1/projects/*
+    methods: [post]
+    cost: 3
+`)
+
+	policies, err := LoadRateLimitPolicies(filename)
+
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, policies.Limits[0].Window)
+	assert.Equal(t, 1, policies.Policies[0].Cost)
+	assert.Equal(t, []string{http.MethodPost}, policies.Policies[1].Methods)
+
+	applied := policies.match(http.MethodPost, "/v1/projects/:identifier")
+	require.Len(t, applied, 1)
+	assert.Equal(t, 4, applied[0].cost)
+}
+
+func TestLoadRateLimitPolicies_Invalid(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "rate-limits.yml")
+	writePolicies(t, filename, `
+limits:
+  - namespace: general
+    requests: 0
+    window: 1m
+policies:
+  - namespace: search
+    cost: -1
+  - namespace: general
+    cost: 0
+`)
+
+	_, err := LoadRateLimitPolicies(filename)
+
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "limits[0]: requests must be at least 1")
+	assert.ErrorContains(t, err, `policies[0]: unknown namespace "search"`)
+	assert.ErrorContains(t, err, "policies[0]: cost must be at least 1")
+	assert.ErrorContains(t, err, "policies[1]: cost must be at least 1")
+}
+
+func TestLoadRateLimitPolicies_RequestsBelowCost(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "rate-limits.yml")
+	writePolicies(t, filename, `
+limits:
+  - namespace: general
+    requests: 3
+    window: 1m
+policies:
+  - namespace: general
+  - namespace: general
+    route: /v1/projects/*
+    methods: [post]
+    cost: 3
+`)
+
+	_, err := LoadRateLimitPolicies(filename)
+
+	assert.ErrorContains(t, err, "limits[0]: requests 3 is below the cost 4 of a single request")
+}
+
+func TestRateLimitPolicies_CheckQuotas(t *testing.T) {
+	policies := DefaultRateLimitPolicies()
+
+	assert.NoError(t, policies.CheckQuotas(map[RateLimitTier]map[string]int{
+		RateLimitTierAnonymous: {"general": 1, "write": 5, "search": 1},
+	}))
+
+	err := policies.CheckQuotas(map[RateLimitTier]map[string]int{
+		RateLimitTierAnonymous: {"write": 3},
+		RateLimitTierUser:      {"general": 0},
+	})
+
+	// The upload url costs 5 from write
+	assert.ErrorContains(t, err, "anonymous quota for write is 3, below the cost 5 of a single request")
+	assert.ErrorContains(t, err, "user quota for general is 0, below the cost 1 of a single request")
+}
+
+func TestRateLimits_WatchPolicies(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "rate-limits.yml")
+	writePolicies(t, filename, "limits: [{namespace: general, requests: 10, window: 1m}]\npolicies: [{namespace: general}]\n")
+	policies, err := LoadRateLimitPolicies(filename)
+	require.NoError(t, err)
+
+	r := NewRateLimits(redis.NewClient(&redis.Options{Addr: "127.0.0.1:1"}), RateLimitOptions{}, policies)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go r.WatchPolicies(ctx, slog.New(slog.DiscardHandler), filename, 10*time.Millisecond)
+
+	// An invalid file keeps the current policies
+	writePolicies(t, filename, "limits: [{namespace: general, requests: 0, window: 1m}]\n")
+	require.NoError(t, os.Chtimes(filename, time.Now(), time.Now().Add(time.Second)))
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, 10, r.policies.Load().Limits[0].Requests)
+
+	writePolicies(t, filename, "limits: [{namespace: general, requests: 20, window: 1m}]\npolicies: [{namespace: general}]\n")
+	require.NoError(t, os.Chtimes(filename, time.Now(), time.Now().Add(2*time.Second)))
+
+	assert.Eventually(t, func() bool {
+		return r.policies.Load().Limits[0].Requests == 20
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestRateLimits_DeniedRequestsAreRefunded(t *testing.T) {
+	r := newTestRateLimits(RateLimitOptions{
+		UserCeiling: 10,
+		Quotas: map[RateLimitTier]map[string]int{
+			RateLimitTierUser: {"write": 1},
+		},
+	})
+	mw := r.Middleware()
+	e := echo.New()
+	asUser := func(c *echo.Context) { c.Set("userId", "1") }
+
+	first := serveLimited(e, mw, http.MethodPost, "/v1/projects", asUser)
+	second := serveLimited(e, mw, http.MethodPost, "/v1/projects", asUser)
+
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, http.StatusTooManyRequests, second.Code)
+
+	// Only the allowed request counts towards the ceiling and the general limit
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/v1/rate-limits", nil), httptest.NewRecorder())
+	asUser(c)
+	status := r.Status(c)
+
+	require.NotNil(t, status.UserCeiling)
+	assert.EqualValues(t, 9, status.UserCeiling.Remaining)
+	require.Len(t, status.Limits, 3)
+	assert.Equal(t, "general", status.Limits[0].Namespace)
+	assert.EqualValues(t, 99, status.Limits[0].Remaining)
+	assert.EqualValues(t, 0, status.Limits[1].Remaining)
+}
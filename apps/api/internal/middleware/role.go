@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"github.com/labstack/echo/v5"
+	custom_errors "github.com/terraforge-gg/terraforge/internal/errors"
+	"github.com/terraforge-gg/terraforge/internal/models"
+	"github.com/terraforge-gg/terraforge/internal/utils"
+)
+
+// RequireRole must run after JWTMiddleware so the session role is available.
+// Roles are ranked, so requiring a moderator also admits admins.
+func RequireRole(role models.UserRole) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c *echo.Context) error {
+			if !utils.GetSessionUserRole(c).AtLeast(role) {
+				return custom_errors.ErrInsufficientRole
+			}
+
+			return next(c)
+		}
+	}
+}
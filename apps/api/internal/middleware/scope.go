@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+
+	"github.com/labstack/echo/v5"
+	custom_errors "github.com/terraforge-gg/terraforge/internal/errors"
+	"github.com/terraforge-gg/terraforge/internal/models"
+	"github.com/terraforge-gg/terraforge/internal/utils"
+)
+
+// ProjectIdResolver resolves a project id or slug to the project id, as seen by userId.
+type ProjectIdResolver func(ctx context.Context, identifier string, userId string) (string, error)
+
+// RequireScope limits requests authenticated with a personal access token to tokens holding scope.
+// Project restricted tokens must also include the project in the route's :identifier param, and
+// cannot be used on routes without one. Anonymous and session requests pass through unchanged.
+func RequireScope(scope models.TokenScope, resolveProjectId ProjectIdResolver) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c *echo.Context) error {
+			token, ok := utils.GetSessionAccessToken(c)
+
+			if !ok {
+				return next(c)
+			}
+
+			if !token.HasScope(scope) {
+				return custom_errors.ErrTokenMissingScope.WithDetail("Token is missing the '"+string(scope)+"' scope.").With("scope", scope)
+			}
+
+			if len(token.ProjectIds) == 0 {
+				return next(c)
+			}
+
+			identifier := c.Param("identifier")
+
+			if identifier == "" {
+				return custom_errors.ErrTokenProjectRestricted.WithDetail("Token is restricted to specific projects.")
+			}
+
+			projectId, err := resolveProjectId(c.Request().Context(), identifier, token.UserId)
+
+			// Unknown projects are left to the handler so they return the usual not found. Any
+			// other failure is returned, the token cannot be let through without knowing the project.
+			if errors.Is(err, custom_errors.ErrProjectNotFound) {
+				return next(c)
+			}
+
+			if err != nil {
+				return err
+			}
+
+			if !token.AllowsProject(projectId) {
+				return custom_errors.ErrTokenProjectRestricted
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// SessionOnly rejects requests authenticated with a personal access token,
+// for routes such as token management that need a signed in user.
+func SessionOnly() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c *echo.Context) error {
+			if _, ok := utils.GetSessionAccessToken(c); ok {
+				return custom_errors.ErrTokenNotAllowed
+			}
+
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/assert"
+	custom_errors "github.com/terraforge-gg/terraforge/internal/errors"
+	"github.com/terraforge-gg/terraforge/internal/models"
+)
+
+// Serves a project route with a token restricted to project "p1", resolving identifiers with resolve.
+func serveScoped(t *testing.T, resolve ProjectIdResolver) (bool, error) {
+	t.Helper()
+
+	token := &models.PersonalAccessToken{
+		UserId:     "1",
+		Scopes:     []models.TokenScope{models.TokenScopeProjectWrite},
+		ProjectIds: []string{"p1"},
+	}
+
+	called := false
+	var err error
+
+	e := echo.New()
+	e.HTTPErrorHandler = func(c *echo.Context, handlerErr error) {
+		err = handlerErr
+	}
+	e.PATCH("/projects/:identifier", func(c *echo.Context) error {
+		called = true
+		return c.NoContent(http.StatusNoContent)
+	}, func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c *echo.Context) error {
+			c.Set("accessToken", token)
+			return next(c)
+		}
+	}, RequireScope(models.TokenScopeProjectWrite, resolve))
+
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPatch, "/projects/calamity", nil))
+
+	return called, err
+}
+
+func TestRequireScope_AllowedProject(t *testing.T) {
+	called, err := serveScoped(t, func(ctx context.Context, identifier string, userId string) (string, error) {
+		return "p1", nil
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, called)
+}
+
+func TestRequireScope_OtherProject(t *testing.T) {
+	called, err := serveScoped(t, func(ctx context.Context, identifier string, userId string) (string, error) {
+		return "p2", nil
+	})
+
+	assert.ErrorIs(t, err, custom_errors.ErrTokenProjectRestricted)
+	assert.False(t, called)
+}
+
+func TestRequireScope_UnknownProjectLeftToHandler(t *testing.T) {
+	called, err := serveScoped(t, func(ctx context.Context, identifier string, userId string) (string, error) {
+		return "", custom_errors.ErrProjectNotFound
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, called)
+}
+
+func TestRequireScope_ResolverFailure(t *testing.T) {
+	failure := errors.New("connection refused")
+
+	called, err := serveScoped(t, func(ctx context.Context, identifier string, userId string) (string, error) {
+		return "", failure
+	})
+
+	assert.ErrorIs(t, err, failure)
+	assert.False(t, called)
+}
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"github.com/labstack/echo/v5"
+	custom_errors "github.com/terraforge-gg/terraforge/internal/errors"
+	"github.com/terraforge-gg/terraforge/internal/utils"
+)
+
+// RequireVerifiedEmail must run after JWTMiddleware. Applies to personal access tokens too, as the
+// owner's email can become unverified after the token was created. Test accounts are exempt.
+// Passes everything through when enabled is false, so environments without email delivery still work.
+func RequireVerifiedEmail(enabled bool) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c *echo.Context) error {
+			if !enabled {
+				return next(c)
+			}
+
+			if !utils.GetSessionEmailVerified(c) && !utils.GetSessionTestAccount(c) {
+				return custom_errors.ErrEmailNotVerified
+			}
+
+			return next(c)
+		}
+	}
+}
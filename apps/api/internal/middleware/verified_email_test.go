@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/assert"
+	custom_errors "github.com/terraforge-gg/terraforge/internal/errors"
+	"github.com/terraforge-gg/terraforge/internal/models"
+)
+
+type stubTokenAuthenticator struct {
+	token *models.PersonalAccessToken
+}
+
+func (s stubTokenAuthenticator) Authenticate(ctx context.Context, rawToken string) (*models.PersonalAccessToken, error) {
+	return s.token, nil
+}
+
+// Serves a write route authenticated with a personal access token belonging to a user in the given state.
+func serveWithAccessToken(t *testing.T, emailVerified bool, testAccount bool) (bool, error) {
+	t.Helper()
+
+	tokens := stubTokenAuthenticator{token: &models.PersonalAccessToken{
+		UserId:            "1",
+		Scopes:            []models.TokenScope{models.TokenScopeProjectWrite},
+		UserEmailVerified: emailVerified,
+		UserTestAccount:   testAccount,
+	}}
+
+	called := false
+	var err error
+
+	e := echo.New()
+	e.HTTPErrorHandler = func(c *echo.Context, handlerErr error) {
+		err = handlerErr
+	}
+	e.POST("/projects", func(c *echo.Context) error {
+		called = true
+		return c.NoContent(http.StatusCreated)
+	}, JWTMiddleware(nil, tokens), RequireVerifiedEmail(true))
+
+	req := httptest.NewRequest(http.MethodPost, "/projects", nil)
+	req.Header.Set("Authorization", "Bearer "+models.PersonalAccessTokenPrefix+"secret")
+	e.ServeHTTP(httptest.NewRecorder(), req)
+
+	return called, err
+}
+
+func TestRequireVerifiedEmail_AccessTokenOfVerifiedUser(t *testing.T) {
+	called, err := serveWithAccessToken(t, true, false)
+
+	assert.NoError(t, err)
+	assert.True(t, called)
+}
+
+func TestRequireVerifiedEmail_AccessTokenOfUnverifiedUser(t *testing.T) {
+	called, err := serveWithAccessToken(t, false, false)
+
+	assert.ErrorIs(t, err, custom_errors.ErrEmailNotVerified)
+	assert.False(t, called)
+}
+
+func TestRequireVerifiedEmail_AccessTokenOfTestAccount(t *testing.T) {
+	called, err := serveWithAccessToken(t, false, true)
+
+	assert.NoError(t, err)
+	assert.True(t, called)
+}
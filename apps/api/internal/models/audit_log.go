@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+type AuditAction string
+
+const (
+	AuditActionUserBan             AuditAction = "user.ban"
+	AuditActionUserUnban           AuditAction = "user.unban"
+	AuditActionUserTestAccount     AuditAction = "user.test_account"
+	AuditActionProjectStatusChange AuditAction = "project.status_change"
+	AuditActionProjectRestore      AuditAction = "project.restore"
+)
+
+type AuditTargetType string
+
+const (
+	AuditTargetUser    AuditTargetType = "user"
+	AuditTargetProject AuditTargetType = "project"
+)
+
+// AuditLog records an administrative action, written in the same transaction as the action itself.
+type AuditLog struct {
+	Id         string
+	ActorId    *string
+	Action     AuditAction
+	TargetType AuditTargetType
+	TargetId   string
+	Metadata   map[string]any
+	CreatedAt  time.Time
+}
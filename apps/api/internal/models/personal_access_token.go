@@ -0,0 +1,48 @@
+package models
+
+import (
+	"slices"
+	"time"
+)
+
+type TokenScope string
+
+const (
+	TokenScopeProjectRead  TokenScope = "project:read"
+	TokenScopeProjectWrite TokenScope = "project:write"
+	TokenScopeReleaseWrite TokenScope = "release:write"
+)
+
+// Personal access tokens start with this prefix so they can be told apart from JWTs.
+const PersonalAccessTokenPrefix = "tf_pat_"
+
+type PersonalAccessToken struct {
+	Id          string
+	UserId      string
+	Name        string
+	TokenHash   string
+	TokenPrefix string
+	Scopes      []TokenScope
+	ProjectIds  []string
+	ExpiresAt   *time.Time
+	LastUsedAt  *time.Time
+	RevokedAt   *time.Time
+	CreatedAt   time.Time
+	// State of the owning user, loaded with the token so requests made with it are held
+	// to the same policies as the user's own session
+	UserEmailVerified bool
+	UserTestAccount   bool
+}
+
+func (t *PersonalAccessToken) HasScope(scope TokenScope) bool {
+	return slices.Contains(t.Scopes, scope)
+}
+
+// Reports whether the token may act on the project. Tokens without project ids are unrestricted.
+func (t *PersonalAccessToken) AllowsProject(projectId string) bool {
+	return len(t.ProjectIds) == 0 || slices.Contains(t.ProjectIds, projectId)
+}
+
+func (t *PersonalAccessToken) IsActive(now time.Time) bool {
+	return t.RevokedAt == nil && (t.ExpiresAt == nil || now.Before(*t.ExpiresAt))
+}
@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+type SearchIndex string
+
+const (
+	SearchIndexProjects SearchIndex = "projects"
+	SearchIndexUsers    SearchIndex = "users"
+)
+
+type SearchQuery struct {
+	Id              string
+	Index           SearchIndex
+	Query           string
+	NormalizedQuery string
+	Filters         map[string]string
+	ResultCount     int64
+	Limit           int64
+	Offset          int64
+	UserId          *string
+	CreatedAt       time.Time
+}
+
+type SearchClickType string
+
+const (
+	SearchClickTypeView     SearchClickType = "view"
+	SearchClickTypeDownload SearchClickType = "download"
+)
+
+type SearchClick struct {
+	Id            string
+	SearchQueryId string
+	ProjectId     string
+	ReleaseId     *string
+	Type          SearchClickType
+	CreatedAt     time.Time
+}
+
+type SearchQueryStat struct {
+	NormalizedQuery    string
+	Searches           int64
+	AverageResultCount float64
+	Clicks             int64
+	LastSearchedAt     time.Time
+}
+
+type SearchAnalyticsReport struct {
+	Since             time.Time
+	TopQueries        []SearchQueryStat
+	ZeroResultQueries []SearchQueryStat
+}
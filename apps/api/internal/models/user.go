@@ -2,6 +2,31 @@ package models
 
 import "time"
 
+type UserRole string
+
+const (
+	UserRoleUser      UserRole = "user"
+	UserRoleModerator UserRole = "moderator"
+	UserRoleAdmin     UserRole = "admin"
+)
+
+var userRoleRanks = map[UserRole]int{
+	UserRoleUser:      0,
+	UserRoleModerator: 1,
+	UserRoleAdmin:     2,
+}
+
+// Reports whether the role grants at least the permissions of min. Unknown roles grant nothing.
+func (r UserRole) AtLeast(min UserRole) bool {
+	rank, ok := userRoleRanks[r]
+	return ok && rank >= userRoleRanks[min]
+}
+
+func (r UserRole) IsValid() bool {
+	_, ok := userRoleRanks[r]
+	return ok
+}
+
 type User struct {
 	Id              string
 	Name            string
@@ -10,6 +35,21 @@ type User struct {
 	Email           string
 	EmailVerified   bool
 	Image           *string
+	Role            UserRole
+	Banned          bool
+	BanReason       *string
+	BannedAt        *time.Time
+	TestAccount     bool
 	CreatedAt       time.Time
 	UpdatedAt       time.Time
 }
+
+type UserStats struct {
+	ProjectCount   int64
+	TotalDownloads int64
+}
+
+type UserWithStats struct {
+	User  User
+	Stats UserStats
+}
@@ -0,0 +1,136 @@
+// Package pagination implements the opaque cursors list endpoints are paged with. A cursor
+// encodes the ordering key of the last item on a page, so the next page starts right after
+// that item however many rows were inserted or removed in the meantime.
+package pagination
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"slices"
+	"time"
+
+	custom_errors "github.com/terraforge-gg/terraforge/internal/errors"
+)
+
+const (
+	DefaultLimit int64 = 25
+	MaxLimit     int64 = 100
+)
+
+// Params selects a page: up to Limit items after the position encoded in Cursor, or the first
+// page when Cursor is empty.
+type Params struct {
+	Limit  int64
+	Cursor string
+}
+
+func (p Params) IsFirst() bool {
+	return p.Cursor == ""
+}
+
+// QueryLimit is the number of rows to query, one more than the limit so NewPage can tell
+// whether there is a next page.
+func (p Params) QueryLimit() int64 {
+	return p.Limit + 1
+}
+
+// After decodes the position the page starts after into key, leaving key unchanged for the
+// first page.
+func (p Params) After(key any) error {
+	if p.IsFirst() {
+		return nil
+	}
+
+	return Decode(p.Cursor, key)
+}
+
+type Page[T any] struct {
+	Items []T `json:"items"`
+	// Empty on the last page
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// NewPage builds a page from rows queried with Params.QueryLimit. The extra row is dropped,
+// it only shows that a next page exists, which starts after the key of the last item kept.
+func NewPage[T any](rows []T, limit int64, key func(T) any) Page[T] {
+	if rows == nil {
+		rows = []T{}
+	}
+
+	if int64(len(rows)) <= limit {
+		return Page[T]{Items: rows}
+	}
+
+	rows = rows[:limit]
+
+	return Page[T]{Items: rows, NextCursor: Encode(key(rows[len(rows)-1]))}
+}
+
+// Slice pages through a list small enough to be loaded and cached whole. The cursor is the id
+// of the last item, so it stays valid when items are added to the list.
+func Slice[T any](items []T, p Params, id func(T) string) (Page[T], error) {
+	start := 0
+
+	if !p.IsFirst() {
+		var key IdKey
+
+		if err := Decode(p.Cursor, &key); err != nil {
+			return Page[T]{}, err
+		}
+
+		i := slices.IndexFunc(items, func(item T) bool { return id(item) == key.Id })
+
+		if i < 0 {
+			return Page[T]{}, custom_errors.ErrInvalidCursor
+		}
+
+		start = i + 1
+	}
+
+	end := min(int64(start)+p.QueryLimit(), int64(len(items)))
+
+	return NewPage(items[start:end], p.Limit, func(item T) any { return IdKey{Id: id(item)} }), nil
+}
+
+// TimeKey is the position of a row in a list ordered by a timestamp, the id breaking ties.
+type TimeKey struct {
+	Time time.Time `json:"t"`
+	Id   string    `json:"id"`
+}
+
+// IdKey is the position of an item in a list paged with Slice.
+type IdKey struct {
+	Id string `json:"id"`
+}
+
+// OffsetKey is the position in search results, which are ranked by relevance rather than a
+// column a query could start after.
+type OffsetKey struct {
+	Offset int64 `json:"o"`
+}
+
+func Encode(key any) string {
+	// Keys are plain structs, which always marshal
+	b, _ := json.Marshal(key)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// Decode reads a cursor made by Encode into key. Cursors that were not, or were made for a
+// different key, are reported as custom_errors.ErrInvalidCursor.
+func Decode(cursor string, key any) error {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+
+	if err != nil {
+		return custom_errors.ErrInvalidCursor
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(key); err != nil {
+		return custom_errors.ErrInvalidCursor
+	}
+
+	return nil
+}
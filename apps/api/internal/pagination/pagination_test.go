@@ -0,0 +1,78 @@
+package pagination
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	custom_errors "github.com/terraforge-gg/terraforge/internal/errors"
+)
+
+func idOf(s string) string {
+	return s
+}
+
+func TestNewPage_DropsExtraRow(t *testing.T) {
+	page := NewPage([]string{"a", "b", "c"}, 2, func(s string) any { return IdKey{Id: s} })
+
+	assert.Equal(t, []string{"a", "b"}, page.Items)
+
+	var key IdKey
+	require.NoError(t, Decode(page.NextCursor, &key))
+	assert.Equal(t, "b", key.Id)
+}
+
+func TestNewPage_LastPage(t *testing.T) {
+	page := NewPage[string](nil, 2, func(s string) any { return IdKey{Id: s} })
+
+	assert.Equal(t, []string{}, page.Items)
+	assert.Empty(t, page.NextCursor)
+}
+
+func TestSlice_FollowsCursors(t *testing.T) {
+	items := []string{"a", "b", "c", "d", "e"}
+	var seen []string
+	params := Params{Limit: 2}
+
+	for {
+		page, err := Slice(items, params, idOf)
+		require.NoError(t, err)
+		seen = append(seen, page.Items...)
+
+		if page.NextCursor == "" {
+			break
+		}
+		params.Cursor = page.NextCursor
+	}
+
+	assert.Equal(t, items, seen)
+}
+
+func TestSlice_CursorSurvivesInsertions(t *testing.T) {
+	first, err := Slice([]string{"c", "b", "a"}, Params{Limit: 1}, idOf)
+	require.NoError(t, err)
+
+	// A newer item is listed first by the time the next page is requested
+	next, err := Slice([]string{"d", "c", "b", "a"}, Params{Limit: 1, Cursor: first.NextCursor}, idOf)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b"}, next.Items)
+}
+
+func TestDecode_RejectsInvalidCursors(t *testing.T) {
+	var key TimeKey
+
+	assert.ErrorIs(t, Decode("not a cursor", &key), custom_errors.ErrInvalidCursor)
+	assert.ErrorIs(t, Decode(Encode(OffsetKey{Offset: 10}), &key), custom_errors.ErrInvalidCursor)
+}
+
+func TestDecode_RoundTripsTimeKey(t *testing.T) {
+	want := TimeKey{Time: time.Date(2026, 3, 14, 9, 26, 53, 589793000, time.UTC), Id: "release-1"}
+
+	var got TimeKey
+	require.NoError(t, Decode(Encode(want), &got))
+
+	assert.True(t, want.Time.Equal(got.Time))
+	assert.Equal(t, want.Id, got.Id)
+}
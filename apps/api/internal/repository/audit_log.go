@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/terraforge-gg/terraforge/internal/database"
+	"github.com/terraforge-gg/terraforge/internal/models"
+	"github.com/terraforge-gg/terraforge/internal/pagination"
+)
+
+type AuditLogRepository interface {
+	InsertAuditLog(ctx context.Context, q database.Querier, auditLog *models.AuditLog) error
+	FindAuditLogs(ctx context.Context, q database.Querier, params FindAuditLogsParams) (pagination.Page[models.AuditLog], error)
+}
+
+type auditLogRepository struct{}
+
+func NewAuditLogRepository() AuditLogRepository {
+	return &auditLogRepository{}
+}
+
+func (r *auditLogRepository) InsertAuditLog(ctx context.Context, q database.Querier, auditLog *models.AuditLog) error {
+	query := `INSERT INTO "audit_log"
+		("id", "actorId", "action", "targetType", "targetId", "metadata", "createdAt")
+		VALUES ($1, $2, $3, $4, $5, $6, $7);`
+
+	metadata, err := json.Marshal(auditLog.Metadata)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = q.ExecContext(
+		ctx,
+		query,
+		auditLog.Id,
+		auditLog.ActorId,
+		auditLog.Action,
+		auditLog.TargetType,
+		auditLog.TargetId,
+		metadata,
+		auditLog.CreatedAt)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+type FindAuditLogsParams struct {
+	TargetType *models.AuditTargetType
+	TargetId   *string
+	Page       pagination.Params
+}
+
+// Lists audit logs newest first, optionally for a single target.
+func (r *auditLogRepository) FindAuditLogs(ctx context.Context, q database.Querier, params FindAuditLogsParams) (pagination.Page[models.AuditLog], error) {
+	var after pagination.TimeKey
+
+	if err := params.Page.After(&after); err != nil {
+		return pagination.Page[models.AuditLog]{}, err
+	}
+
+	query := `
+		SELECT
+			"id",
+			"actorId",
+			"action",
+			"targetType",
+			"targetId",
+			"metadata",
+			"createdAt"
+		FROM "audit_log"
+		WHERE ($1::TEXT IS NULL OR "targetType" = $1)
+			AND ($2::TEXT IS NULL OR "targetId" = $2)
+			AND ($4 = '' OR ("createdAt", "id") < ($3, $4))
+		ORDER BY "createdAt" DESC, "id" DESC
+		LIMIT $5;`
+
+	rows, err := q.QueryContext(ctx, query, params.TargetType, params.TargetId, after.Time, after.Id, params.Page.QueryLimit())
+
+	if err != nil {
+		return pagination.Page[models.AuditLog]{}, err
+	}
+
+	defer rows.Close()
+
+	auditLogs := []models.AuditLog{}
+
+	for rows.Next() {
+		var a models.AuditLog
+		var metadata []byte
+
+		err := rows.Scan(
+			&a.Id,
+			&a.ActorId,
+			&a.Action,
+			&a.TargetType,
+			&a.TargetId,
+			&metadata,
+			&a.CreatedAt)
+
+		if err != nil {
+			return pagination.Page[models.AuditLog]{}, err
+		}
+
+		if err := json.Unmarshal(metadata, &a.Metadata); err != nil {
+			return pagination.Page[models.AuditLog]{}, err
+		}
+
+		auditLogs = append(auditLogs, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return pagination.Page[models.AuditLog]{}, err
+	}
+
+	return pagination.NewPage(auditLogs, params.Page.Limit, func(a models.AuditLog) any {
+		return pagination.TimeKey{Time: a.CreatedAt, Id: a.Id}
+	}), nil
+}
@@ -37,7 +37,7 @@ func (r *loaderVersionRepository) FindLoaderVersionById(ctx context.Context, q d
 
 	lv := &models.LoaderVersion{}
 
-	err := q.QueryRow(query, id).Scan(
+	err := q.QueryRowContext(ctx, query, id).Scan(
 		&lv.Id,
 		&lv.GameVersion,
 		&lv.VersionLabel,
@@ -71,7 +71,7 @@ func (r *loaderVersionRepository) FindLoaderVersionByGameVersion(ctx context.Con
 
 	lv := &models.LoaderVersion{}
 
-	err := q.QueryRow(query, gameVersion).Scan(
+	err := q.QueryRowContext(ctx, query, gameVersion).Scan(
 		&lv.Id,
 		&lv.GameVersion,
 		&lv.VersionLabel,
@@ -105,7 +105,7 @@ func (r *loaderVersionRepository) FindLoaderVersionByLabel(ctx context.Context,
 
 	lv := &models.LoaderVersion{}
 
-	err := q.QueryRow(query, label).Scan(
+	err := q.QueryRowContext(ctx, query, label).Scan(
 		&lv.Id,
 		&lv.GameVersion,
 		&lv.VersionLabel,
@@ -9,6 +9,7 @@ import (
 
 type MockSearchRepository struct {
 	Projects  []meilisearch.ProjectDocument
+	Users     []meilisearch.UserDocument
 	TotalHits int64
 	Err       error
 }
@@ -36,8 +37,29 @@ func (m *MockSearchRepository) FindProjects(ctx context.Context, query string, p
 	}, m.Err
 }
 
+func (m *MockSearchRepository) IndexUser(ctx context.Context, user *models.UserWithStats) error {
+	return nil
+}
+
+func (m *MockSearchRepository) IndexUsers(ctx context.Context, users []models.UserWithStats) error {
+	return nil
+}
+
+func (m *MockSearchRepository) DeleteUser(ctx context.Context, userId string) error {
+	return nil
+}
+
+func (m *MockSearchRepository) FindUsers(ctx context.Context, query string, limit int64, offset int64) (*meilisearch.UserSearchResult, error) {
+	return &meilisearch.UserSearchResult{
+		Users:     m.Users,
+		TotalHits: m.TotalHits,
+	}, m.Err
+}
+
 func (m *MockSearchRepository) Health(ctx context.Context) error {
 	return m.Err
 }
 
 func (m *MockSearchRepository) EnsureProjectIndexExists() {}
+
+func (m *MockSearchRepository) EnsureUserIndexExists() {}
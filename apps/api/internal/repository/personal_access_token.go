@@ -0,0 +1,228 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/terraforge-gg/terraforge/internal/database"
+	"github.com/terraforge-gg/terraforge/internal/models"
+	"github.com/terraforge-gg/terraforge/internal/pagination"
+)
+
+type PersonalAccessTokenRepository interface {
+	InsertToken(ctx context.Context, q database.Querier, token *models.PersonalAccessToken) error
+	FindTokenByHash(ctx context.Context, q database.Querier, tokenHash string) (*models.PersonalAccessToken, error)
+	FindTokensByUserId(ctx context.Context, q database.Querier, userId string, page pagination.Params) (pagination.Page[models.PersonalAccessToken], error)
+	RevokeToken(ctx context.Context, q database.Querier, id string, userId string, revokedAt time.Time) (bool, error)
+	UpdateTokenLastUsedAt(ctx context.Context, q database.Querier, id string, lastUsedAt time.Time) error
+	RevokeTokensByUserId(ctx context.Context, q database.Querier, userId string, revokedAt time.Time) error
+}
+
+type personalAccessTokenRepository struct{}
+
+func NewPersonalAccessTokenRepository() PersonalAccessTokenRepository {
+	return &personalAccessTokenRepository{}
+}
+
+const personalAccessTokenColumns = `
+	"id",
+	"userId",
+	"name",
+	"tokenHash",
+	"tokenPrefix",
+	"scopes",
+	"projectIds",
+	"expiresAt",
+	"lastUsedAt",
+	"revokedAt",
+	"createdAt"`
+
+func (r *personalAccessTokenRepository) InsertToken(ctx context.Context, q database.Querier, token *models.PersonalAccessToken) error {
+	query := `INSERT INTO "personal_access_token"
+		("id", "userId", "name", "tokenHash", "tokenPrefix", "scopes", "projectIds", "expiresAt", "createdAt")
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);`
+
+	scopes := make([]string, len(token.Scopes))
+	for i, scope := range token.Scopes {
+		scopes[i] = string(scope)
+	}
+
+	_, err := q.ExecContext(
+		ctx,
+		query,
+		token.Id,
+		token.UserId,
+		token.Name,
+		token.TokenHash,
+		token.TokenPrefix,
+		pq.Array(scopes),
+		pq.Array(token.ProjectIds),
+		token.ExpiresAt,
+		token.CreatedAt)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Also loads the owner's verified email and test account flags.
+func (r *personalAccessTokenRepository) FindTokenByHash(ctx context.Context, q database.Querier, tokenHash string) (*models.PersonalAccessToken, error) {
+	query := `SELECT` + personalAccessTokenColumns + `,
+			u."emailVerified",
+			u."testAccount"
+		FROM "personal_access_token"
+		JOIN (SELECT "id" AS "ownerId", "emailVerified", "testAccount" FROM "user") u ON u."ownerId" = "userId"
+		WHERE "tokenHash" = $1;`
+
+	var emailVerified, testAccount bool
+
+	token, err := scanPersonalAccessToken(q.QueryRowContext(ctx, query, tokenHash), &emailVerified, &testAccount)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	token.UserEmailVerified = emailVerified
+	token.UserTestAccount = testAccount
+
+	return token, nil
+}
+
+// Lists a user's active tokens newest first.
+func (r *personalAccessTokenRepository) FindTokensByUserId(ctx context.Context, q database.Querier, userId string, page pagination.Params) (pagination.Page[models.PersonalAccessToken], error) {
+	var after pagination.TimeKey
+
+	if err := page.After(&after); err != nil {
+		return pagination.Page[models.PersonalAccessToken]{}, err
+	}
+
+	query := `SELECT` + personalAccessTokenColumns + `
+		FROM "personal_access_token"
+		WHERE "userId" = $1 AND "revokedAt" IS NULL
+			AND ($3 = '' OR ("createdAt", "id") < ($2, $3))
+		ORDER BY "createdAt" DESC, "id" DESC
+		LIMIT $4;`
+
+	rows, err := q.QueryContext(ctx, query, userId, after.Time, after.Id, page.QueryLimit())
+
+	if err != nil {
+		return pagination.Page[models.PersonalAccessToken]{}, err
+	}
+
+	defer rows.Close()
+
+	tokens := []models.PersonalAccessToken{}
+
+	for rows.Next() {
+		token, err := scanPersonalAccessToken(rows)
+
+		if err != nil {
+			return pagination.Page[models.PersonalAccessToken]{}, err
+		}
+
+		tokens = append(tokens, *token)
+	}
+
+	if err := rows.Err(); err != nil {
+		return pagination.Page[models.PersonalAccessToken]{}, err
+	}
+
+	return pagination.NewPage(tokens, page.Limit, func(t models.PersonalAccessToken) any {
+		return pagination.TimeKey{Time: t.CreatedAt, Id: t.Id}
+	}), nil
+}
+
+// Returns false when no active token with the id belongs to the user.
+func (r *personalAccessTokenRepository) RevokeToken(ctx context.Context, q database.Querier, id string, userId string, revokedAt time.Time) (bool, error) {
+	query := `UPDATE "personal_access_token"
+		SET "revokedAt" = $3
+		WHERE "id" = $1 AND "userId" = $2 AND "revokedAt" IS NULL;`
+
+	result, err := q.ExecContext(ctx, query, id, userId, revokedAt)
+
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+func (r *personalAccessTokenRepository) RevokeTokensByUserId(ctx context.Context, q database.Querier, userId string, revokedAt time.Time) error {
+	query := `UPDATE "personal_access_token"
+		SET "revokedAt" = $2
+		WHERE "userId" = $1 AND "revokedAt" IS NULL;`
+
+	_, err := q.ExecContext(ctx, query, userId, revokedAt)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *personalAccessTokenRepository) UpdateTokenLastUsedAt(ctx context.Context, q database.Querier, id string, lastUsedAt time.Time) error {
+	query := `UPDATE "personal_access_token" SET "lastUsedAt" = $2 WHERE "id" = $1;`
+
+	_, err := q.ExecContext(ctx, query, id, lastUsedAt)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// Scans personalAccessTokenColumns, followed by any extra columns the query selects into extra.
+func scanPersonalAccessToken(row rowScanner, extra ...any) (*models.PersonalAccessToken, error) {
+	var token models.PersonalAccessToken
+	var scopes pq.StringArray
+	var projectIds pq.StringArray
+
+	dest := []any{
+		&token.Id,
+		&token.UserId,
+		&token.Name,
+		&token.TokenHash,
+		&token.TokenPrefix,
+		&scopes,
+		&projectIds,
+		&token.ExpiresAt,
+		&token.LastUsedAt,
+		&token.RevokedAt,
+		&token.CreatedAt,
+	}
+
+	err := row.Scan(append(dest, extra...)...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	token.Scopes = make([]models.TokenScope, len(scopes))
+	for i, scope := range scopes {
+		token.Scopes[i] = models.TokenScope(scope)
+	}
+
+	token.ProjectIds = projectIds
+
+	return &token, nil
+}
@@ -9,17 +9,21 @@ import (
 	"github.com/lib/pq"
 	"github.com/terraforge-gg/terraforge/internal/database"
 	"github.com/terraforge-gg/terraforge/internal/models"
+	"github.com/terraforge-gg/terraforge/internal/pagination"
 )
 
 type ProjectRepository interface {
 	InsertProject(ctx context.Context, q database.Querier, project *models.Project) error
 	InsertProjectMember(ctx context.Context, q database.Querier, projectMember *models.ProjectMember) error
 	FindProjectByIdentifier(ctx context.Context, q database.Querier, projectIdentifier string, userId string) (*models.Project, error)
-	FindProjectMembersByProjectIdentifier(ctx context.Context, q database.Querier, projectIdentifier string, userId string) ([]models.ProjectMember, error)
+	FindProjectMembersByProjectIdentifier(ctx context.Context, q database.Querier, projectIdentifier string, userId string, page pagination.Params) (pagination.Page[models.ProjectMember], error)
 	FindProjectMemberByProjectIdAndUserId(ctx context.Context, q database.Querier, projectId string, userId string) (*models.ProjectMember, error)
 	UpdateProject(ctx context.Context, q database.Querier, project models.Project) error
 	DeleteProjectByIdentifier(ctx context.Context, q database.Querier, identifier string, deletedAt time.Time) error
-	FindProjectsByUserIdentifier(ctx context.Context, q database.Querier, userIdentifier string, status models.ProjectStatus) ([]models.Project, error)
+	FindProjectsByUserIdentifier(ctx context.Context, q database.Querier, userIdentifier string, status models.ProjectStatus, page pagination.Params) (pagination.Page[models.Project], error)
+	FindProjectByIdIncludingDeleted(ctx context.Context, q database.Querier, projectId string) (*models.Project, error)
+	UpdateProjectStatus(ctx context.Context, q database.Querier, projectId string, status models.ProjectStatus) error
+	RestoreProject(ctx context.Context, q database.Querier, projectId string) error
 }
 
 type projectRepository struct{}
@@ -78,6 +82,11 @@ func (r *projectRepository) InsertProjectMember(ctx context.Context, q database.
 		projectMember.CreatedAt)
 
 	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return database.ErrUniqueViolation
+		}
+
 		return err
 	}
 
@@ -142,7 +151,14 @@ type projectMemberRow struct {
 	Image     sql.NullString
 }
 
-func (r *projectRepository) FindProjectMembersByProjectIdentifier(ctx context.Context, q database.Querier, projectIdentifier string, userId string) ([]models.ProjectMember, error) {
+// Lists a project's members in the order they joined, so the owner comes first.
+func (r *projectRepository) FindProjectMembersByProjectIdentifier(ctx context.Context, q database.Querier, projectIdentifier string, userId string, page pagination.Params) (pagination.Page[models.ProjectMember], error) {
+	var after pagination.TimeKey
+
+	if err := page.After(&after); err != nil {
+		return pagination.Page[models.ProjectMember]{}, err
+	}
+
 	query := `
         SELECT
             pm."id",
@@ -163,12 +179,15 @@ func (r *projectRepository) FindProjectMembersByProjectIdentifier(ctx context.Co
 					WHERE pm2."projectId" = p."id"
 					AND pm2."userId" = $2
 				))
-			);`
+			)
+			AND ($4 = '' OR (pm."createdAt", pm."id") > ($3, $4))
+		ORDER BY pm."createdAt", pm."id"
+		LIMIT $5;`
 
-	rows, err := q.QueryContext(ctx, query, projectIdentifier, userId)
+	rows, err := q.QueryContext(ctx, query, projectIdentifier, userId, after.Time, after.Id, page.QueryLimit())
 
 	if err != nil {
-		return nil, err
+		return pagination.Page[models.ProjectMember]{}, err
 	}
 
 	defer rows.Close()
@@ -185,7 +204,7 @@ func (r *projectRepository) FindProjectMembersByProjectIdentifier(ctx context.Co
 			&row.Username,
 			&row.Image,
 		); err != nil {
-			return nil, err
+			return pagination.Page[models.ProjectMember]{}, err
 		}
 
 		var image *string
@@ -209,14 +228,12 @@ func (r *projectRepository) FindProjectMembersByProjectIdentifier(ctx context.Co
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, err
+		return pagination.Page[models.ProjectMember]{}, err
 	}
 
-	if len(projectMembers) == 0 {
-		return nil, nil
-	}
-
-	return projectMembers, nil
+	return pagination.NewPage(projectMembers, page.Limit, func(m models.ProjectMember) any {
+		return pagination.TimeKey{Time: m.CreatedAt, Id: m.Id}
+	}), nil
 }
 
 func (r *projectRepository) FindProjectMemberByProjectIdAndUserId(ctx context.Context, q database.Querier, projectId string, userId string) (*models.ProjectMember, error) {
@@ -291,7 +308,14 @@ func (r *projectRepository) DeleteProjectByIdentifier(ctx context.Context, q dat
 	return nil
 }
 
-func (r *projectRepository) FindProjectsByUserIdentifier(ctx context.Context, q database.Querier, userIdentifier string, projectStatus models.ProjectStatus) ([]models.Project, error) {
+// Lists a user's projects, newest first. Ordered by creation rather than downloads, which
+// change between requests and would make pages skip or repeat projects.
+func (r *projectRepository) FindProjectsByUserIdentifier(ctx context.Context, q database.Querier, userIdentifier string, projectStatus models.ProjectStatus, page pagination.Params) (pagination.Page[models.Project], error) {
+	var after pagination.TimeKey
+
+	if err := page.After(&after); err != nil {
+		return pagination.Page[models.Project]{}, err
+	}
 
 	query := `
 		SELECT
@@ -311,12 +335,14 @@ func (r *projectRepository) FindProjectsByUserIdentifier(ctx context.Context, q
 		FROM "active_project" p
 		JOIN "user" u ON p."userId" = u."id"
 		WHERE (u."id" = $1 OR u."username" = $1) AND p."status" = $2
-		ORDER BY p."downloads" DESC, p."updatedAt" DESC LIMIT 100;
+			AND ($4 = '' OR (p."createdAt", p."id") < ($3, $4))
+		ORDER BY p."createdAt" DESC, p."id" DESC
+		LIMIT $5;
 	`
 
-	rows, err := q.QueryContext(ctx, query, userIdentifier, projectStatus)
+	rows, err := q.QueryContext(ctx, query, userIdentifier, projectStatus, after.Time, after.Id, page.QueryLimit())
 	if err != nil {
-		return nil, err
+		return pagination.Page[models.Project]{}, err
 	}
 	defer rows.Close()
 
@@ -341,15 +367,102 @@ func (r *projectRepository) FindProjectsByUserIdentifier(ctx context.Context, q
 			&p.UserId,
 		)
 		if err != nil {
-			return nil, err
+			return pagination.Page[models.Project]{}, err
 		}
 
 		projects = append(projects, p)
 	}
 
 	if err := rows.Err(); err != nil {
+		return pagination.Page[models.Project]{}, err
+	}
+
+	return pagination.NewPage(projects, page.Limit, func(p models.Project) any {
+		return pagination.TimeKey{Time: p.CreatedAt, Id: p.Id}
+	}), nil
+}
+
+// Finds a project by id regardless of its status or whether it has been deleted, for administration.
+func (r *projectRepository) FindProjectByIdIncludingDeleted(ctx context.Context, q database.Querier, projectId string) (*models.Project, error) {
+	query := `
+		SELECT
+			"id",
+			"name",
+			"slug",
+			"summary",
+			"description",
+			"iconUrl",
+			"downloads",
+			"type",
+			"status",
+			"createdAt",
+			"updatedAt",
+			"deletedAt",
+			"userId"
+		FROM "project"
+		WHERE "id" = $1;`
+
+	project := &models.Project{}
+	err := q.QueryRowContext(ctx, query, projectId).Scan(
+		&project.Id,
+		&project.Name,
+		&project.Slug,
+		&project.Summary,
+		&project.Description,
+		&project.IconUrl,
+		&project.Downloads,
+		&project.Type,
+		&project.Status,
+		&project.CreatedAt,
+		&project.UpdatedAt,
+		&project.DeletedAt,
+		&project.UserId)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	if err != nil {
 		return nil, err
 	}
 
-	return projects, nil
+	return project, nil
+}
+
+func (r *projectRepository) UpdateProjectStatus(ctx context.Context, q database.Querier, projectId string, status models.ProjectStatus) error {
+	query := `
+		UPDATE "active_project"
+		SET "status" = $2, "updatedAt" = now()
+		WHERE "id" = $1;`
+
+	_, err := q.ExecContext(ctx, query, projectId, status)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Clears "deletedAt". Fails with database.ErrUniqueViolation when another project has taken the slug since.
+func (r *projectRepository) RestoreProject(ctx context.Context, q database.Querier, projectId string) error {
+	query := `
+		UPDATE "project"
+		SET "deletedAt" = NULL, "updatedAt" = now()
+		WHERE "id" = $1;`
+
+	_, err := q.ExecContext(ctx, query, projectId)
+
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) {
+			if pqErr.Code == "23505" {
+				return database.ErrUniqueViolation
+			}
+		}
+
+		return err
+	}
+
+	return nil
 }
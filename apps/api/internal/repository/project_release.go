@@ -11,6 +11,7 @@ import (
 	"github.com/lib/pq"
 	"github.com/terraforge-gg/terraforge/internal/database"
 	"github.com/terraforge-gg/terraforge/internal/models"
+	"github.com/terraforge-gg/terraforge/internal/pagination"
 )
 
 type ProjectReleaseRepository interface {
@@ -18,7 +19,7 @@ type ProjectReleaseRepository interface {
 	InsertRelease(ctx context.Context, q database.Querier, version *models.ProjectRelease) error
 	InsertDependencies(ctx context.Context, q database.Querier, deps []models.ProjectReleaseDependency) error
 	FindByProjectIdAndVersionNumber(ctx context.Context, q database.Querier, projectId string, versionNumber string) (*models.ProjectRelease, error)
-	FindReleasesByProjectIdWithLoaderVersion(ctx context.Context, q database.Querier, projectId string) ([]models.ProjectRelease, error)
+	FindReleasesByProjectIdWithLoaderVersion(ctx context.Context, q database.Querier, projectId string, page pagination.Params) (pagination.Page[models.ProjectRelease], error)
 }
 
 type projectReleaseRepository struct{}
@@ -227,7 +228,14 @@ func (r *projectReleaseRepository) FindByProjectIdAndVersionNumber(ctx context.C
 	return version, nil
 }
 
-func (r *projectReleaseRepository) FindReleasesByProjectIdWithLoaderVersion(ctx context.Context, q database.Querier, projectId string) ([]models.ProjectRelease, error) {
+// Lists a project's releases newest first.
+func (r *projectReleaseRepository) FindReleasesByProjectIdWithLoaderVersion(ctx context.Context, q database.Querier, projectId string, page pagination.Params) (pagination.Page[models.ProjectRelease], error) {
+	var after pagination.TimeKey
+
+	if err := page.After(&after); err != nil {
+		return pagination.Page[models.ProjectRelease]{}, err
+	}
+
 	query := `
 		SELECT
 			v."id",
@@ -252,11 +260,13 @@ func (r *projectReleaseRepository) FindReleasesByProjectIdWithLoaderVersion(ctx
 		FROM "project_release" v
 		LEFT JOIN "loader_version" l ON v."loaderVersionId" = l."id"
 		WHERE v."projectId" = $1
-		ORDER BY v."createdAt" DESC;`
+			AND ($3 = '' OR (v."createdAt", v."id") < ($2, $3))
+		ORDER BY v."createdAt" DESC, v."id" DESC
+		LIMIT $4;`
 
-	rows, err := q.QueryContext(ctx, query, projectId)
+	rows, err := q.QueryContext(ctx, query, projectId, after.Time, after.Id, page.QueryLimit())
 	if err != nil {
-		return nil, err
+		return pagination.Page[models.ProjectRelease]{}, err
 	}
 	defer rows.Close()
 
@@ -293,7 +303,7 @@ func (r *projectReleaseRepository) FindReleasesByProjectIdWithLoaderVersion(ctx
 			&loaderReleasedAt,
 			&loaderUpdatedAt,
 		); err != nil {
-			return nil, err
+			return pagination.Page[models.ProjectRelease]{}, err
 		}
 
 		if loaderId.Valid {
@@ -311,12 +321,10 @@ func (r *projectReleaseRepository) FindReleasesByProjectIdWithLoaderVersion(ctx
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-
-	if len(versions) == 0 {
-		return nil, nil
+		return pagination.Page[models.ProjectRelease]{}, err
 	}
 
-	return versions, nil
+	return pagination.NewPage(versions, page.Limit, func(v models.ProjectRelease) any {
+		return pagination.TimeKey{Time: v.CreatedAt, Id: v.Id}
+	}), nil
 }
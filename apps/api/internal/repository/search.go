@@ -3,7 +3,9 @@ package repository
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log/slog"
+	"time"
 
 	msearch "github.com/meilisearch/meilisearch-go"
 	"github.com/terraforge-gg/terraforge/internal/lib/meilisearch"
@@ -15,8 +17,13 @@ type SearchRepository interface {
 	UpdateProject(ctx context.Context, project *models.Project) error
 	DeleteProject(ctx context.Context, projectId string) error
 	FindProjects(ctx context.Context, query string, projectType string, limit int64, offset int64) (*meilisearch.ProjectSearchResult, error)
+	IndexUser(ctx context.Context, user *models.UserWithStats) error
+	IndexUsers(ctx context.Context, users []models.UserWithStats) error
+	DeleteUser(ctx context.Context, userId string) error
+	FindUsers(ctx context.Context, query string, limit int64, offset int64) (*meilisearch.UserSearchResult, error)
 	Health(ctx context.Context) error
 	EnsureProjectIndexExists()
+	EnsureUserIndexExists()
 }
 
 type meiliSearchRepository struct {
@@ -31,29 +38,31 @@ func NewMeiliSearchRepository(logger *slog.Logger, meiliSearchClient *meilisearc
 	}
 
 	repo.EnsureProjectIndexExists()
+	repo.EnsureUserIndexExists()
 
 	return repo
 }
 
 const PROJECTS_INDEX = "projects"
+const USERS_INDEX = "users"
 
 func (s *meiliSearchRepository) IndexProject(ctx context.Context, project *models.Project) error {
 	doc := meilisearch.ProjectToDocument(project)
 	index := s.meiliSearch.Client.Index(PROJECTS_INDEX)
-	_, err := index.AddDocuments([]meilisearch.ProjectDocument{*doc}, &msearch.DocumentOptions{PrimaryKey: msearch.StringPtr("id")})
+	_, err := index.AddDocumentsWithContext(ctx, []meilisearch.ProjectDocument{*doc}, &msearch.DocumentOptions{PrimaryKey: msearch.StringPtr("id")})
 	return err
 }
 
 func (s *meiliSearchRepository) UpdateProject(ctx context.Context, project *models.Project) error {
 	doc := meilisearch.ProjectToDocument(project)
 	index := s.meiliSearch.Client.Index(PROJECTS_INDEX)
-	_, err := index.UpdateDocuments([]meilisearch.ProjectDocument{*doc}, &msearch.DocumentOptions{PrimaryKey: msearch.StringPtr("id")})
+	_, err := index.UpdateDocumentsWithContext(ctx, []meilisearch.ProjectDocument{*doc}, &msearch.DocumentOptions{PrimaryKey: msearch.StringPtr("id")})
 	return err
 }
 
 func (s *meiliSearchRepository) DeleteProject(ctx context.Context, projectId string) error {
 	index := s.meiliSearch.Client.Index(PROJECTS_INDEX)
-	_, err := index.DeleteDocument(projectId, nil)
+	_, err := index.DeleteDocumentWithContext(ctx, projectId, nil)
 	return err
 }
 
@@ -65,7 +74,7 @@ func (s *meiliSearchRepository) FindProjects(ctx context.Context, query string,
 		Filter: "type = '" + projectType + "'",
 	}
 
-	res, err := index.Search(query, request)
+	res, err := index.SearchWithContext(ctx, query, request)
 
 	if err != nil {
 		return nil, err
@@ -96,8 +105,75 @@ func (s *meiliSearchRepository) FindProjects(ctx context.Context, query string,
 	return result, nil
 }
 
+func (s *meiliSearchRepository) IndexUser(ctx context.Context, user *models.UserWithStats) error {
+	doc := meilisearch.UserToDocument(user)
+	index := s.meiliSearch.Client.Index(USERS_INDEX)
+	_, err := index.AddDocumentsWithContext(ctx, []meilisearch.UserDocument{*doc}, &msearch.DocumentOptions{PrimaryKey: msearch.StringPtr("id")})
+	return err
+}
+
+func (s *meiliSearchRepository) IndexUsers(ctx context.Context, users []models.UserWithStats) error {
+	if len(users) == 0 {
+		return nil
+	}
+
+	docs := make([]meilisearch.UserDocument, len(users))
+
+	for i := range users {
+		docs[i] = *meilisearch.UserToDocument(&users[i])
+	}
+
+	index := s.meiliSearch.Client.Index(USERS_INDEX)
+	_, err := index.AddDocumentsWithContext(ctx, docs, &msearch.DocumentOptions{PrimaryKey: msearch.StringPtr("id")})
+	return err
+}
+
+func (s *meiliSearchRepository) DeleteUser(ctx context.Context, userId string) error {
+	index := s.meiliSearch.Client.Index(USERS_INDEX)
+	_, err := index.DeleteDocumentWithContext(ctx, userId, nil)
+	return err
+}
+
+func (s *meiliSearchRepository) FindUsers(ctx context.Context, query string, limit int64, offset int64) (*meilisearch.UserSearchResult, error) {
+	index := s.meiliSearch.Client.Index(USERS_INDEX)
+	request := &msearch.SearchRequest{
+		Limit:  limit,
+		Offset: offset,
+	}
+
+	res, err := index.SearchWithContext(ctx, query, request)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var users []meilisearch.UserDocument
+	for _, hit := range res.Hits {
+		var user meilisearch.UserDocument
+
+		hitJSON, err := json.Marshal(hit)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal(hitJSON, &user); err != nil {
+			return nil, err
+		}
+
+		users = append(users, user)
+	}
+
+	result := &meilisearch.UserSearchResult{
+		Users:     users,
+		TotalHits: res.EstimatedTotalHits,
+	}
+
+	return result, nil
+}
+
 func (s *meiliSearchRepository) Health(ctx context.Context) error {
-	_, err := s.meiliSearch.Client.Health()
+	_, err := s.meiliSearch.Client.HealthWithContext(ctx)
 
 	if err != nil {
 		return err
@@ -107,29 +183,134 @@ func (s *meiliSearchRepository) Health(ctx context.Context) error {
 }
 
 func (s *meiliSearchRepository) EnsureProjectIndexExists() {
-	_, err := s.meiliSearch.Client.GetIndex(PROJECTS_INDEX)
+	s.ensureIndex(PROJECTS_INDEX)
+}
+
+func (s *meiliSearchRepository) EnsureUserIndexExists() {
+	s.ensureIndex(USERS_INDEX)
+}
+
+const (
+	indexTaskTimeout      = 30 * time.Second
+	indexTaskPollInterval = 100 * time.Millisecond
+)
+
+// Creates the index if it is missing, then diffs the live settings against
+// index_settings.json and applies only what changed, waiting for each task to finish.
+func (s *meiliSearchRepository) ensureIndex(uid string) {
+	ctx, cancel := context.WithTimeout(context.Background(), indexTaskTimeout)
+	defer cancel()
+
+	cfg, err := meilisearch.LoadIndexSettingsConfig()
+
 	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to load index settings", "index", uid, "error", err)
+		return
+	}
 
-		_, err = s.meiliSearch.Client.CreateIndex(&msearch.IndexConfig{
-			Uid:        PROJECTS_INDEX,
-			PrimaryKey: "id",
+	declared, ok := cfg.Indexes[uid]
+
+	if !ok {
+		s.logger.ErrorContext(ctx, "No settings declared for index", "index", uid, "version", cfg.Version)
+		return
+	}
+
+	_, err = s.meiliSearch.Client.GetIndexWithContext(ctx, uid)
+	if err != nil {
+		task, err := s.meiliSearch.Client.CreateIndexWithContext(ctx, &msearch.IndexConfig{
+			Uid:        uid,
+			PrimaryKey: declared.PrimaryKey,
 		})
+
+		if err == nil {
+			err = s.waitForTask(ctx, task.TaskUID)
+		}
+
 		if err != nil {
-			s.logger.Error("Failed to create '"+PROJECTS_INDEX+"' index", "Error:", err)
+			s.logger.ErrorContext(ctx, "Failed to create index", "index", uid, "error", err)
+			return
 		}
 
-		s.logger.Info(PROJECTS_INDEX + " index created")
+		s.logger.InfoContext(ctx, "Index created", "index", uid)
 	}
 
-	index := s.meiliSearch.Client.Index(PROJECTS_INDEX)
-	_, err = index.UpdateSettings(&msearch.Settings{
-		SearchableAttributes: []string{"name", "slug", "summary", "description"},
-		FilterableAttributes: []string{"type", "downloads", "updatedAt"},
-	})
+	index := s.meiliSearch.Client.Index(uid)
+	live, err := index.GetSettingsWithContext(ctx)
 
 	if err != nil {
-		s.logger.Error("Failed to update projects index", "Error:", err)
+		s.logger.ErrorContext(ctx, "Failed to fetch index settings", "index", uid, "error", err)
+		return
+	}
+
+	change := declared.Diff(live)
+
+	if change == nil {
+		s.logger.InfoContext(ctx, "Index settings up to date", "index", uid, "version", cfg.Version)
+		return
 	}
 
-	s.logger.Info(PROJECTS_INDEX + " index setting updated")
+	if change.Update != nil {
+		task, err := index.UpdateSettingsWithContext(ctx, change.Update)
+
+		if err == nil {
+			err = s.waitForTask(ctx, task.TaskUID)
+		}
+
+		if err != nil {
+			s.logger.ErrorContext(ctx, "Failed to update index settings", "index", uid, "version", cfg.Version, "error", err)
+			return
+		}
+	}
+
+	for _, setting := range change.Reset {
+		task, err := resetSetting(ctx, index, setting)
+
+		if err == nil {
+			err = s.waitForTask(ctx, task.TaskUID)
+		}
+
+		if err != nil {
+			s.logger.ErrorContext(ctx, "Failed to reset index setting", "index", uid, "setting", setting, "version", cfg.Version, "error", err)
+			return
+		}
+	}
+
+	s.logger.InfoContext(ctx, "Index settings updated", "index", uid, "version", cfg.Version, "reset", change.Reset)
+}
+
+func resetSetting(ctx context.Context, index msearch.IndexManager, setting string) (*msearch.TaskInfo, error) {
+	switch setting {
+	case meilisearch.SettingSearchableAttributes:
+		return index.ResetSearchableAttributesWithContext(ctx)
+	case meilisearch.SettingFilterableAttributes:
+		return index.ResetFilterableAttributesWithContext(ctx)
+	case meilisearch.SettingSortableAttributes:
+		return index.ResetSortableAttributesWithContext(ctx)
+	case meilisearch.SettingRankingRules:
+		return index.ResetRankingRulesWithContext(ctx)
+	case meilisearch.SettingDistinctAttribute:
+		return index.ResetDistinctAttributeWithContext(ctx)
+	case meilisearch.SettingStopWords:
+		return index.ResetStopWordsWithContext(ctx)
+	case meilisearch.SettingSynonyms:
+		return index.ResetSynonymsWithContext(ctx)
+	case meilisearch.SettingTypoTolerance:
+		return index.ResetTypoToleranceWithContext(ctx)
+	}
+
+	return nil, fmt.Errorf("unknown index setting %q", setting)
+}
+
+func (s *meiliSearchRepository) waitForTask(ctx context.Context, taskUID int64) error {
+	task, err := s.meiliSearch.Client.WaitForTaskWithContext(ctx, taskUID, indexTaskPollInterval)
+
+	if err != nil {
+		return err
+	}
+
+	if task.Status != msearch.TaskStatusSucceeded {
+		return fmt.Errorf("task %d %s: %s", taskUID, task.Status, task.Error.Message)
+	}
+
+	return nil
 }
@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/terraforge-gg/terraforge/internal/database"
+	"github.com/terraforge-gg/terraforge/internal/models"
+)
+
+type SearchAnalyticsRepository interface {
+	InsertSearchQuery(ctx context.Context, q database.Querier, searchQuery *models.SearchQuery) error
+	InsertSearchClick(ctx context.Context, q database.Querier, searchClick *models.SearchClick) error
+	FindTopQueries(ctx context.Context, q database.Querier, since time.Time, limit int64) ([]models.SearchQueryStat, error)
+	FindZeroResultQueries(ctx context.Context, q database.Querier, since time.Time, limit int64) ([]models.SearchQueryStat, error)
+}
+
+type searchAnalyticsRepository struct{}
+
+func NewSearchAnalyticsRepository() SearchAnalyticsRepository {
+	return &searchAnalyticsRepository{}
+}
+
+func (r *searchAnalyticsRepository) InsertSearchQuery(ctx context.Context, q database.Querier, searchQuery *models.SearchQuery) error {
+	query := `INSERT INTO "search_query"
+		("id", "index", "query", "normalizedQuery", "filters", "resultCount", "limit", "offset", "userId", "createdAt")
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10);`
+
+	filters, err := json.Marshal(searchQuery.Filters)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = q.ExecContext(
+		ctx,
+		query,
+		searchQuery.Id,
+		searchQuery.Index,
+		searchQuery.Query,
+		searchQuery.NormalizedQuery,
+		filters,
+		searchQuery.ResultCount,
+		searchQuery.Limit,
+		searchQuery.Offset,
+		searchQuery.UserId,
+		searchQuery.CreatedAt)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Clicks come from clients, so one is only inserted for a recorded project search and only
+// once per search, project and type. Anything else is dropped without an error.
+func (r *searchAnalyticsRepository) InsertSearchClick(ctx context.Context, q database.Querier, searchClick *models.SearchClick) error {
+	query := `INSERT INTO "search_click"
+		("id", "searchQueryId", "projectId", "releaseId", "type", "createdAt")
+		SELECT $1, sq."id", $3, $4, $5, $6
+		FROM "search_query" sq
+		WHERE sq."id" = $2 AND sq."index" = 'projects'
+		ON CONFLICT ("searchQueryId", "projectId", "type") DO NOTHING;`
+
+	_, err := q.ExecContext(
+		ctx,
+		query,
+		searchClick.Id,
+		searchClick.SearchQueryId,
+		searchClick.ProjectId,
+		searchClick.ReleaseId,
+		searchClick.Type,
+		searchClick.CreatedAt)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *searchAnalyticsRepository) FindTopQueries(ctx context.Context, q database.Querier, since time.Time, limit int64) ([]models.SearchQueryStat, error) {
+	query := `
+		SELECT
+			sq."normalizedQuery",
+			COUNT(sq."id"),
+			AVG(sq."resultCount"),
+			COALESCE(SUM(sc."clicks"), 0),
+			MAX(sq."createdAt")
+		FROM "search_query" sq
+		-- Counted per search first, joining the clicks directly would repeat a search once per click
+		LEFT JOIN (
+			SELECT "searchQueryId", COUNT(*) AS "clicks"
+			FROM "search_click"
+			WHERE "createdAt" >= $1
+			GROUP BY "searchQueryId"
+		) sc ON sc."searchQueryId" = sq."id"
+		WHERE sq."createdAt" >= $1 AND sq."normalizedQuery" <> ''
+		GROUP BY sq."normalizedQuery"
+		ORDER BY COUNT(sq."id") DESC, MAX(sq."createdAt") DESC
+		LIMIT $2;`
+
+	return r.findQueryStats(ctx, q, query, since, limit)
+}
+
+func (r *searchAnalyticsRepository) FindZeroResultQueries(ctx context.Context, q database.Querier, since time.Time, limit int64) ([]models.SearchQueryStat, error) {
+	query := `
+		SELECT
+			sq."normalizedQuery",
+			COUNT(sq."id"),
+			0,
+			0,
+			MAX(sq."createdAt")
+		FROM "search_query" sq
+		WHERE sq."createdAt" >= $1 AND sq."resultCount" = 0 AND sq."normalizedQuery" <> ''
+		GROUP BY sq."normalizedQuery"
+		ORDER BY COUNT(sq."id") DESC, MAX(sq."createdAt") DESC
+		LIMIT $2;`
+
+	return r.findQueryStats(ctx, q, query, since, limit)
+}
+
+func (r *searchAnalyticsRepository) findQueryStats(ctx context.Context, q database.Querier, query string, since time.Time, limit int64) ([]models.SearchQueryStat, error) {
+	rows, err := q.QueryContext(ctx, query, since, limit)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	stats := []models.SearchQueryStat{}
+
+	for rows.Next() {
+		var stat models.SearchQueryStat
+
+		err := rows.Scan(
+			&stat.NormalizedQuery,
+			&stat.Searches,
+			&stat.AverageResultCount,
+			&stat.Clicks,
+			&stat.LastSearchedAt)
+
+		if err != nil {
+			return nil, err
+		}
+
+		stats = append(stats, stat)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
@@ -3,13 +3,21 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/terraforge-gg/terraforge/internal/database"
 	"github.com/terraforge-gg/terraforge/internal/models"
+	"github.com/terraforge-gg/terraforge/internal/pagination"
 )
 
 type UserRepository interface {
 	FindUserByIdentifier(ctx context.Context, q database.Querier, userIdentifier string) (*models.User, error)
+	FindUserStatsById(ctx context.Context, q database.Querier, userId string) (*models.UserStats, error)
+	FindUsersWithStats(ctx context.Context, q database.Querier, updatedSince time.Time, page pagination.Params) (pagination.Page[models.UserWithStats], error)
+	FindUsers(ctx context.Context, q database.Querier, params FindUsersParams) (pagination.Page[models.User], int64, error)
+	UpdateUserBan(ctx context.Context, q database.Querier, userId string, banned bool, reason *string, bannedAt *time.Time) error
+	DeleteUserSessions(ctx context.Context, q database.Querier, userId string) error
+	UpdateUserTestAccount(ctx context.Context, q database.Querier, userId string, testAccount bool) error
 }
 
 type userRepository struct{}
@@ -28,6 +36,11 @@ func (r *userRepository) FindUserByIdentifier(ctx context.Context, q database.Qu
 			"email",
 			"emailVerified",
 			"image",
+			"role",
+			"banned",
+			"banReason",
+			"bannedAt",
+			"testAccount",
 			"createdAt",
 			"updatedAt"
 		FROM "user" 
@@ -42,6 +55,11 @@ func (r *userRepository) FindUserByIdentifier(ctx context.Context, q database.Qu
 		&user.Email,
 		&user.EmailVerified,
 		&user.Image,
+		&user.Role,
+		&user.Banned,
+		&user.BanReason,
+		&user.BannedAt,
+		&user.TestAccount,
 		&user.CreatedAt,
 		&user.UpdatedAt)
 
@@ -55,3 +73,235 @@ func (r *userRepository) FindUserByIdentifier(ctx context.Context, q database.Qu
 
 	return user, nil
 }
+
+func (r *userRepository) FindUserStatsById(ctx context.Context, q database.Querier, userId string) (*models.UserStats, error) {
+	query := `
+		SELECT
+			COUNT("id"),
+			COALESCE(SUM("downloads"), 0)
+		FROM "active_project"
+		WHERE "userId" = $1 AND "status" = 'approved';`
+
+	stats := &models.UserStats{}
+	err := q.QueryRowContext(ctx, query, userId).Scan(
+		&stats.ProjectCount,
+		&stats.TotalDownloads)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// Lists searchable users in id order for indexing, those updated at or after updatedSince or
+// whose projects were, as the project count and downloads are part of a user's document.
+func (r *userRepository) FindUsersWithStats(ctx context.Context, q database.Querier, updatedSince time.Time, page pagination.Params) (pagination.Page[models.UserWithStats], error) {
+	var after pagination.IdKey
+
+	if err := page.After(&after); err != nil {
+		return pagination.Page[models.UserWithStats]{}, err
+	}
+
+	query := `
+		SELECT
+			u."id",
+			u."name",
+			u."username",
+			u."displayUsername",
+			u."image",
+			u."createdAt",
+			u."updatedAt",
+			COUNT(p."id"),
+			COALESCE(SUM(p."downloads"), 0)
+		FROM "user" u
+		LEFT JOIN "active_project" p ON p."userId" = u."id" AND p."status" = 'approved'
+		WHERE u."username" IS NOT NULL AND NOT u."banned"
+			AND (u."updatedAt" >= $1 OR EXISTS (
+				-- Project timestamps are stored as UTC without a time zone
+				SELECT 1 FROM "project" cp
+				WHERE cp."userId" = u."id"
+					AND (cp."updatedAt" >= ($1 AT TIME ZONE 'UTC') OR cp."deletedAt" >= ($1 AT TIME ZONE 'UTC'))
+			))
+			AND u."id" > $2
+		GROUP BY u."id"
+		ORDER BY u."id"
+		LIMIT $3;`
+
+	rows, err := q.QueryContext(ctx, query, updatedSince, after.Id, page.QueryLimit())
+
+	if err != nil {
+		return pagination.Page[models.UserWithStats]{}, err
+	}
+
+	defer rows.Close()
+
+	var users []models.UserWithStats
+
+	for rows.Next() {
+		var u models.UserWithStats
+
+		err := rows.Scan(
+			&u.User.Id,
+			&u.User.Name,
+			&u.User.Username,
+			&u.User.DisplayUsername,
+			&u.User.Image,
+			&u.User.CreatedAt,
+			&u.User.UpdatedAt,
+			&u.Stats.ProjectCount,
+			&u.Stats.TotalDownloads)
+
+		if err != nil {
+			return pagination.Page[models.UserWithStats]{}, err
+		}
+
+		users = append(users, u)
+	}
+
+	if err := rows.Err(); err != nil {
+		return pagination.Page[models.UserWithStats]{}, err
+	}
+
+	return pagination.NewPage(users, page.Limit, func(u models.UserWithStats) any {
+		return pagination.IdKey{Id: u.User.Id}
+	}), nil
+}
+
+type FindUsersParams struct {
+	// Matches a prefix of the username or email, case insensitively
+	Query  string
+	Role   *models.UserRole
+	Banned *bool
+	Page   pagination.Params
+}
+
+// Lists users for administration, newest first. Returns the page and the total number of matches.
+func (r *userRepository) FindUsers(ctx context.Context, q database.Querier, params FindUsersParams) (pagination.Page[models.User], int64, error) {
+	var after pagination.TimeKey
+
+	if err := params.Page.After(&after); err != nil {
+		return pagination.Page[models.User]{}, 0, err
+	}
+
+	// The total is counted before the cursor is applied so every page reports the same total
+	query := `
+		WITH "match" AS (
+			SELECT *
+			FROM "user"
+			WHERE ($1 = '' OR "username" ILIKE $1 || '%' OR "email" ILIKE $1 || '%')
+				AND ($2::TEXT IS NULL OR "role" = $2)
+				AND ($3::BOOLEAN IS NULL OR "banned" = $3)
+		)
+		SELECT
+			"id",
+			"name",
+			COALESCE("username", ''),
+			"displayUsername",
+			"email",
+			"emailVerified",
+			"image",
+			"role",
+			"banned",
+			"banReason",
+			"bannedAt",
+			"testAccount",
+			"createdAt",
+			"updatedAt",
+			(SELECT COUNT(*) FROM "match")
+		FROM "match"
+		WHERE $5 = '' OR ("createdAt", "id") < ($4, $5)
+		ORDER BY "createdAt" DESC, "id" DESC
+		LIMIT $6;`
+
+	rows, err := q.QueryContext(ctx, query, params.Query, params.Role, params.Banned, after.Time, after.Id, params.Page.QueryLimit())
+
+	if err != nil {
+		return pagination.Page[models.User]{}, 0, err
+	}
+
+	defer rows.Close()
+
+	users := []models.User{}
+	var total int64
+
+	for rows.Next() {
+		var u models.User
+
+		err := rows.Scan(
+			&u.Id,
+			&u.Name,
+			&u.Username,
+			&u.DisplayUsername,
+			&u.Email,
+			&u.EmailVerified,
+			&u.Image,
+			&u.Role,
+			&u.Banned,
+			&u.BanReason,
+			&u.BannedAt,
+			&u.TestAccount,
+			&u.CreatedAt,
+			&u.UpdatedAt,
+			&total)
+
+		if err != nil {
+			return pagination.Page[models.User]{}, 0, err
+		}
+
+		users = append(users, u)
+	}
+
+	if err := rows.Err(); err != nil {
+		return pagination.Page[models.User]{}, 0, err
+	}
+
+	page := pagination.NewPage(users, params.Page.Limit, func(u models.User) any {
+		return pagination.TimeKey{Time: u.CreatedAt, Id: u.Id}
+	})
+
+	return page, total, nil
+}
+
+func (r *userRepository) UpdateUserBan(ctx context.Context, q database.Querier, userId string, banned bool, reason *string, bannedAt *time.Time) error {
+	query := `
+		UPDATE "user"
+		SET "banned" = $2, "banReason" = $3, "bannedAt" = $4, "updatedAt" = now()
+		WHERE "id" = $1;`
+
+	_, err := q.ExecContext(ctx, query, userId, banned, reason, bannedAt)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *userRepository) UpdateUserTestAccount(ctx context.Context, q database.Querier, userId string, testAccount bool) error {
+	query := `
+		UPDATE "user"
+		SET "testAccount" = $2, "updatedAt" = now()
+		WHERE "id" = $1;`
+
+	_, err := q.ExecContext(ctx, query, userId, testAccount)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Signs the user out everywhere. JWTs already issued stay valid until they expire.
+func (r *userRepository) DeleteUserSessions(ctx context.Context, q database.Querier, userId string) error {
+	query := `DELETE FROM "session" WHERE "userId" = $1;`
+
+	_, err := q.ExecContext(ctx, query, userId)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+// Package requestctx carries what is known about a request through its context, so the logs
+// and spans of the services and repositories serving it can be tied back to it.
+package requestctx
+
+import (
+	"context"
+	"sync"
+
+	"github.com/labstack/echo/v5"
+	"github.com/terraforge-gg/terraforge/internal/utils"
+)
+
+// Info describes the request a context belongs to. The user is only known once the caller
+// has been authenticated, after Info is created, so it is set separately.
+type Info struct {
+	Id    string
+	Route string
+
+	mu     sync.Mutex
+	userId string
+}
+
+func (i *Info) UserId() string {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	return i.userId
+}
+
+func (i *Info) SetUserId(userId string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.userId = userId
+}
+
+type infoKey struct{}
+
+func WithInfo(ctx context.Context, info *Info) context.Context {
+	return context.WithValue(ctx, infoKey{}, info)
+}
+
+func FromContext(ctx context.Context) (*Info, bool) {
+	info, ok := ctx.Value(infoKey{}).(*Info)
+	return info, ok
+}
+
+// Middleware adds the request's Info to its context. It goes after the request id middleware
+// so the id is known.
+func Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c *echo.Context) error {
+			info := &Info{
+				Id:    c.Response().Header().Get(echo.HeaderXRequestID),
+				Route: c.Path(),
+			}
+
+			c.SetRequest(c.Request().WithContext(WithInfo(c.Request().Context(), info)))
+
+			return next(c)
+		}
+	}
+}
+
+// UserMiddleware records the authenticated user on the request's Info. It goes after the
+// middleware that identifies the caller.
+func UserMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c *echo.Context) error {
+			if userId, ok := utils.GetSessionUserId(c); ok {
+				if info, ok := FromContext(c.Request().Context()); ok {
+					info.SetUserId(userId)
+				}
+			}
+
+			return next(c)
+		}
+	}
+}
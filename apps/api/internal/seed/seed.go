@@ -47,7 +47,7 @@ func SeedLoaderVersions(logger *slog.Logger, loaderVersionService service.Loader
 
 		_, err := loaderVersionService.GetLoaderVersionByLabel(ctx, versionLabel)
 		if err == nil {
-			logger.Info("loader version already exists, skipping", "game version", gameVersion, "version label", versionLabel)
+			logger.Info("loader version already exists, skipping", "gameVersion", gameVersion, "versionLabel", versionLabel)
 			continue
 		}
 
@@ -59,7 +59,7 @@ func SeedLoaderVersions(logger *slog.Logger, loaderVersionService service.Loader
 			return err
 		}
 
-		logger.Info("seeded loader version", "game version", gameVersion, "version label", versionLabel, "build type", buildType)
+		logger.Info("seeded loader version", "gameVersion", gameVersion, "versionLabel", versionLabel, "buildType", buildType)
 	}
 
 	return nil
@@ -0,0 +1,63 @@
+package server
+
+import (
+	"database/sql"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/terraforge-gg/terraforge/internal/background"
+	"github.com/terraforge-gg/terraforge/internal/lib/redis"
+	"github.com/terraforge-gg/terraforge/internal/metrics"
+)
+
+// Registers the metrics read from the server's dependencies when scraped.
+func registerMetrics(db *sql.DB, tasks *background.Group, redisClient *redis.RedisClient) error {
+	breakerCollectors := []prometheus.Collector{
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: "terraforge",
+			Subsystem: "redis",
+			Name:      "circuit_breaker_trips_total",
+			Help:      "Times the Redis circuit breaker has opened.",
+		}, func() float64 { return float64(redisClient.Breaker.Stats().Trips) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: "terraforge",
+			Subsystem: "redis",
+			Name:      "circuit_breaker_short_circuited_total",
+			Help:      "Redis commands failed fast while the circuit breaker was open.",
+		}, func() float64 { return float64(redisClient.Breaker.Stats().ShortCircuit) }),
+	}
+
+	for _, state := range []redis.BreakerState{redis.BreakerClosed, redis.BreakerOpen, redis.BreakerHalfOpen} {
+		breakerCollectors = append(breakerCollectors, prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace:   "terraforge",
+			Subsystem:   "redis",
+			Name:        "circuit_breaker_state",
+			Help:        "1 for the state the Redis circuit breaker is in, 0 for the others.",
+			ConstLabels: prometheus.Labels{"state": string(state)},
+		}, func() float64 {
+			if redisClient.Breaker.Stats().State == state {
+				return 1
+			}
+
+			return 0
+		}))
+	}
+
+	collectorsToRegister := append(breakerCollectors,
+		collectors.NewDBStatsCollector(db, "postgres"),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: "terraforge",
+			Subsystem: "background",
+			Name:      "tasks_running",
+			Help:      "Background tasks, such as search indexing, started and not yet finished.",
+		}, func() float64 { return float64(tasks.Running()) }),
+	)
+
+	for _, c := range collectorsToRegister {
+		if err := metrics.Registry.Register(c); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -1,9 +1,10 @@
 package server
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
-	"log"
 	"log/slog"
 	"net/http"
 	"time"
@@ -12,70 +13,156 @@ import (
 	"github.com/labstack/echo/v5"
 	"github.com/labstack/echo/v5/middleware"
 	"github.com/terraforge-gg/terraforge/internal/auth"
+	"github.com/terraforge-gg/terraforge/internal/background"
 	"github.com/terraforge-gg/terraforge/internal/cache"
 	"github.com/terraforge-gg/terraforge/internal/config"
+	custom_errors "github.com/terraforge-gg/terraforge/internal/errors"
 	"github.com/terraforge-gg/terraforge/internal/handler"
 	"github.com/terraforge-gg/terraforge/internal/lib/aws"
 	"github.com/terraforge-gg/terraforge/internal/lib/meilisearch"
 	"github.com/terraforge-gg/terraforge/internal/lib/redis"
+	custom_logger "github.com/terraforge-gg/terraforge/internal/logger"
+	"github.com/terraforge-gg/terraforge/internal/metrics"
 	custom_middleware "github.com/terraforge-gg/terraforge/internal/middleware"
+	"github.com/terraforge-gg/terraforge/internal/models"
 	"github.com/terraforge-gg/terraforge/internal/repository"
+	"github.com/terraforge-gg/terraforge/internal/requestctx"
 	"github.com/terraforge-gg/terraforge/internal/seed"
 	"github.com/terraforge-gg/terraforge/internal/service"
+	"github.com/terraforge-gg/terraforge/internal/tracing"
 	"github.com/terraforge-gg/terraforge/internal/validation"
 )
 
-func NewServer(cfg *config.Config, logger *slog.Logger, db *sql.DB) (*echo.Echo, error) {
-	jwtValidator, err := auth.NewValidator(cfg.AuthUrl + "/api/auth/jwks")
-	authMiddleware := custom_middleware.JWTMiddleware(jwtValidator)
-	authOptionalMiddleware := custom_middleware.OptionalJWTMiddleware(jwtValidator)
+// Server is the API along with what has to be stopped when it shuts down.
+type Server struct {
+	Echo         *echo.Echo
+	tasks        *background.Group
+	jwtValidator *auth.Validator
+	redisClient  *redis.RedisClient
+}
+
+func NewServer(cfg *config.Config, logger *slog.Logger, db *sql.DB) (*Server, error) {
+	jwtValidator, err := auth.NewValidator(cfg.AuthUrl+"/api/auth/jwks", auth.ValidatorOptions{
+		Issuer:          cfg.JwtIssuer,
+		Audience:        cfg.JwtAudience,
+		RefreshInterval: cfg.JwksRefreshInterval,
+	})
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to create JWKS validator: %w", err)
 	}
 
+	tasks := background.NewGroup()
+	projectRepo := repository.NewProjectRepository()
+
+	tokenRepo := repository.NewPersonalAccessTokenRepository()
+	tokenService := service.NewPersonalAccessTokenService(logger, db, tokenRepo, projectRepo, tasks)
+	tokenHandler := handler.NewPersonalAccessTokenHandler(cfg, logger, tokenService)
+
+	authMiddleware := custom_middleware.JWTMiddleware(jwtValidator, tokenService)
+	authOptionalMiddleware := custom_middleware.OptionalJWTMiddleware(jwtValidator, tokenService)
+	sessionOnly := custom_middleware.SessionOnly()
+	verifiedEmail := custom_middleware.RequireVerifiedEmail(cfg.RequireVerifiedEmail)
+
+	resolveProjectId := func(ctx context.Context, identifier string, userId string) (string, error) {
+		project, err := projectRepo.FindProjectByIdentifier(ctx, db, identifier, userId)
+
+		if err != nil {
+			return "", err
+		}
+
+		if project == nil {
+			return "", custom_errors.ErrProjectNotFound
+		}
+
+		return project.Id, nil
+	}
+
+	projectRead := custom_middleware.RequireScope(models.TokenScopeProjectRead, resolveProjectId)
+	projectWrite := custom_middleware.RequireScope(models.TokenScopeProjectWrite, resolveProjectId)
+	releaseWrite := custom_middleware.RequireScope(models.TokenScopeReleaseWrite, resolveProjectId)
+
 	aws_config, err := aws.NewAwsConfig(cfg)
 
 	if err != nil {
-		log.Fatal(err)
+		return nil, fmt.Errorf("failed to create aws config: %w", err)
 	}
 
 	s3_client := aws.NewS3Client(cfg, aws_config)
 	objectStoreService := service.NewObjectStoreService(s3_client, cfg.R2Bucket)
 
-	redisClient, err := redis.NewRedisClient(cfg.RedisUrl, cfg.RedisPassword)
+	redisClient, err := redis.NewRedisClient(logger, cfg.RedisUrl, cfg.RedisPassword)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to create redis client: %w", err)
 	}
 
-	projectCache := cache.NewProjectCache(redisClient)
+	localCache := cache.NewLocalCache(cfg.LocalCacheSize, cfg.LocalCacheTTL)
+	projectCache := cache.NewProjectCache(redisClient, localCache)
+
+	if localCache != nil {
+		tasks.Start(func(ctx context.Context) {
+			localCache.Listen(ctx, redisClient.Client, logger)
+		})
+	}
 
 	meiliClient := meilisearch.NewMeiliSearch(cfg.MeiliSearchHostUrl, cfg.MeiliSearchMasterKey)
 	meiliSearchRepo := repository.NewMeiliSearchRepository(logger, meiliClient)
 	searchService := service.NewSearchService(logger, meiliSearchRepo)
 
+	searchAnalyticsRepo := repository.NewSearchAnalyticsRepository()
+	searchAnalyticsService := service.NewSearchAnalyticsService(logger, db, searchAnalyticsRepo, cfg.SearchAnalyticsSampleRate, tasks)
+
 	authHealthCheckService := auth.NewAuthHealthCheckService(logger, cfg.AuthUrl)
 
-	generalLimiter := custom_middleware.RateLimiter(redisClient.Client, custom_middleware.RateLimitGeneral)
-	writeLimiter := custom_middleware.RateLimiter(redisClient.Client, custom_middleware.RateLimitWrite)
-	searchLimiter := custom_middleware.RateLimiter(redisClient.Client, custom_middleware.RateLimitSearch)
+	rateLimitQuotas := custom_middleware.RateLimitQuotas(cfg.RateLimitTiers)
+
+	rateLimitPolicies, err := custom_middleware.LoadRateLimitPolicies(cfg.RateLimitPoliciesFile)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to load rate limit policies: %w", err)
+	}
+
+	if err := rateLimitPolicies.CheckQuotas(rateLimitQuotas); err != nil {
+		return nil, fmt.Errorf("invalid rate limit tiers: %w", err)
+	}
+
+	rateLimits := custom_middleware.NewRateLimits(redisClient.Client, custom_middleware.RateLimitOptions{
+		Quotas:            rateLimitQuotas,
+		UserCeiling:       cfg.RateLimitUserCeiling,
+		TrustedPartnerIds: cfg.RateLimitTrustedPartnerIds,
+	}, rateLimitPolicies)
+	rateLimitHandler := handler.NewRateLimitHandler(rateLimits)
+
+	tasks.Start(func(ctx context.Context) {
+		rateLimits.WatchPolicies(ctx, logger, cfg.RateLimitPoliciesFile, cfg.RateLimitPoliciesReloadInterval)
+	})
 
 	loaderVersionRepo := repository.NewLoaderVersionRepository()
-	loaderVersionService := service.NewLoaderVersionService(logger, db, loaderVersionRepo)
+	loaderVersionCache := cache.NewLoaderVersionCache(redisClient)
+	loaderVersionService := service.NewLoaderVersionService(logger, db, loaderVersionRepo, loaderVersionCache)
 	loaderVersionHandler := handler.NewLoaderVersionHandler(cfg, logger, loaderVersionService)
 
 	userRepository := repository.NewUserRepository()
 
-	projectRepo := repository.NewProjectRepository()
-	projectService := service.NewProjectService(logger, db, projectRepo, meiliSearchRepo, projectCache, userRepository)
-	projectHandler := handler.NewProjectHandler(cfg, logger, projectService, searchService)
+	projectService := service.NewProjectService(logger, db, projectRepo, meiliSearchRepo, projectCache, userRepository, tasks)
+	projectHandler := handler.NewProjectHandler(cfg, logger, projectService, searchService, searchAnalyticsService)
 
-	userHandler := handler.NewUserHandler(cfg, logger, projectService)
+	userService := service.NewUserService(logger, db, userRepository, meiliSearchRepo)
+	userHandler := handler.NewUserHandler(cfg, logger, projectService, searchService, searchAnalyticsService)
+
+	tasks.Start(func(ctx context.Context) {
+		userService.RunUserSearchSync(ctx, 5*time.Minute)
+	})
 
 	projectReleasenRepo := repository.NewProjectReleaseRepository()
-	projectReleaseService := service.NewProjectReleaseService(logger, cfg.CdnUrl, db, projectRepo, projectReleasenRepo, loaderVersionRepo, objectStoreService)
-	projectReleaseHandler := handler.NewProjectReleaseHandler(cfg, logger, projectReleaseService)
+	releaseCache := cache.NewReleaseCache(redisClient)
+	projectReleaseService := service.NewProjectReleaseService(logger, cfg.CdnUrl, db, projectRepo, projectReleasenRepo, loaderVersionRepo, objectStoreService, releaseCache, tasks)
+	projectReleaseHandler := handler.NewProjectReleaseHandler(cfg, logger, projectReleaseService, searchAnalyticsService)
+
+	auditLogRepo := repository.NewAuditLogRepository()
+	adminService := service.NewAdminService(logger, db, userRepository, projectRepo, tokenRepo, auditLogRepo, meiliSearchRepo, projectCache, tasks)
+	adminHandler := handler.NewAdminHandler(cfg, logger, searchAnalyticsService, adminService)
 
 	if cfg.SeedDb {
 		seed.SeedLoaderVersions(logger, loaderVersionService)
@@ -101,24 +188,53 @@ func NewServer(cfg *config.Config, logger *slog.Logger, db *sql.DB) (*echo.Echo,
 			Timeout: 2 * time.Second,
 			Check:   authHealthCheckService.Health,
 		}),
-		health.WithCheck(health.Check{
-			Name:    "redis",
-			Timeout: 2 * time.Second,
-			Check:   redisClient.Health,
+		// Redis only backs caches and rate limits, which fall back to the database and
+		// per instance limits, so an outage reports the service as degraded instead of down
+		health.WithInfoFunc(func(info map[string]any) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			status := health.StatusUp
+			if err := redisClient.Health(ctx); err != nil {
+				status = health.StatusDown
+				info["degraded"] = true
+			}
+
+			info["redis"] = map[string]any{
+				"status":  status,
+				"breaker": redisClient.Breaker.Stats(),
+			}
 		}),
 	)
 
+	trustedProxies, err := custom_middleware.LoadTrustedProxies(cfg.TrustedProxies, cfg.TrustedProxiesFile)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to load trusted proxies: %w", err)
+	}
+
+	if err := registerMetrics(db, tasks, redisClient); err != nil {
+		return nil, fmt.Errorf("failed to register metrics: %w", err)
+	}
+
 	e := echo.New()
 
+	e.Logger = logger
+	e.HTTPErrorHandler = handler.NewErrorHandler(logger)
+	e.IPExtractor = custom_middleware.NewIPExtractor(trustedProxies)
 	e.Validator = &validation.Validator{Validator: validate}
 
-	e.Use(middleware.RequestLogger())
+	e.Use(custom_logger.Middleware(logger))
 	e.Use(middleware.RequestID())
+	e.Use(requestctx.Middleware())
+	e.Use(tracing.Middleware())
+	e.Use(metrics.Middleware())
 	e.Use(middleware.Recover())
 	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
 		AllowOrigins:     []string{cfg.FrontendUrl},
 		AllowMethods:     []string{http.MethodGet, http.MethodHead, http.MethodPut, http.MethodPatch, http.MethodPost, http.MethodDelete},
 		AllowHeaders:     []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization},
+		ExposeHeaders:    []string{"RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "RateLimit-Policy", echo.HeaderRetryAfter},
 		AllowCredentials: true,
 	}))
 
@@ -140,25 +256,69 @@ func NewServer(cfg *config.Config, logger *slog.Logger, db *sql.DB) (*echo.Echo,
 	e.GET("/ready", echo.WrapHandler(health.NewHandler(checker)))
 
 	v1 := e.Group("/v1")
-	v1.Use(generalLimiter)
+	// Identifies the caller up front so rate limits apply per user or token rather than per IP
+	v1.Use(authOptionalMiddleware, requestctx.UserMiddleware(), rateLimits.Middleware())
 	v1.File("/openapi.yml", "./docs/openapi.yml")
+	v1.GET("/rate-limits", rateLimitHandler.GetRateLimits)
 
 	v1.GET("/loader-versions/:id", loaderVersionHandler.GetLoaderVersionById)
 	v1.GET("/loader-versions", loaderVersionHandler.GetLoaderVersions)
 
-	v1.GET("/users/:userIdentifier/projects", userHandler.GetProjectsByUserId, authOptionalMiddleware)
+	v1.GET("/users", userHandler.SearchUsers)
+	v1.GET("/users/:userIdentifier/projects", userHandler.GetProjectsByUserId, authOptionalMiddleware, projectRead)
+
+	v1.POST("/projects", projectHandler.CreateProject, authMiddleware, verifiedEmail, projectWrite)
+	v1.GET("/projects", projectHandler.SearchProjects)
+	v1.GET("/projects/:identifier", projectHandler.GetProjectByIdentifier, authOptionalMiddleware, projectRead)
+	v1.GET("/projects/:identifier/members", projectHandler.GetProjectMembers, authOptionalMiddleware, projectRead)
+	v1.POST("/projects/:identifier/members", projectHandler.AddProjectMember, authMiddleware, verifiedEmail, projectWrite)
+	v1.PATCH("/projects/:identifier", projectHandler.UpdateProject, authMiddleware, projectWrite)
+	v1.DELETE("/projects/:identifier", projectHandler.DeleteProject, authMiddleware, projectWrite)
+
+	v1.POST("/projects/:identifier/releases", projectReleaseHandler.CreateRelease, authMiddleware, verifiedEmail, releaseWrite)
+	v1.POST("/projects/:identifier/releases/publish", projectReleaseHandler.PublishRelease, authMiddleware, verifiedEmail, releaseWrite)
+	v1.GET("/projects/:identifier/releases", projectReleaseHandler.GetReleases, authOptionalMiddleware, projectRead)
+	v1.GET("/projects/:identifier/releases/:releaseId", projectReleaseHandler.GetRelease, authOptionalMiddleware, projectRead)
+	v1.GET("/projects/:identifier/releases/upload-url", projectReleaseHandler.GeneratePresignedPutUrl, authMiddleware, verifiedEmail, releaseWrite)
+
+	v1.POST("/tokens", tokenHandler.CreateToken, authMiddleware, sessionOnly, verifiedEmail)
+	v1.GET("/tokens", tokenHandler.GetTokens, authMiddleware, sessionOnly)
+	v1.DELETE("/tokens/:tokenId", tokenHandler.RevokeToken, authMiddleware, sessionOnly)
+
+	requireAdmin := custom_middleware.RequireRole(models.UserRoleAdmin)
+
+	admin := v1.Group("/admin", authMiddleware, sessionOnly, custom_middleware.RequireRole(models.UserRoleModerator))
+	admin.GET("/search-analytics", adminHandler.GetSearchAnalyticsReport, requireAdmin)
+	admin.GET("/users", adminHandler.GetUsers)
+	admin.POST("/users/:userId/ban", adminHandler.BanUser, requireAdmin)
+	admin.DELETE("/users/:userId/ban", adminHandler.UnbanUser, requireAdmin)
+	admin.PUT("/projects/:projectId/status", adminHandler.UpdateProjectStatus)
+	admin.PUT("/users/:userId/test-account", adminHandler.UpdateTestAccount, requireAdmin)
+	admin.POST("/projects/:projectId/restore", adminHandler.RestoreProject, requireAdmin)
+	admin.GET("/audit-logs", adminHandler.GetAuditLogs, requireAdmin)
+
+	return &Server{
+		Echo:         e,
+		tasks:        tasks,
+		jwtValidator: jwtValidator,
+		redisClient:  redisClient,
+	}, nil
+}
 
-	v1.POST("/projects", projectHandler.CreateProject, authMiddleware, writeLimiter)
-	v1.GET("/projects", projectHandler.SearchProjects, searchLimiter)
-	v1.GET("/projects/:identifier", projectHandler.GetProjectByIdentifier, authOptionalMiddleware)
-	v1.GET("/projects/:identifier/members", projectHandler.GetProjectMembers, authOptionalMiddleware)
-	v1.PATCH("/projects/:identifier", projectHandler.UpdateProject, authMiddleware, writeLimiter)
-	v1.DELETE("/projects/:identifier", projectHandler.DeleteProject, authMiddleware, writeLimiter)
+// Shutdown stops the background workers, waits for background tasks until ctx is done and
+// then closes the connections the server opened. The HTTP server must already be stopped.
+func (s *Server) Shutdown(ctx context.Context) error {
+	err := s.tasks.Shutdown(ctx)
 
-	v1.POST("/projects/:identifier/releases", projectReleaseHandler.CreateRelease, authMiddleware, writeLimiter)
-	v1.GET("/projects/:identifier/releases", projectReleaseHandler.GetReleases, authOptionalMiddleware)
-	v1.GET("/projects/:identifier/releases/:releaseId", projectReleaseHandler.GetRelease, authOptionalMiddleware)
-	v1.GET("/projects/:identifier/releases/upload-url", projectReleaseHandler.GeneratePresignedPutUrl, authMiddleware)
+	if err != nil {
+		err = fmt.Errorf("background work did not finish: %w", err)
+	}
+
+	s.jwtValidator.Close()
+
+	if closeErr := s.redisClient.Close(); closeErr != nil {
+		err = errors.Join(err, fmt.Errorf("failed to close redis client: %w", closeErr))
+	}
 
-	return e, nil
+	return err
 }
@@ -0,0 +1,402 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/terraforge-gg/terraforge/internal/background"
+	"github.com/terraforge-gg/terraforge/internal/cache"
+	"github.com/terraforge-gg/terraforge/internal/database"
+	custom_errors "github.com/terraforge-gg/terraforge/internal/errors"
+	"github.com/terraforge-gg/terraforge/internal/models"
+	"github.com/terraforge-gg/terraforge/internal/pagination"
+	"github.com/terraforge-gg/terraforge/internal/repository"
+	"github.com/terraforge-gg/terraforge/internal/utils"
+)
+
+// AdminService holds platform wide moderation actions. Every change is audit logged in the
+// same transaction as the change itself.
+type AdminService interface {
+	GetUsers(ctx context.Context, params repository.FindUsersParams) (pagination.Page[models.User], int64, error)
+	BanUser(ctx context.Context, params BanUserParams) (*models.User, error)
+	UnbanUser(ctx context.Context, actorId string, userId string) (*models.User, error)
+	SetTestAccount(ctx context.Context, actorId string, userId string, testAccount bool) (*models.User, error)
+	SetProjectStatus(ctx context.Context, params SetProjectStatusParams) (*models.Project, error)
+	RestoreProject(ctx context.Context, actorId string, projectId string) (*models.Project, error)
+	GetAuditLogs(ctx context.Context, params repository.FindAuditLogsParams) (pagination.Page[models.AuditLog], error)
+}
+
+type adminService struct {
+	logger       *slog.Logger
+	db           *sql.DB
+	userRepo     repository.UserRepository
+	projectRepo  repository.ProjectRepository
+	tokenRepo    repository.PersonalAccessTokenRepository
+	auditLogRepo repository.AuditLogRepository
+	searchRepo   repository.SearchRepository
+	projectCache cache.ProjectCache
+	tasks        *background.Group
+}
+
+func NewAdminService(logger *slog.Logger, db *sql.DB, userRepo repository.UserRepository, projectRepo repository.ProjectRepository, tokenRepo repository.PersonalAccessTokenRepository, auditLogRepo repository.AuditLogRepository, searchRepo repository.SearchRepository, projectCache cache.ProjectCache, tasks *background.Group) AdminService {
+	return &adminService{
+		logger:       logger,
+		db:           db,
+		userRepo:     userRepo,
+		projectRepo:  projectRepo,
+		tokenRepo:    tokenRepo,
+		auditLogRepo: auditLogRepo,
+		searchRepo:   searchRepo,
+		projectCache: projectCache,
+		tasks:        tasks,
+	}
+}
+
+func (s *adminService) GetUsers(ctx context.Context, params repository.FindUsersParams) (pagination.Page[models.User], int64, error) {
+	return s.userRepo.FindUsers(ctx, s.db, params)
+}
+
+type BanUserParams struct {
+	ActorId string
+	UserId  string
+	Reason  *string
+}
+
+// Bans a user, signing them out everywhere and revoking their personal access tokens.
+// With their sessions gone no new JWTs can be issued, those already issued expire shortly.
+func (s *adminService) BanUser(ctx context.Context, params BanUserParams) (*models.User, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback()
+
+	user, err := s.userRepo.FindUserByIdentifier(ctx, tx, params.UserId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil {
+		return nil, custom_errors.ErrUserNotFound
+	}
+
+	if user.Id == params.ActorId {
+		return nil, custom_errors.ErrUserCannotBanSelf
+	}
+
+	if user.Role == models.UserRoleAdmin {
+		return nil, custom_errors.ErrUserCannotBeBanned
+	}
+
+	now := time.Now().UTC()
+
+	if err := s.userRepo.UpdateUserBan(ctx, tx, user.Id, true, params.Reason, &now); err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.DeleteUserSessions(ctx, tx, user.Id); err != nil {
+		return nil, err
+	}
+
+	if err := s.tokenRepo.RevokeTokensByUserId(ctx, tx, user.Id, now); err != nil {
+		return nil, err
+	}
+
+	err = s.audit(ctx, tx, params.ActorId, models.AuditActionUserBan, models.AuditTargetUser, user.Id, map[string]any{
+		"reason": params.Reason,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	user.Banned = true
+	user.BanReason = params.Reason
+	user.BannedAt = &now
+
+	s.tasks.Go(ctx, func(ctx context.Context) {
+		err := s.searchRepo.DeleteUser(ctx, user.Id)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "Failed to delete banned user document.", "userId", user.Id, "error", err)
+		}
+	})
+
+	return user, nil
+}
+
+func (s *adminService) UnbanUser(ctx context.Context, actorId string, userId string) (*models.User, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback()
+
+	user, err := s.userRepo.FindUserByIdentifier(ctx, tx, userId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil {
+		return nil, custom_errors.ErrUserNotFound
+	}
+
+	if !user.Banned {
+		return nil, custom_errors.ErrUserNotBanned
+	}
+
+	if err := s.userRepo.UpdateUserBan(ctx, tx, user.Id, false, nil, nil); err != nil {
+		return nil, err
+	}
+
+	err = s.audit(ctx, tx, actorId, models.AuditActionUserUnban, models.AuditTargetUser, user.Id, map[string]any{
+		"banReason": user.BanReason,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	user.Banned = false
+	user.BanReason = nil
+	user.BannedAt = nil
+
+	s.tasks.Go(ctx, func(ctx context.Context) {
+		err := syncUserSearchDocument(ctx, s.db, s.userRepo, s.searchRepo, user.Id)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "Failed to sync user search document.", "userId", user.Id, "error", err)
+		}
+	})
+
+	return user, nil
+}
+
+// Flags or unflags a test account. Test accounts are exempt from the verified email
+// requirement, the change applies once the user's JWT is refreshed.
+func (s *adminService) SetTestAccount(ctx context.Context, actorId string, userId string, testAccount bool) (*models.User, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback()
+
+	user, err := s.userRepo.FindUserByIdentifier(ctx, tx, userId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil {
+		return nil, custom_errors.ErrUserNotFound
+	}
+
+	if err := s.userRepo.UpdateUserTestAccount(ctx, tx, user.Id, testAccount); err != nil {
+		return nil, err
+	}
+
+	err = s.audit(ctx, tx, actorId, models.AuditActionUserTestAccount, models.AuditTargetUser, user.Id, map[string]any{
+		"testAccount": testAccount,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	user.TestAccount = testAccount
+
+	return user, nil
+}
+
+type SetProjectStatusParams struct {
+	ActorId   string
+	ProjectId string
+	Status    models.ProjectStatus
+	Reason    *string
+}
+
+// Changes the status of any project, bypassing the owner checks of the regular project routes.
+func (s *adminService) SetProjectStatus(ctx context.Context, params SetProjectStatusParams) (*models.Project, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback()
+
+	project, err := s.projectRepo.FindProjectByIdIncludingDeleted(ctx, tx, params.ProjectId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if project == nil || project.DeletedAt != nil {
+		return nil, custom_errors.ErrProjectNotFound
+	}
+
+	previousStatus := project.Status
+
+	if err := s.projectRepo.UpdateProjectStatus(ctx, tx, project.Id, params.Status); err != nil {
+		return nil, err
+	}
+
+	err = s.audit(ctx, tx, params.ActorId, models.AuditActionProjectStatusChange, models.AuditTargetProject, project.Id, map[string]any{
+		"from":   previousStatus,
+		"to":     params.Status,
+		"reason": params.Reason,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	project.Status = params.Status
+	project.UpdatedAt = time.Now().UTC()
+
+	s.invalidateProject(ctx, project.Id)
+
+	// Only approved projects are searchable, anything else is taken out of the index
+	s.tasks.Go(ctx, func(ctx context.Context) {
+		if project.Status != models.ProjectStatusApproved {
+			if err := s.searchRepo.DeleteProject(ctx, project.Id); err != nil {
+				s.logger.ErrorContext(ctx, "Failed to delete project document.", "projectId", project.Id, "error", err)
+			}
+			return
+		}
+
+		if err := s.searchRepo.UpdateProject(ctx, project); err != nil {
+			s.logger.ErrorContext(ctx, "Failed to update project document.", "projectId", project.Id, "error", err)
+		}
+	})
+
+	if previousStatus == models.ProjectStatusApproved || project.Status == models.ProjectStatusApproved {
+		s.tasks.Go(ctx, func(ctx context.Context) {
+			s.syncOwnerSearchDocument(ctx, project.UserId)
+		})
+	}
+
+	return project, nil
+}
+
+// Undoes a soft delete. Fails with ErrProjectSlugUsed when another project has taken the slug since.
+func (s *adminService) RestoreProject(ctx context.Context, actorId string, projectId string) (*models.Project, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback()
+
+	project, err := s.projectRepo.FindProjectByIdIncludingDeleted(ctx, tx, projectId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if project == nil {
+		return nil, custom_errors.ErrProjectNotFound
+	}
+
+	if project.DeletedAt == nil {
+		return nil, custom_errors.ErrProjectNotDeleted
+	}
+
+	err = s.projectRepo.RestoreProject(ctx, tx, project.Id)
+
+	if err != nil {
+		if errors.Is(err, database.ErrUniqueViolation) {
+			return nil, custom_errors.ErrProjectSlugUsed.WithDetail("Another project is using this project's slug.")
+		}
+		return nil, err
+	}
+
+	err = s.audit(ctx, tx, actorId, models.AuditActionProjectRestore, models.AuditTargetProject, project.Id, map[string]any{
+		"deletedAt": project.DeletedAt,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	project.DeletedAt = nil
+	project.UpdatedAt = time.Now().UTC()
+
+	s.invalidateProject(ctx, project.Id)
+
+	if project.Status == models.ProjectStatusApproved {
+		s.tasks.Go(ctx, func(ctx context.Context) {
+			err := s.searchRepo.IndexProject(ctx, project)
+			if err != nil {
+				s.logger.ErrorContext(ctx, "Failed to index project document.", "projectId", project.Id, "error", err)
+			}
+		})
+
+		s.tasks.Go(ctx, func(ctx context.Context) {
+			s.syncOwnerSearchDocument(ctx, project.UserId)
+		})
+	}
+
+	return project, nil
+}
+
+func (s *adminService) GetAuditLogs(ctx context.Context, params repository.FindAuditLogsParams) (pagination.Page[models.AuditLog], error) {
+	return s.auditLogRepo.FindAuditLogs(ctx, s.db, params)
+}
+
+func (s *adminService) audit(ctx context.Context, q database.Querier, actorId string, action models.AuditAction, targetType models.AuditTargetType, targetId string, metadata map[string]any) error {
+	return s.auditLogRepo.InsertAuditLog(ctx, q, &models.AuditLog{
+		Id:         utils.NewUUID(),
+		ActorId:    &actorId,
+		Action:     action,
+		TargetType: targetType,
+		TargetId:   targetId,
+		Metadata:   metadata,
+		CreatedAt:  time.Now().UTC(),
+	})
+}
+
+func (s *adminService) invalidateProject(ctx context.Context, projectId string) {
+	err := s.projectCache.DeleteProject(ctx, projectId)
+
+	if err != nil {
+		s.logger.WarnContext(ctx, "Failed to delete project from cache", "projectId", projectId, "error", err)
+	}
+}
+
+func (s *adminService) syncOwnerSearchDocument(ctx context.Context, userId string) {
+	err := syncUserSearchDocument(ctx, s.db, s.userRepo, s.searchRepo, userId)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to sync user search document.", "userId", userId, "error", err)
+	}
+}
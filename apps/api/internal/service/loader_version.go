@@ -3,11 +3,14 @@ package service
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"log/slog"
 	"time"
 
-	"github.com/terraforge-gg/terraforge/internal/errors"
+	"github.com/terraforge-gg/terraforge/internal/cache"
+	custom_errors "github.com/terraforge-gg/terraforge/internal/errors"
 	"github.com/terraforge-gg/terraforge/internal/models"
+	"github.com/terraforge-gg/terraforge/internal/pagination"
 	"github.com/terraforge-gg/terraforge/internal/repository"
 )
 
@@ -15,18 +18,22 @@ type LoaderVersionService interface {
 	GetLoaderVersionById(ctx context.Context, id string) (*models.LoaderVersion, error)
 	GetLoaderVersionByGameVersion(ctx context.Context, gameVersion string) (*models.LoaderVersion, error)
 	GetLoaderVersionByLabel(ctx context.Context, label string) (*models.LoaderVersion, error)
-	GetLoaderVersions(ctx context.Context) ([]models.LoaderVersion, error)
+	GetLoaderVersions(ctx context.Context, page pagination.Params) (pagination.Page[models.LoaderVersion], error)
 	CreateLoaderVersion(ctx context.Context, params CreateLoaderVersionParams) error
 }
 
 type loaderVersionService struct {
-	logger            *slog.Logger
-	db                *sql.DB
-	loaderVersionRepo repository.LoaderVersionRepository
+	logger             *slog.Logger
+	db                 *sql.DB
+	loaderVersionRepo  repository.LoaderVersionRepository
+	loaderVersionCache cache.LoaderVersionCache
 }
 
-func NewLoaderVersionService(logger *slog.Logger, db *sql.DB, loaderVersionRepo repository.LoaderVersionRepository) LoaderVersionService {
-	return &loaderVersionService{logger: logger, db: db, loaderVersionRepo: loaderVersionRepo}
+// Loader versions only change when new tModLoader releases are synced.
+const loaderVersionCacheTTL = time.Hour
+
+func NewLoaderVersionService(logger *slog.Logger, db *sql.DB, loaderVersionRepo repository.LoaderVersionRepository, loaderVersionCache cache.LoaderVersionCache) LoaderVersionService {
+	return &loaderVersionService{logger: logger, db: db, loaderVersionRepo: loaderVersionRepo, loaderVersionCache: loaderVersionCache}
 }
 
 func (s *loaderVersionService) GetLoaderVersionById(ctx context.Context, id string) (*models.LoaderVersion, error) {
@@ -37,7 +44,7 @@ func (s *loaderVersionService) GetLoaderVersionById(ctx context.Context, id stri
 	}
 
 	if modLoaderVersion == nil {
-		return nil, errors.ErrLoaderVersionNotFound
+		return nil, custom_errors.ErrLoaderVersionNotFound.With("loaderVersionId", id)
 	}
 
 	return modLoaderVersion, nil
@@ -51,7 +58,7 @@ func (s *loaderVersionService) GetLoaderVersionByGameVersion(ctx context.Context
 	}
 
 	if loaderVersion == nil {
-		return nil, errors.ErrLoaderVersionNotFound
+		return nil, custom_errors.ErrLoaderVersionNotFound
 	}
 
 	return loaderVersion, nil
@@ -65,19 +72,44 @@ func (s *loaderVersionService) GetLoaderVersionByLabel(ctx context.Context, labe
 	}
 
 	if loaderVersion == nil {
-		return nil, errors.ErrLoaderVersionNotFound
+		return nil, custom_errors.ErrLoaderVersionNotFound
 	}
 
 	return loaderVersion, nil
 }
 
-func (s *loaderVersionService) GetLoaderVersions(ctx context.Context) ([]models.LoaderVersion, error) {
-	loaderVersions, err := s.loaderVersionRepo.FindLoaderVersions(ctx, s.db)
+// The list is small enough to be cached whole, so pages are cut from it rather than queried.
+func (s *loaderVersionService) GetLoaderVersions(ctx context.Context, page pagination.Params) (pagination.Page[models.LoaderVersion], error) {
+	loaderVersions, err := s.getLoaderVersions(ctx)
+
+	if err != nil {
+		return pagination.Page[models.LoaderVersion]{}, err
+	}
+
+	return pagination.Slice(loaderVersions, page, func(lv models.LoaderVersion) string { return lv.Id })
+}
+
+func (s *loaderVersionService) getLoaderVersions(ctx context.Context) ([]models.LoaderVersion, error) {
+	loaderVersions, err := s.loaderVersionCache.GetLoaderVersions(ctx)
+
+	// The list is small and cheap to load, so stale entries are simply reloaded
+	if err == nil {
+		return loaderVersions, nil
+	}
+
+	// Cache failures fall back to the database rather than failing the request
+	if !errors.Is(err, cache.ErrCacheMiss) && !errors.Is(err, cache.ErrCacheStale) {
+		s.logger.WarnContext(ctx, "Failed to read loader versions from cache", "error", err)
+	}
+
+	loaderVersions, err = s.loaderVersionRepo.FindLoaderVersions(ctx, s.db)
 
 	if err != nil {
 		return nil, err
 	}
 
+	_ = s.loaderVersionCache.SetLoaderVersions(ctx, loaderVersions, loaderVersionCacheTTL)
+
 	return loaderVersions, nil
 }
 
@@ -104,5 +136,11 @@ func (s *loaderVersionService) CreateLoaderVersion(ctx context.Context, params C
 		return err
 	}
 
+	err = s.loaderVersionCache.DeleteLoaderVersions(ctx)
+
+	if err != nil {
+		s.logger.WarnContext(ctx, "Failed to delete loader versions from cache", "error", err)
+	}
+
 	return nil
 }
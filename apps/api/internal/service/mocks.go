@@ -2,17 +2,21 @@ package service
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"github.com/terraforge-gg/terraforge/internal/models"
+	"github.com/terraforge-gg/terraforge/internal/pagination"
 )
 
 type MockProjectService struct {
 	CreateUserProjectFunc           func(ctx context.Context, params CreateUserProjectParams) (*models.Project, error)
 	GetProjectByIdentifierFunc      func(ctx context.Context, params GetProjectByIdentifierParams) (*models.Project, error)
-	GetProjectMembersFunc           func(ctx context.Context, params GetProjectMembersParams) ([]models.ProjectMember, error)
+	GetProjectMembersFunc           func(ctx context.Context, params GetProjectMembersParams) (pagination.Page[models.ProjectMember], error)
+	AddProjectMemberFunc            func(ctx context.Context, params AddProjectMemberParams) (*models.ProjectMember, error)
 	UpdateProjectFunc               func(ctx context.Context, params UpdateProjectParams) (*models.Project, error)
 	DeleteProjectFunc               func(ctx context.Context, params DeleteProjectParams) error
-	GetProjectsByUserIdentifierFunc func(ctx context.Context, params GetProjectsByUserIdentifierParams) ([]models.Project, error)
+	GetProjectsByUserIdentifierFunc func(ctx context.Context, params GetProjectsByUserIdentifierParams) (pagination.Page[models.Project], error)
 }
 
 func NewMockProjectService() *MockProjectService {
@@ -33,10 +37,17 @@ func (m *MockProjectService) GetProjectByIdentifier(ctx context.Context, params
 	return nil, nil
 }
 
-func (m *MockProjectService) GetProjectMembers(ctx context.Context, params GetProjectMembersParams) ([]models.ProjectMember, error) {
+func (m *MockProjectService) GetProjectMembers(ctx context.Context, params GetProjectMembersParams) (pagination.Page[models.ProjectMember], error) {
 	if m.GetProjectMembersFunc != nil {
 		return m.GetProjectMembersFunc(ctx, params)
 	}
+	return pagination.Page[models.ProjectMember]{}, nil
+}
+
+func (m *MockProjectService) AddProjectMember(ctx context.Context, params AddProjectMemberParams) (*models.ProjectMember, error) {
+	if m.AddProjectMemberFunc != nil {
+		return m.AddProjectMemberFunc(ctx, params)
+	}
 	return nil, nil
 }
 
@@ -54,15 +65,16 @@ func (m *MockProjectService) DeleteProject(ctx context.Context, params DeletePro
 	return nil
 }
 
-func (m *MockProjectService) GetProjectsByUserIdentifier(ctx context.Context, params GetProjectsByUserIdentifierParams) ([]models.Project, error) {
+func (m *MockProjectService) GetProjectsByUserIdentifier(ctx context.Context, params GetProjectsByUserIdentifierParams) (pagination.Page[models.Project], error) {
 	if m.GetProjectsByUserIdentifierFunc != nil {
 		return m.GetProjectsByUserIdentifierFunc(ctx, params)
 	}
-	return nil, nil
+	return pagination.Page[models.Project]{}, nil
 }
 
 type MockSearchService struct {
 	SearchProjectsFunc func(ctx context.Context, query string, projectType string, limit int64, offset int64) ([]models.Project, int64, error)
+	SearchUsersFunc    func(ctx context.Context, query string, limit int64, offset int64) ([]models.UserWithStats, int64, error)
 	HealthFunc         func(ctx context.Context) error
 }
 
@@ -77,6 +89,13 @@ func (m *MockSearchService) SearchProjects(ctx context.Context, query string, pr
 	return nil, 0, nil
 }
 
+func (m *MockSearchService) SearchUsers(ctx context.Context, query string, limit int64, offset int64) ([]models.UserWithStats, int64, error) {
+	if m.SearchUsersFunc != nil {
+		return m.SearchUsersFunc(ctx, query, limit, offset)
+	}
+	return nil, 0, nil
+}
+
 func (m *MockSearchService) Health(ctx context.Context) error {
 	if m.HealthFunc != nil {
 		return m.HealthFunc(ctx)
@@ -88,7 +107,8 @@ type MockProjectReleaseService struct {
 	GetReleaseByIdWithDependenciesFunc func(ctx context.Context, projectIdentifier string, releaseId string, userId string) (*models.ProjectRelease, error)
 	CreateReleaseFunc                  func(ctx context.Context, projectIdentifier string, userId string, params CreateReleaseParams) (*models.ProjectRelease, error)
 	GeneratePresignedPutUrlFunc        func(ctx context.Context, projectIdentifier string, userId string, fileSize string) (string, error)
-	GetReleasesByProjectIdFunc         func(ctx context.Context, id string, userId string) ([]models.ProjectRelease, error)
+	GetReleasesByProjectIdFunc         func(ctx context.Context, id string, userId string, page pagination.Params) (pagination.Page[models.ProjectRelease], error)
+	PublishReleaseFunc                 func(ctx context.Context, projectIdentifier string, userId string, params PublishReleaseParams) (*models.ProjectRelease, error)
 }
 
 func NewMockProjectReleaseService() *MockProjectReleaseService {
@@ -116,9 +136,16 @@ func (m *MockProjectReleaseService) GenerateProjectReleasePresignedPutUrl(ctx co
 	return "", nil
 }
 
-func (m *MockProjectReleaseService) GetReleasesByProjectId(ctx context.Context, id string, userId string) ([]models.ProjectRelease, error) {
+func (m *MockProjectReleaseService) GetReleasesByProjectId(ctx context.Context, id string, userId string, page pagination.Params) (pagination.Page[models.ProjectRelease], error) {
 	if m.GetReleasesByProjectIdFunc != nil {
-		return m.GetReleasesByProjectIdFunc(ctx, id, userId)
+		return m.GetReleasesByProjectIdFunc(ctx, id, userId, page)
+	}
+	return pagination.Page[models.ProjectRelease]{}, nil
+}
+
+func (m *MockProjectReleaseService) PublishRelease(ctx context.Context, projectIdentifier string, userId string, params PublishReleaseParams) (*models.ProjectRelease, error) {
+	if m.PublishReleaseFunc != nil {
+		return m.PublishReleaseFunc(ctx, projectIdentifier, userId, params)
 	}
 	return nil, nil
 }
@@ -127,7 +154,7 @@ type MockLoaderVersionService struct {
 	GetLoaderVersionByIdFunc          func(ctx context.Context, id string) (*models.LoaderVersion, error)
 	GetLoaderVersionByGameVersionFunc func(ctx context.Context, gameVersion string) (*models.LoaderVersion, error)
 	GetLoaderVersionByLabelFunc       func(ctx context.Context, label string) (*models.LoaderVersion, error)
-	GetLoaderVersionsFunc             func(ctx context.Context) ([]models.LoaderVersion, error)
+	GetLoaderVersionsFunc             func(ctx context.Context, page pagination.Params) (pagination.Page[models.LoaderVersion], error)
 	CreateLoaderVersionFunc           func(ctx context.Context, params CreateLoaderVersionParams) error
 }
 
@@ -135,6 +162,8 @@ type MockObjectStoreService struct {
 	GeneratePresignedPutUrlFunc func(ctx context.Context, key string, contentType string, fileSize int64) (string, error)
 	GetFileMetadateFunc         func(ctx context.Context, key string) (*metadata, error)
 	MoveFileFunc                func(ctx context.Context, sourceKey string, destinationKey string) (string, error)
+	UploadFileFunc              func(ctx context.Context, key string, contentType string, body io.Reader, maxSize int64) (*UploadedFile, error)
+	DeleteFileFunc              func(ctx context.Context, key string) error
 }
 
 func NewMockObjectStoreService() *MockObjectStoreService {
@@ -165,6 +194,28 @@ func (m *MockObjectStoreService) MoveFile(ctx context.Context, sourceKey string,
 	return "/cdn/releases/" + destinationKey, nil
 }
 
+func (m *MockObjectStoreService) UploadFile(ctx context.Context, key string, contentType string, body io.Reader, maxSize int64) (*UploadedFile, error) {
+	if m.UploadFileFunc != nil {
+		return m.UploadFileFunc(ctx, key, contentType, body, maxSize)
+	}
+	n, err := io.Copy(io.Discard, body)
+	if err != nil {
+		return nil, err
+	}
+	return &UploadedFile{
+		Path:          "/cdn/releases/" + key,
+		ContentLength: n,
+		Hash:          "mock-hash",
+	}, nil
+}
+
+func (m *MockObjectStoreService) DeleteFile(ctx context.Context, key string) error {
+	if m.DeleteFileFunc != nil {
+		return m.DeleteFileFunc(ctx, key)
+	}
+	return nil
+}
+
 func NewMockLoaderVersionService() *MockLoaderVersionService {
 	return &MockLoaderVersionService{}
 }
@@ -190,11 +241,11 @@ func (m *MockLoaderVersionService) GetLoaderVersionByLabel(ctx context.Context,
 	return nil, nil
 }
 
-func (m *MockLoaderVersionService) GetLoaderVersions(ctx context.Context) ([]models.LoaderVersion, error) {
+func (m *MockLoaderVersionService) GetLoaderVersions(ctx context.Context, page pagination.Params) (pagination.Page[models.LoaderVersion], error) {
 	if m.GetLoaderVersionsFunc != nil {
-		return m.GetLoaderVersionsFunc(ctx)
+		return m.GetLoaderVersionsFunc(ctx, page)
 	}
-	return nil, nil
+	return pagination.Page[models.LoaderVersion]{}, nil
 }
 
 func (m *MockLoaderVersionService) CreateLoaderVersion(ctx context.Context, params CreateLoaderVersionParams) error {
@@ -203,3 +254,33 @@ func (m *MockLoaderVersionService) CreateLoaderVersion(ctx context.Context, para
 	}
 	return nil
 }
+
+type MockSearchAnalyticsService struct {
+	RecordSearchFunc func(ctx context.Context, params RecordSearchParams) (string, bool)
+	RecordClickFunc  func(ctx context.Context, params RecordClickParams)
+	GetReportFunc    func(ctx context.Context, since time.Time, limit int64) (*models.SearchAnalyticsReport, error)
+}
+
+func NewMockSearchAnalyticsService() *MockSearchAnalyticsService {
+	return &MockSearchAnalyticsService{}
+}
+
+func (m *MockSearchAnalyticsService) RecordSearch(ctx context.Context, params RecordSearchParams) (string, bool) {
+	if m.RecordSearchFunc != nil {
+		return m.RecordSearchFunc(ctx, params)
+	}
+	return "", false
+}
+
+func (m *MockSearchAnalyticsService) RecordClick(ctx context.Context, params RecordClickParams) {
+	if m.RecordClickFunc != nil {
+		m.RecordClickFunc(ctx, params)
+	}
+}
+
+func (m *MockSearchAnalyticsService) GetReport(ctx context.Context, since time.Time, limit int64) (*models.SearchAnalyticsReport, error) {
+	if m.GetReportFunc != nil {
+		return m.GetReportFunc(ctx, since, limit)
+	}
+	return &models.SearchAnalyticsReport{Since: since}, nil
+}
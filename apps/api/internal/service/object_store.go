@@ -1,20 +1,28 @@
 package service
 
 import (
+	"bytes"
 	"context"
+	"crypto/md5"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
 	"strconv"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/terraforge-gg/terraforge/internal/metrics"
 )
 
 type ObjectStoreService interface {
 	GeneratePresignedPutUrl(ctx context.Context, key string, contentType string, fileSize int64) (string, error)
 	GetFileMetadate(ctx context.Context, key string) (*metadata, error)
 	MoveFile(ctx context.Context, sourceKey string, destinationKey string) (string, error)
+	UploadFile(ctx context.Context, key string, contentType string, body io.Reader, maxSize int64) (*UploadedFile, error)
+	DeleteFile(ctx context.Context, key string) error
 }
 
 type objectStoreService struct {
@@ -32,6 +40,7 @@ func NewObjectStoreService(client *s3.Client, assetsBucketName string) ObjectSto
 func (s *objectStoreService) GeneratePresignedPutUrl(ctx context.Context, key string, contentType string, fileSize int64) (string, error) {
 	presignClient := s3.NewPresignClient(s.client)
 
+	start := time.Now()
 	put, err := presignClient.PresignPutObject(ctx, &s3.PutObjectInput{
 		Bucket:        aws.String(s.assetsBucketName),
 		Key:           aws.String(key),
@@ -40,8 +49,13 @@ func (s *objectStoreService) GeneratePresignedPutUrl(ctx context.Context, key st
 	}, func(opts *s3.PresignOptions) {
 		opts.Expires = 15 * time.Minute
 	})
+	metrics.ObserveSince(metrics.ObjectStoreDuration, "presign_put", start, err)
 
-	return put.URL, err
+	if err != nil {
+		return "", err
+	}
+
+	return put.URL, nil
 }
 
 type metadata struct {
@@ -52,13 +66,16 @@ type metadata struct {
 var (
 	ErrFileNotFound     = errors.New("s3 file not found")
 	ErrFailedToMoveFile = errors.New("failed to move file")
+	ErrFileTooLarge     = errors.New("file too large")
 )
 
 func (s *objectStoreService) GetFileMetadate(ctx context.Context, key string) (*metadata, error) {
+	start := time.Now()
 	response, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{
 		Bucket: &s.assetsBucketName,
 		Key:    &key,
 	})
+	metrics.ObserveSince(metrics.ObjectStoreDuration, "head", start, err)
 
 	if err != nil {
 		return nil, ErrFileNotFound
@@ -77,11 +94,13 @@ func (s *objectStoreService) GetFileMetadate(ctx context.Context, key string) (*
 }
 
 func (s *objectStoreService) MoveFile(ctx context.Context, sourceKey string, destinationKey string) (string, error) {
+	start := time.Now()
 	_, err := s.client.CopyObject(ctx, &s3.CopyObjectInput{
 		Bucket:     &s.assetsBucketName,
 		CopySource: aws.String(fmt.Sprintf("%s/%s", s.assetsBucketName, sourceKey)),
 		Key:        &destinationKey,
 	})
+	metrics.ObserveSince(metrics.ObjectStoreDuration, "copy", start, err)
 
 	if err != nil {
 		return "", ErrFailedToMoveFile
@@ -89,3 +108,150 @@ func (s *objectStoreService) MoveFile(ctx context.Context, sourceKey string, des
 
 	return "/" + s.assetsBucketName + "/" + destinationKey, nil
 }
+
+type UploadedFile struct {
+	Path          string
+	ContentLength int64
+	// Hex MD5 of the content, matching the ETag S3 reports for single part uploads
+	Hash string
+}
+
+// S3 requires every part but the last to be at least 5 MiB.
+const uploadPartSize = 8 << 20
+
+// Streams body to key without knowing its length up front. Small files are sent with a single
+// PutObject, larger ones as a multipart upload, so at most one part is held in memory.
+func (s *objectStoreService) UploadFile(ctx context.Context, key string, contentType string, body io.Reader, maxSize int64) (*UploadedFile, error) {
+	start := time.Now()
+	file, err := s.uploadFile(ctx, key, contentType, body, maxSize)
+	metrics.ObserveSince(metrics.ObjectStoreDuration, "upload", start, err)
+
+	return file, err
+}
+
+func (s *objectStoreService) uploadFile(ctx context.Context, key string, contentType string, body io.Reader, maxSize int64) (*UploadedFile, error) {
+	hash := md5.New()
+	// Read one byte past the limit so oversized files can be told apart from files exactly at it
+	r := io.TeeReader(io.LimitReader(body, maxSize+1), hash)
+	buf := make([]byte, uploadPartSize)
+
+	n, err := io.ReadFull(r, buf)
+
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	if int64(n) > maxSize {
+		return nil, ErrFileTooLarge
+	}
+
+	if n < uploadPartSize {
+		_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+			Bucket:        &s.assetsBucketName,
+			Key:           &key,
+			Body:          bytes.NewReader(buf[:n]),
+			ContentType:   aws.String(contentType),
+			ContentLength: aws.Int64(int64(n)),
+		})
+
+		if err != nil {
+			return nil, err
+		}
+
+		return &UploadedFile{
+			Path:          "/" + s.assetsBucketName + "/" + key,
+			ContentLength: int64(n),
+			Hash:          hex.EncodeToString(hash.Sum(nil)),
+		}, nil
+	}
+
+	upload, err := s.client.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{
+		Bucket:      &s.assetsBucketName,
+		Key:         &key,
+		ContentType: aws.String(contentType),
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	size, parts, err := s.uploadParts(ctx, key, upload.UploadId, r, buf, n, maxSize)
+
+	if err != nil {
+		// Use a fresh context so the abort still happens when the request was cancelled
+		_, _ = s.client.AbortMultipartUpload(context.WithoutCancel(ctx), &s3.AbortMultipartUploadInput{
+			Bucket:   &s.assetsBucketName,
+			Key:      &key,
+			UploadId: upload.UploadId,
+		})
+		return nil, err
+	}
+
+	_, err = s.client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          &s.assetsBucketName,
+		Key:             &key,
+		UploadId:        upload.UploadId,
+		MultipartUpload: &types.CompletedMultipartUpload{Parts: parts},
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &UploadedFile{
+		Path:          "/" + s.assetsBucketName + "/" + key,
+		ContentLength: size,
+		Hash:          hex.EncodeToString(hash.Sum(nil)),
+	}, nil
+}
+
+// Uploads the already buffered first part and then the rest of r, returning the total size.
+func (s *objectStoreService) uploadParts(ctx context.Context, key string, uploadId *string, r io.Reader, buf []byte, n int, maxSize int64) (int64, []types.CompletedPart, error) {
+	var parts []types.CompletedPart
+	var size int64
+
+	for partNumber := int32(1); n > 0; partNumber++ {
+		size += int64(n)
+
+		if size > maxSize {
+			return 0, nil, ErrFileTooLarge
+		}
+
+		part, err := s.client.UploadPart(ctx, &s3.UploadPartInput{
+			Bucket:        &s.assetsBucketName,
+			Key:           &key,
+			UploadId:      uploadId,
+			PartNumber:    aws.Int32(partNumber),
+			Body:          bytes.NewReader(buf[:n]),
+			ContentLength: aws.Int64(int64(n)),
+		})
+
+		if err != nil {
+			return 0, nil, err
+		}
+
+		parts = append(parts, types.CompletedPart{
+			ETag:       part.ETag,
+			PartNumber: aws.Int32(partNumber),
+		})
+
+		n, err = io.ReadFull(r, buf)
+
+		if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+			return 0, nil, err
+		}
+	}
+
+	return size, parts, nil
+}
+
+func (s *objectStoreService) DeleteFile(ctx context.Context, key string) error {
+	start := time.Now()
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: &s.assetsBucketName,
+		Key:    &key,
+	})
+	metrics.ObserveSince(metrics.ObjectStoreDuration, "delete", start, err)
+
+	return err
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/terraforge-gg/terraforge/internal/background"
+	custom_errors "github.com/terraforge-gg/terraforge/internal/errors"
+	"github.com/terraforge-gg/terraforge/internal/models"
+	"github.com/terraforge-gg/terraforge/internal/pagination"
+	"github.com/terraforge-gg/terraforge/internal/repository"
+	"github.com/terraforge-gg/terraforge/internal/utils"
+)
+
+type PersonalAccessTokenService interface {
+	CreateToken(ctx context.Context, params CreateTokenParams) (*models.PersonalAccessToken, string, error)
+	GetTokens(ctx context.Context, userId string, page pagination.Params) (pagination.Page[models.PersonalAccessToken], error)
+	RevokeToken(ctx context.Context, userId string, tokenId string) error
+	Authenticate(ctx context.Context, rawToken string) (*models.PersonalAccessToken, error)
+}
+
+type personalAccessTokenService struct {
+	logger      *slog.Logger
+	db          *sql.DB
+	tokenRepo   repository.PersonalAccessTokenRepository
+	projectRepo repository.ProjectRepository
+	tasks       *background.Group
+}
+
+func NewPersonalAccessTokenService(logger *slog.Logger, db *sql.DB, tokenRepo repository.PersonalAccessTokenRepository, projectRepo repository.ProjectRepository, tasks *background.Group) PersonalAccessTokenService {
+	return &personalAccessTokenService{logger: logger, db: db, tokenRepo: tokenRepo, projectRepo: projectRepo, tasks: tasks}
+}
+
+// lastUsedAt is only written when it is older than this, so busy tokens do not update the row on every request.
+const tokenLastUsedResolution = time.Minute
+
+type CreateTokenParams struct {
+	UserId             string
+	Name               string
+	Scopes             []models.TokenScope
+	ProjectIdentifiers []string
+	ExpiresAt          *time.Time
+}
+
+// Creates a token and returns it alongside the raw token, which is not stored and cannot be shown again.
+func (s *personalAccessTokenService) CreateToken(ctx context.Context, params CreateTokenParams) (*models.PersonalAccessToken, string, error) {
+	projectIds := make([]string, 0, len(params.ProjectIdentifiers))
+
+	for _, identifier := range params.ProjectIdentifiers {
+		project, err := s.projectRepo.FindProjectByIdentifier(ctx, s.db, identifier, params.UserId)
+
+		if err != nil {
+			return nil, "", err
+		}
+
+		if project == nil {
+			return nil, "", custom_errors.ErrPersonalAccessTokenProjectNotFound.With("project", identifier)
+		}
+
+		member, err := s.projectRepo.FindProjectMemberByProjectIdAndUserId(ctx, s.db, project.Id, params.UserId)
+
+		if err != nil {
+			return nil, "", err
+		}
+
+		if member == nil {
+			return nil, "", custom_errors.ErrPersonalAccessTokenProjectNotFound.With("project", identifier)
+		}
+
+		projectIds = append(projectIds, project.Id)
+	}
+
+	secret, err := generateTokenSecret()
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	rawToken := models.PersonalAccessTokenPrefix + secret
+
+	token := &models.PersonalAccessToken{
+		Id:          utils.NewUUID(),
+		UserId:      params.UserId,
+		Name:        params.Name,
+		TokenHash:   hashToken(rawToken),
+		TokenPrefix: rawToken[:len(models.PersonalAccessTokenPrefix)+6],
+		Scopes:      params.Scopes,
+		ProjectIds:  projectIds,
+		ExpiresAt:   params.ExpiresAt,
+		CreatedAt:   time.Now().UTC(),
+	}
+
+	err = s.tokenRepo.InsertToken(ctx, s.db, token)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	return token, rawToken, nil
+}
+
+func (s *personalAccessTokenService) GetTokens(ctx context.Context, userId string, page pagination.Params) (pagination.Page[models.PersonalAccessToken], error) {
+	return s.tokenRepo.FindTokensByUserId(ctx, s.db, userId, page)
+}
+
+func (s *personalAccessTokenService) RevokeToken(ctx context.Context, userId string, tokenId string) error {
+	revoked, err := s.tokenRepo.RevokeToken(ctx, s.db, tokenId, userId, time.Now().UTC())
+
+	if err != nil {
+		return err
+	}
+
+	if !revoked {
+		return custom_errors.ErrPersonalAccessTokenNotFound
+	}
+
+	return nil
+}
+
+func (s *personalAccessTokenService) Authenticate(ctx context.Context, rawToken string) (*models.PersonalAccessToken, error) {
+	if !strings.HasPrefix(rawToken, models.PersonalAccessTokenPrefix) {
+		return nil, custom_errors.ErrPersonalAccessTokenInvalid
+	}
+
+	token, err := s.tokenRepo.FindTokenByHash(ctx, s.db, hashToken(rawToken))
+
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+
+	if token == nil || !token.IsActive(now) {
+		return nil, custom_errors.ErrPersonalAccessTokenInvalid
+	}
+
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) > tokenLastUsedResolution {
+		s.tasks.Go(ctx, func(ctx context.Context) {
+			err := s.tokenRepo.UpdateTokenLastUsedAt(ctx, s.db, token.Id, now)
+			if err != nil {
+				s.logger.ErrorContext(ctx, "Failed to update personal access token last used time.", "tokenId", token.Id, "error", err)
+			}
+		})
+	}
+
+	return token, nil
+}
+
+func generateTokenSecret() (string, error) {
+	b := make([]byte, 32)
+
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Tokens carry 256 bits of entropy, so a fast unsalted hash is enough to protect them at rest.
+func hashToken(rawToken string) string {
+	sum := sha256.Sum256([]byte(rawToken))
+	return hex.EncodeToString(sum[:])
+}
@@ -7,21 +7,25 @@ import (
 	"log/slog"
 	"time"
 
+	"github.com/terraforge-gg/terraforge/internal/background"
 	"github.com/terraforge-gg/terraforge/internal/cache"
 	"github.com/terraforge-gg/terraforge/internal/database"
 	custom_errors "github.com/terraforge-gg/terraforge/internal/errors"
 	"github.com/terraforge-gg/terraforge/internal/models"
+	"github.com/terraforge-gg/terraforge/internal/pagination"
 	"github.com/terraforge-gg/terraforge/internal/repository"
 	"github.com/terraforge-gg/terraforge/internal/utils"
+	"golang.org/x/sync/singleflight"
 )
 
 type ProjectService interface {
 	CreateUserProject(ctx context.Context, params CreateUserProjectParams) (*models.Project, error)
 	GetProjectByIdentifier(ctx context.Context, params GetProjectByIdentifierParams) (*models.Project, error)
-	GetProjectMembers(ctx context.Context, params GetProjectMembersParams) ([]models.ProjectMember, error)
+	GetProjectMembers(ctx context.Context, params GetProjectMembersParams) (pagination.Page[models.ProjectMember], error)
+	AddProjectMember(ctx context.Context, params AddProjectMemberParams) (*models.ProjectMember, error)
 	UpdateProject(ctx context.Context, params UpdateProjectParams) (*models.Project, error)
 	DeleteProject(ctx context.Context, params DeleteProjectParams) error
-	GetProjectsByUserIdentifier(ctx context.Context, params GetProjectsByUserIdentifierParams) ([]models.Project, error)
+	GetProjectsByUserIdentifier(ctx context.Context, params GetProjectsByUserIdentifierParams) (pagination.Page[models.Project], error)
 }
 
 type projectService struct {
@@ -31,10 +35,25 @@ type projectService struct {
 	searchRepo   repository.SearchRepository
 	projectCache cache.ProjectCache
 	userRepo     repository.UserRepository
+	tasks        *background.Group
+	// Coalesces concurrent repository loads for the same key so an expiring
+	// cache entry results in a single Postgres query.
+	loads singleflight.Group
 }
 
-func NewProjectService(logger *slog.Logger, db *sql.DB, projectRepo repository.ProjectRepository, searchRepo repository.SearchRepository, projectCache cache.ProjectCache, userRepo repository.UserRepository) ProjectService {
-	return &projectService{logger: logger, db: db, projectRepo: projectRepo, searchRepo: searchRepo, projectCache: projectCache, userRepo: userRepo}
+const (
+	projectCacheTTL = 5 * time.Minute
+	// Bounds background refreshes and the shared loads behind cache misses
+	cacheRefreshTimeout = 10 * time.Second
+)
+
+// Only the page most requests are for, the first one at the default limit, is cached.
+func isCachedPage(page pagination.Params) bool {
+	return page.IsFirst() && page.Limit == pagination.DefaultLimit
+}
+
+func NewProjectService(logger *slog.Logger, db *sql.DB, projectRepo repository.ProjectRepository, searchRepo repository.SearchRepository, projectCache cache.ProjectCache, userRepo repository.UserRepository, tasks *background.Group) ProjectService {
+	return &projectService{logger: logger, db: db, projectRepo: projectRepo, searchRepo: searchRepo, projectCache: projectCache, userRepo: userRepo, tasks: tasks}
 }
 
 type CreateUserProjectParams struct {
@@ -102,12 +121,16 @@ func (s *projectService) CreateUserProject(ctx context.Context, params CreateUse
 	}
 
 	if project.Status == models.ProjectStatusApproved {
-		go func() {
-			err = s.searchRepo.IndexProject(context.Background(), project)
+		s.tasks.Go(ctx, func(ctx context.Context) {
+			err := s.searchRepo.IndexProject(ctx, project)
 			if err != nil {
-				s.logger.Error("Failed to index project.", "Project: ", project, "Error: ", err)
+				s.logger.ErrorContext(ctx, "Failed to index project.", "projectId", project.Id, "error", err)
 			}
-		}()
+		})
+
+		s.tasks.Go(ctx, func(ctx context.Context) {
+			s.syncOwnerSearchDocument(ctx, project.UserId)
+		})
 	}
 
 	return project, nil
@@ -125,65 +148,233 @@ func (s *projectService) GetProjectByIdentifier(ctx context.Context, params GetP
 		return project, nil
 	}
 
+	if errors.Is(err, cache.ErrCacheStale) {
+		s.tasks.Go(ctx, func(ctx context.Context) {
+			s.refreshProject(ctx, params.Identifier, project.Id)
+		})
+		return project, nil
+	}
+
+	// Cache failures fall back to the database rather than failing the request
 	if !errors.Is(err, cache.ErrCacheMiss) {
-		return nil, err
+		s.logger.WarnContext(ctx, "Failed to read project from cache", "identifier", params.Identifier, "error", err)
 	}
 
-	project, err = s.projectRepo.FindProjectByIdentifier(ctx, s.db, params.Identifier, params.UserId)
+	return s.loadProject(ctx, params.Identifier, params.UserId)
+}
+
+func (s *projectService) loadProject(ctx context.Context, identifier string, userId string) (*models.Project, error) {
+	// Draft visibility depends on the user, so only requests from the same user share a load
+	v, err, _ := s.loads.Do("project:"+identifier+":"+userId, func() (any, error) {
+		// Detach from the caller so one cancelled request does not fail every waiter, with a
+		// deadline of its own so a stuck query does not hold the load for every later caller
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), cacheRefreshTimeout)
+		defer cancel()
+
+		project, err := s.projectRepo.FindProjectByIdentifier(ctx, s.db, identifier, userId)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if project == nil {
+			return nil, custom_errors.ErrProjectNotFound
+		}
+
+		if project.Status == models.ProjectStatusApproved {
+			_ = s.projectCache.SetProject(ctx, project, projectCacheTTL)
+		}
+
+		return project, nil
+	})
 
 	if err != nil {
 		return nil, err
 	}
 
-	if project == nil {
-		return nil, custom_errors.ErrProjectNotFound
-	}
+	return v.(*models.Project), nil
+}
 
-	if project.Status == models.ProjectStatusApproved {
-		_ = s.projectCache.SetProject(ctx, project, 5*time.Minute)
+// Only approved projects are cached, so refreshes load the public view of the project.
+func (s *projectService) refreshProject(ctx context.Context, identifier string, projectId string) {
+	ctx, cancel := context.WithTimeout(ctx, cacheRefreshTimeout)
+	defer cancel()
+
+	_, err := s.loadProject(ctx, identifier, "")
+
+	if errors.Is(err, custom_errors.ErrProjectNotFound) {
+		err = s.projectCache.DeleteProject(ctx, projectId)
 	}
 
-	return project, nil
+	if err != nil {
+		s.logger.WarnContext(ctx, "Failed to refresh cached project", "identifier", identifier, "error", err)
+	}
 }
 
 type GetProjectMembersParams struct {
 	Identifier string
 	UserId     string
+	Page       pagination.Params
 }
 
-func (s *projectService) GetProjectMembers(ctx context.Context, params GetProjectMembersParams) ([]models.ProjectMember, error) {
+func (s *projectService) GetProjectMembers(ctx context.Context, params GetProjectMembersParams) (pagination.Page[models.ProjectMember], error) {
 	project, err := s.GetProjectByIdentifier(ctx, GetProjectByIdentifierParams{
 		Identifier: params.Identifier,
 		UserId:     params.UserId,
 	})
 
 	if err != nil {
-		return nil, err
+		return pagination.Page[models.ProjectMember]{}, err
 	}
 
 	if project == nil {
-		return nil, custom_errors.ErrProjectNotFound
+		return pagination.Page[models.ProjectMember]{}, custom_errors.ErrProjectNotFound
 	}
 
-	if projectMembers, err := s.projectCache.GetProjectMembers(ctx, params.Identifier); err == nil {
+	if !isCachedPage(params.Page) {
+		return s.projectRepo.FindProjectMembersByProjectIdentifier(ctx, s.db, project.Id, params.UserId, params.Page)
+	}
+
+	projectMembers, err := s.projectCache.GetProjectMembers(ctx, params.Identifier)
+
+	if err == nil {
 		return projectMembers, nil
 	}
 
-	members, err := s.projectRepo.FindProjectMembersByProjectIdentifier(ctx, s.db, params.Identifier, params.UserId)
+	if errors.Is(err, cache.ErrCacheStale) {
+		s.tasks.Go(ctx, func(ctx context.Context) {
+			s.refreshProjectMembers(ctx, project)
+		})
+		return projectMembers, nil
+	}
+
+	return s.loadProjectMembers(ctx, project, params.Identifier, params.UserId)
+}
+
+// Loads the first page of a project's members, caching it when the project is approved.
+func (s *projectService) loadProjectMembers(ctx context.Context, project *models.Project, identifier string, userId string) (pagination.Page[models.ProjectMember], error) {
+	v, err, _ := s.loads.Do("members:"+identifier+":"+userId, func() (any, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), cacheRefreshTimeout)
+		defer cancel()
+
+		members, err := s.projectRepo.FindProjectMembersByProjectIdentifier(ctx, s.db, identifier, userId, pagination.Params{Limit: pagination.DefaultLimit})
+
+		if err != nil {
+			return nil, err
+		}
+
+		// Every project has an owner, so no members means the project is not visible to the user
+		if len(members.Items) == 0 {
+			return nil, custom_errors.ErrProjectNotFound
+		}
+
+		if project.Status == models.ProjectStatusApproved {
+			_ = s.projectCache.SetProjectMembers(ctx, project, members, projectCacheTTL)
+		}
+
+		return members, nil
+	})
+
+	if err != nil {
+		return pagination.Page[models.ProjectMember]{}, err
+	}
+
+	return v.(pagination.Page[models.ProjectMember]), nil
+}
+
+func (s *projectService) refreshProjectMembers(ctx context.Context, project *models.Project) {
+	ctx, cancel := context.WithTimeout(ctx, cacheRefreshTimeout)
+	defer cancel()
+
+	_, err := s.loadProjectMembers(ctx, project, project.Id, "")
+
+	if err != nil {
+		s.logger.WarnContext(ctx, "Failed to refresh cached project members", "projectId", project.Id, "error", err)
+	}
+}
+
+type AddProjectMemberParams struct {
+	Identifier string
+	// Id or username of the user to add
+	MemberIdentifier string
+	Role             models.ProjectMemberRole
+	UserId           string
+}
+
+// Adds a user to the project with the given role. Only the owner can add members.
+func (s *projectService) AddProjectMember(ctx context.Context, params AddProjectMemberParams) (*models.ProjectMember, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback()
+
+	project, err := s.projectRepo.FindProjectByIdentifier(ctx, tx, params.Identifier, params.UserId)
 
 	if err != nil {
 		return nil, err
 	}
 
-	if members == nil {
+	if project == nil {
 		return nil, custom_errors.ErrProjectNotFound
 	}
 
-	if project.Status == models.ProjectStatusApproved {
-		_ = s.projectCache.SetProjectMembers(ctx, project, members, 5*time.Minute)
+	projectMember, err := s.projectRepo.FindProjectMemberByProjectIdAndUserId(ctx, tx, project.Id, params.UserId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if projectMember == nil {
+		return nil, custom_errors.ErrProjectNotFound
+	}
+
+	if projectMember.Role != models.ProjectMemberRoleOwner {
+		return nil, custom_errors.ErrProjectUnauthorisedAction
+	}
+
+	user, err := s.userRepo.FindUserByIdentifier(ctx, tx, params.MemberIdentifier)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil || user.Banned {
+		return nil, custom_errors.ErrUserNotFound
+	}
+
+	member := &models.ProjectMember{
+		Id:        utils.NewUUID(),
+		ProjectId: project.Id,
+		UserId:    user.Id,
+		Role:      params.Role,
+		CreatedAt: time.Now().UTC(),
+		User:      *user,
 	}
 
-	return members, nil
+	err = s.projectRepo.InsertProjectMember(ctx, tx, member)
+
+	if err != nil {
+		if errors.Is(err, database.ErrUniqueViolation) {
+			return nil, custom_errors.ErrProjectMemberExists
+		}
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	// Drops the cached members pages along with the project
+	err = s.projectCache.DeleteProject(ctx, project.Id)
+
+	if err != nil {
+		s.logger.WarnContext(ctx, "Failed to delete project from cache", "error", err)
+	}
+
+	return member, nil
 }
 
 type UpdateProjectParams struct {
@@ -273,16 +464,27 @@ func (s *projectService) UpdateProject(ctx context.Context, params UpdateProject
 		return nil, err
 	}
 
+	// Drop the old slug and members keys before caching the updated project
+	err = s.projectCache.DeleteProject(ctx, project.Id)
+
+	if err != nil {
+		s.logger.WarnContext(ctx, "Failed to delete project from cache", "error", err)
+	}
+
 	if project.Status == models.ProjectStatusApproved {
-		err = s.projectCache.SetProject(ctx, project, 5*time.Minute)
+		err = s.projectCache.SetProject(ctx, project, projectCacheTTL)
+
+		if err != nil {
+			s.logger.WarnContext(ctx, "Failed to cache project", "error", err)
+		}
 	}
 
-	go func() {
-		err = s.searchRepo.UpdateProject(context.Background(), project)
+	s.tasks.Go(ctx, func(ctx context.Context) {
+		err := s.searchRepo.UpdateProject(ctx, project)
 		if err != nil {
-			s.logger.Error("Failed to update project document.", "Project: ", project, "Error: ", err)
+			s.logger.ErrorContext(ctx, "Failed to update project document.", "projectId", project.Id, "error", err)
 		}
-	}()
+	})
 
 	return project, nil
 }
@@ -341,15 +543,21 @@ func (s *projectService) DeleteProject(ctx context.Context, params DeleteProject
 	err = s.projectCache.DeleteProject(ctx, project.Id)
 
 	if err != nil {
-		s.logger.Warn("Failed to delete project from cache", "Error", err)
+		s.logger.WarnContext(ctx, "Failed to delete project from cache", "error", err)
 	}
 
-	go func() {
-		err = s.searchRepo.DeleteProject(context.Background(), project.Id)
+	s.tasks.Go(ctx, func(ctx context.Context) {
+		err := s.searchRepo.DeleteProject(ctx, project.Id)
 		if err != nil {
-			s.logger.Error("Failed to delete project document.", "Project: ", project, "Error: ", err)
+			s.logger.ErrorContext(ctx, "Failed to delete project document.", "projectId", project.Id, "error", err)
 		}
-	}()
+	})
+
+	if project.Status == models.ProjectStatusApproved {
+		s.tasks.Go(ctx, func(ctx context.Context) {
+			s.syncOwnerSearchDocument(ctx, project.UserId)
+		})
+	}
 
 	return nil
 }
@@ -357,17 +565,18 @@ func (s *projectService) DeleteProject(ctx context.Context, params DeleteProject
 type GetProjectsByUserIdentifierParams struct {
 	UserIdentifier string
 	SessionUserId  string
+	Page           pagination.Params
 }
 
-func (s *projectService) GetProjectsByUserIdentifier(ctx context.Context, params GetProjectsByUserIdentifierParams) ([]models.Project, error) {
+func (s *projectService) GetProjectsByUserIdentifier(ctx context.Context, params GetProjectsByUserIdentifierParams) (pagination.Page[models.Project], error) {
 	user, err := s.userRepo.FindUserByIdentifier(ctx, s.db, params.UserIdentifier)
 
 	if err != nil {
-		return nil, err
+		return pagination.Page[models.Project]{}, err
 	}
 
 	if user == nil {
-		return nil, custom_errors.ErrUserNotFound
+		return pagination.Page[models.Project]{}, custom_errors.ErrUserNotFound
 	}
 
 	var status = models.ProjectStatusApproved
@@ -376,15 +585,24 @@ func (s *projectService) GetProjectsByUserIdentifier(ctx context.Context, params
 		status = models.ProjectStatusDraft
 	}
 
-	projects, err := s.projectRepo.FindProjectsByUserIdentifier(ctx, s.db, params.UserIdentifier, status)
+	projects, err := s.projectRepo.FindProjectsByUserIdentifier(ctx, s.db, params.UserIdentifier, status, params.Page)
 
 	if err != nil {
-		return nil, err
+		return pagination.Page[models.Project]{}, err
 	}
 
-	if projects == nil {
-		return nil, custom_errors.ErrNoProjectsFound
+	// Only the first page can show the user has no projects, a later one is just past the end
+	if len(projects.Items) == 0 && params.Page.IsFirst() {
+		return pagination.Page[models.Project]{}, custom_errors.ErrNoProjectsFound
 	}
 
 	return projects, nil
 }
+
+// Re-indexes the owner's user document so project count and download totals stay current.
+func (s *projectService) syncOwnerSearchDocument(ctx context.Context, userId string) {
+	err := syncUserSearchDocument(ctx, s.db, s.userRepo, s.searchRepo, userId)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to sync user search document.", "userId", userId, "error", err)
+	}
+}
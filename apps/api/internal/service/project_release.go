@@ -1,21 +1,28 @@
 package service
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"net/url"
 	"strconv"
 	"time"
 
+	"github.com/terraforge-gg/terraforge/internal/background"
+	"github.com/terraforge-gg/terraforge/internal/cache"
 	"github.com/terraforge-gg/terraforge/internal/database"
 	custom_errors "github.com/terraforge-gg/terraforge/internal/errors"
 	"github.com/terraforge-gg/terraforge/internal/lib/aws"
+	"github.com/terraforge-gg/terraforge/internal/lib/tmod"
 	"github.com/terraforge-gg/terraforge/internal/models"
+	"github.com/terraforge-gg/terraforge/internal/pagination"
 	"github.com/terraforge-gg/terraforge/internal/repository"
 	"github.com/terraforge-gg/terraforge/internal/utils"
+	"golang.org/x/sync/singleflight"
 )
 
 type CreateProjectReleaseDependencyParams struct {
@@ -37,7 +44,8 @@ type ProjectReleaseService interface {
 	GetReleaseByIdWithDependencies(ctx context.Context, projectIdentifier string, releaseId string, userId string) (*models.ProjectRelease, error)
 	CreateRelease(ctx context.Context, projectIdentifier string, userId string, params CreateReleaseParams) (*models.ProjectRelease, error)
 	GenerateProjectReleasePresignedPutUrl(ctx context.Context, projectIdentifier string, userId string, fileSize string) (string, error)
-	GetReleasesByProjectId(ctx context.Context, id string, userId string) ([]models.ProjectRelease, error)
+	GetReleasesByProjectId(ctx context.Context, id string, userId string, page pagination.Params) (pagination.Page[models.ProjectRelease], error)
+	PublishRelease(ctx context.Context, projectIdentifier string, userId string, params PublishReleaseParams) (*models.ProjectRelease, error)
 }
 
 type projectReleaseService struct {
@@ -48,8 +56,13 @@ type projectReleaseService struct {
 	projectReleaseRepo repository.ProjectReleaseRepository
 	loaderVersionRepo  repository.LoaderVersionRepository
 	objectStoreService ObjectStoreService
+	releaseCache       cache.ReleaseCache
+	tasks              *background.Group
+	loads              singleflight.Group
 }
 
+const releaseCacheTTL = 5 * time.Minute
+
 func NewProjectReleaseService(
 	logger *slog.Logger,
 	cdnUrl string,
@@ -57,7 +70,9 @@ func NewProjectReleaseService(
 	projectRepo repository.ProjectRepository,
 	projectReleaseRepo repository.ProjectReleaseRepository,
 	loaderVersionRepo repository.LoaderVersionRepository,
-	objectStoreService ObjectStoreService) ProjectReleaseService {
+	objectStoreService ObjectStoreService,
+	releaseCache cache.ReleaseCache,
+	tasks *background.Group) ProjectReleaseService {
 	return &projectReleaseService{
 		logger:             logger,
 		cdnUrl:             cdnUrl,
@@ -66,6 +81,8 @@ func NewProjectReleaseService(
 		projectReleaseRepo: projectReleaseRepo,
 		loaderVersionRepo:  loaderVersionRepo,
 		objectStoreService: objectStoreService,
+		releaseCache:       releaseCache,
+		tasks:              tasks,
 	}
 }
 
@@ -80,91 +97,316 @@ func (s *projectReleaseService) GetReleaseByIdWithDependencies(ctx context.Conte
 		return nil, custom_errors.ErrProjectNotFound
 	}
 
-	release, err := s.projectReleaseRepo.FindReleaseByIdWithDependencies(ctx, s.db, releaseId)
+	// Only releases of approved projects are cached, drafts are always read from the database
+	if project.Status != models.ProjectStatusApproved {
+		return s.loadRelease(ctx, project, releaseId)
+	}
+
+	release, err := s.releaseCache.GetRelease(ctx, releaseId)
+
+	// Releases are cached by id alone, so one of another project must not be served here
+	if (err == nil || errors.Is(err, cache.ErrCacheStale)) && release.ProjectId != project.Id {
+		return nil, custom_errors.ErrProjectReleaseNotFound
+	}
+
+	if err == nil {
+		return release, nil
+	}
+
+	if errors.Is(err, cache.ErrCacheStale) {
+		s.tasks.Go(ctx, func(ctx context.Context) {
+			s.refreshRelease(ctx, project, releaseId)
+		})
+		return release, nil
+	}
+
+	if !errors.Is(err, cache.ErrCacheMiss) {
+		s.logger.WarnContext(ctx, "Failed to read release from cache", "releaseId", releaseId, "error", err)
+	}
+
+	return s.loadRelease(ctx, project, releaseId)
+}
+
+func (s *projectReleaseService) loadRelease(ctx context.Context, project *models.Project, releaseId string) (*models.ProjectRelease, error) {
+	v, err, _ := s.loads.Do("release:"+project.Id+":"+releaseId, func() (any, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), cacheRefreshTimeout)
+		defer cancel()
+
+		release, err := s.projectReleaseRepo.FindReleaseByIdWithDependencies(ctx, s.db, releaseId)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if release == nil || release.ProjectId != project.Id {
+			return nil, custom_errors.ErrProjectReleaseNotFound
+		}
+
+		if project.Status == models.ProjectStatusApproved {
+			_ = s.releaseCache.SetRelease(ctx, release, releaseCacheTTL)
+		}
+
+		return release, nil
+	})
 
 	if err != nil {
 		return nil, err
 	}
 
-	if release == nil {
-		return nil, custom_errors.ErrProjectReleaseNotFound
+	return v.(*models.ProjectRelease), nil
+}
+
+func (s *projectReleaseService) refreshRelease(ctx context.Context, project *models.Project, releaseId string) {
+	ctx, cancel := context.WithTimeout(ctx, cacheRefreshTimeout)
+	defer cancel()
+
+	_, err := s.loadRelease(ctx, project, releaseId)
+
+	if errors.Is(err, custom_errors.ErrProjectReleaseNotFound) {
+		err = s.releaseCache.DeleteRelease(ctx, project.Id, releaseId)
 	}
 
-	return release, nil
+	if err != nil {
+		s.logger.WarnContext(ctx, "Failed to refresh cached release", "releaseId", releaseId, "error", err)
+	}
 }
 
 func (s *projectReleaseService) CreateRelease(ctx context.Context, projectIdentifier string, userId string, params CreateReleaseParams) (*models.ProjectRelease, error) {
 	tx, err := s.db.BeginTx(ctx, nil)
 
 	if err != nil {
-		s.logger.Error("Create release failed. Failed to begin transaction.", "User Id", userId, "Project Identifier", projectIdentifier, "error", err)
+		s.logger.ErrorContext(ctx, "Create release failed. Failed to begin transaction.", "userId", userId, "projectIdentifier", projectIdentifier, "error", err)
 		return nil, err
 	}
 
 	defer tx.Rollback()
 
-	project, err := s.projectRepo.FindProjectByIdentifier(ctx, tx, projectIdentifier, userId)
+	project, loaderVersion, err := s.authorizeRelease(ctx, tx, projectIdentifier, userId, params.LoaderVersionId)
 
 	if err != nil {
-		s.logger.Error("Create release failed. Failed to find project by identifier.", "User Id", userId, "Project Identifier", projectIdentifier, "error", err)
 		return nil, err
 	}
 
-	if project == nil {
-		s.logger.Info("Create release failed. Project not found.", "User Id", userId, "Project Identifier", projectIdentifier, "error", err)
-		return nil, custom_errors.ErrProjectNotFound
+	parsedFileUrl, err := url.Parse(params.FileUrl)
+	sourceKey := aws.ExtractS3Key(parsedFileUrl.Path)
+
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Create release failed. Failed to parse file url.", "userId", userId, "projectIdentifier", projectIdentifier, "fileUrl", params.FileUrl, "error", err)
+		return nil, custom_errors.ErrProjectReleaseFailedToParseFileUrl
 	}
 
-	projectMember, err := s.projectRepo.FindProjectMemberByProjectIdAndUserId(ctx, tx, project.Id, userId)
+	metadata, err := s.objectStoreService.GetFileMetadate(ctx, sourceKey)
+
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Create release failed. Uploaded file not found", "userId", userId, "projectIdentifier", projectIdentifier, "fileUrl", params.FileUrl, "error", err)
+		return nil, custom_errors.ErrProjectReleaseUploadedFileNotFound
+	}
+
+	release := newProjectRelease(project, loaderVersion, params.Name, params.VersionNumber, params.Changelog)
+	release.FileSize = metadata.ContentLength
+	release.FileHash = metadata.ETag
+
+	destinationKey := releaseFileKey(userId, project, &release)
+
+	newPath, err := s.objectStoreService.MoveFile(ctx, sourceKey, destinationKey)
 
 	if err != nil {
-		s.logger.Error("Create release failed. Failed to find project members.", "User Id", userId, "Project Identifier", projectIdentifier, "error", err)
+		s.logger.ErrorContext(ctx, "Failed to move file.", "sourceKey", sourceKey, "destinationKey", destinationKey, "error", err)
 		return nil, err
 	}
 
-	if projectMember == nil {
-		s.logger.Warn("Create release failed. Project members not found.", "User Id", userId, "Project Identifier", projectIdentifier, "error", err)
-		return nil, custom_errors.ErrProjectUnauthorisedAction
+	release.FileUrl = s.cdnUrl + newPath
+
+	err = s.insertRelease(ctx, tx, project, userId, &release, params.Dependencies)
+
+	if err != nil {
+		return nil, err
 	}
 
-	if projectMember.Role != models.ProjectMemberRoleOwner {
-		s.logger.Warn("Create release failed. Project member does not have access to create a release", "User Id", userId, "Project member role", projectMember.Role, "Project Identifier", projectIdentifier, "error", err)
-		return nil, custom_errors.ErrProjectUnauthorisedAction
+	err = tx.Commit()
+
+	if err != nil {
+		return nil, err
 	}
 
-	loaderVersion, err := s.loaderVersionRepo.FindLoaderVersionById(ctx, tx, params.LoaderVersionId)
+	s.invalidateReleases(ctx, project.Id)
+
+	return &release, nil
+}
+
+type PublishReleaseParams struct {
+	Name            string
+	VersionNumber   string
+	Changelog       *string
+	LoaderVersionId string
+	Dependencies    []CreateProjectReleaseDependencyParams
+	File            io.Reader
+}
+
+// Largest release file accepted, for both presigned and direct uploads. The publish request
+// limit and the CLI are derived from it.
+const MaxReleaseFileSize = 524_288_000
+
+// Creates a release from a .tmod streamed in the request, instead of one uploaded beforehand
+// through a presigned url. The file is streamed to a staging key before the transaction is
+// opened, so no connection is held for the whole upload, then moved to its final key once
+// the checks have been repeated in the transaction.
+func (s *projectReleaseService) PublishRelease(ctx context.Context, projectIdentifier string, userId string, params PublishReleaseParams) (*models.ProjectRelease, error) {
+	project, loaderVersion, err := s.authorizePublish(ctx, s.db, projectIdentifier, userId, params)
 
 	if err != nil {
-		s.logger.Error("Create release failed. Failed to find loader version.", "User Id", userId, "Project Identifier", projectIdentifier, "error", err)
 		return nil, err
 	}
 
-	if loaderVersion == nil {
-		s.logger.Warn("Create release failed. Loader version not found", "User Id", userId, "Project member role", projectMember.Role, "Project Identifier", projectIdentifier, "Loader version Id", params.LoaderVersionId, "error", err)
-		return nil, custom_errors.ErrLoaderVersionNotFound
+	var head bytes.Buffer
+
+	if _, err := tmod.ReadHeader(io.TeeReader(params.File, &head)); err != nil {
+		return nil, custom_errors.ErrProjectReleaseInvalidFile
 	}
 
-	parsedFileUrl, err := url.Parse(params.FileUrl)
-	sourceKey := aws.ExtractS3Key(parsedFileUrl.Path)
+	release := newProjectRelease(project, loaderVersion, params.Name, params.VersionNumber, params.Changelog)
+	stagingKey := fmt.Sprintf("uploads/temp/%s/%s.tmod", userId, release.Id)
+
+	file, err := s.objectStoreService.UploadFile(ctx, stagingKey, "application/octet-stream", io.MultiReader(&head, params.File), MaxReleaseFileSize)
 
 	if err != nil {
-		s.logger.Error("Create release failed. Failed to parse file url.", "User Id", userId, "Project Identifier", projectIdentifier, "File url", params.FileUrl, "error", err)
-		return nil, custom_errors.ErrProjectReleaseFailedToParseFileUrl
+		if errors.Is(err, ErrFileTooLarge) {
+			return nil, custom_errors.ErrProjectReleaseFileTooLarge.With("maxFileSize", MaxReleaseFileSize)
+		}
+
+		s.logger.ErrorContext(ctx, "Publish release failed. Failed to upload file.", "userId", userId, "projectIdentifier", projectIdentifier, "stagingKey", stagingKey, "error", err)
+		return nil, err
 	}
 
-	metadata, err := s.objectStoreService.GetFileMetadate(ctx, sourceKey)
+	defer s.deleteReleaseFile(ctx, stagingKey)
+
+	release.FileSize = file.ContentLength
+	release.FileHash = file.Hash
+
+	tx, err := s.db.BeginTx(ctx, nil)
 
 	if err != nil {
-		s.logger.Error("Create release failed. Uploaded file not found", "User Id", userId, "Project Identifier", projectIdentifier, "File url", params.FileUrl, "error", err)
-		return nil, custom_errors.ErrProjectReleaseUploadedFileNotFound
+		s.logger.ErrorContext(ctx, "Publish release failed. Failed to begin transaction.", "userId", userId, "projectIdentifier", projectIdentifier, "error", err)
+		return nil, err
+	}
+
+	defer tx.Rollback()
+
+	// Checked again, by id as the slug may have changed too, since the project or its releases
+	// may have changed during the upload
+	project, _, err = s.authorizePublish(ctx, tx, release.ProjectId, userId, params)
+
+	if err != nil {
+		return nil, err
+	}
+
+	destinationKey := releaseFileKey(userId, project, &release)
+	newPath, err := s.objectStoreService.MoveFile(ctx, stagingKey, destinationKey)
+
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to move file.", "sourceKey", stagingKey, "destinationKey", destinationKey, "error", err)
+		return nil, err
+	}
+
+	release.FileUrl = s.cdnUrl + newPath
+
+	err = s.insertRelease(ctx, tx, project, userId, &release, params.Dependencies)
+
+	if err == nil {
+		err = tx.Commit()
+	}
+
+	if err != nil {
+		s.deleteReleaseFile(ctx, destinationKey)
+		return nil, err
+	}
+
+	s.invalidateReleases(ctx, project.Id)
+
+	return &release, nil
+}
+
+// Checks the user may publish the release and that its version number is not taken yet.
+func (s *projectReleaseService) authorizePublish(ctx context.Context, q database.Querier, projectIdentifier string, userId string, params PublishReleaseParams) (*models.Project, *models.LoaderVersion, error) {
+	project, loaderVersion, err := s.authorizeRelease(ctx, q, projectIdentifier, userId, params.LoaderVersionId)
+
+	if err != nil {
+		return nil, nil, err
 	}
 
-	release := models.ProjectRelease{
+	existing, err := s.projectReleaseRepo.FindByProjectIdAndVersionNumber(ctx, q, project.Id, params.VersionNumber)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if existing != nil {
+		return nil, nil, custom_errors.ErrProjectReleaseNumberAlreadyExists.With("versionNumber", params.VersionNumber)
+	}
+
+	return project, loaderVersion, nil
+}
+
+// Removes a release file that was uploaded or moved for a release that was not created.
+func (s *projectReleaseService) deleteReleaseFile(ctx context.Context, key string) {
+	if err := s.objectStoreService.DeleteFile(context.WithoutCancel(ctx), key); err != nil {
+		s.logger.ErrorContext(ctx, "Failed to delete uploaded release file.", "key", key, "error", err)
+	}
+}
+
+// Checks the user owns the project and the loader version exists.
+func (s *projectReleaseService) authorizeRelease(ctx context.Context, q database.Querier, projectIdentifier string, userId string, loaderVersionId string) (*models.Project, *models.LoaderVersion, error) {
+	project, err := s.projectRepo.FindProjectByIdentifier(ctx, q, projectIdentifier, userId)
+
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Create release failed. Failed to find project by identifier.", "userId", userId, "projectIdentifier", projectIdentifier, "error", err)
+		return nil, nil, err
+	}
+
+	if project == nil {
+		s.logger.InfoContext(ctx, "Create release failed. Project not found.", "userId", userId, "projectIdentifier", projectIdentifier)
+		return nil, nil, custom_errors.ErrProjectNotFound
+	}
+
+	projectMember, err := s.projectRepo.FindProjectMemberByProjectIdAndUserId(ctx, q, project.Id, userId)
+
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Create release failed. Failed to find project members.", "userId", userId, "projectIdentifier", projectIdentifier, "error", err)
+		return nil, nil, err
+	}
+
+	if projectMember == nil {
+		s.logger.WarnContext(ctx, "Create release failed. Project members not found.", "userId", userId, "projectIdentifier", projectIdentifier)
+		return nil, nil, custom_errors.ErrProjectUnauthorisedAction
+	}
+
+	if projectMember.Role != models.ProjectMemberRoleOwner {
+		s.logger.WarnContext(ctx, "Create release failed. Project member does not have access to create a release", "userId", userId, "projectMemberRole", projectMember.Role, "projectIdentifier", projectIdentifier)
+		return nil, nil, custom_errors.ErrProjectUnauthorisedAction
+	}
+
+	loaderVersion, err := s.loaderVersionRepo.FindLoaderVersionById(ctx, q, loaderVersionId)
+
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Create release failed. Failed to find loader version.", "userId", userId, "projectIdentifier", projectIdentifier, "error", err)
+		return nil, nil, err
+	}
+
+	if loaderVersion == nil {
+		s.logger.WarnContext(ctx, "Create release failed. Loader version not found", "userId", userId, "projectMemberRole", projectMember.Role, "projectIdentifier", projectIdentifier, "loaderVersionId", loaderVersionId)
+		return nil, nil, custom_errors.ErrLoaderVersionNotFound.With("loaderVersionId", loaderVersionId)
+	}
+
+	return project, loaderVersion, nil
+}
+
+func newProjectRelease(project *models.Project, loaderVersion *models.LoaderVersion, name string, versionNumber string, changelog *string) models.ProjectRelease {
+	return models.ProjectRelease{
 		Id:              utils.NewUUID(),
 		ProjectId:       project.Id,
-		Name:            params.Name,
-		Changelog:       params.Changelog,
-		VersionNumber:   params.VersionNumber,
+		Name:            name,
+		Changelog:       changelog,
+		VersionNumber:   versionNumber,
 		LoaderVersionId: loaderVersion.Id,
 		LoaderVersion: models.LoaderVersion{
 			Id:           loaderVersion.Id,
@@ -175,72 +417,65 @@ func (s *projectReleaseService) CreateRelease(ctx context.Context, projectIdenti
 			UpdatedAt:    loaderVersion.UpdatedAt,
 		},
 		Downloads: 0,
-		FileUrl:   params.FileUrl,
-		FileSize:  metadata.ContentLength,
-		FileHash:  metadata.ETag,
 		CreatedAt: time.Now().UTC(),
 		UpdatedAt: time.Now().UTC(),
 	}
+}
 
-	destinationKey := fmt.Sprintf("users/%s/projects/%s/releases/%s/%s_%s.tmod", userId, project.Id, release.Id, project.Slug, release.VersionNumber)
-
-	newPath, err := s.objectStoreService.MoveFile(ctx, sourceKey, destinationKey)
-
-	if err != nil {
-		s.logger.Error("Failed to move file.", "Source", sourceKey, "Destination:", destinationKey)
-		return nil, err
-	}
-
-	release.FileUrl = s.cdnUrl + newPath
+func releaseFileKey(userId string, project *models.Project, release *models.ProjectRelease) string {
+	return fmt.Sprintf("users/%s/projects/%s/releases/%s/%s_%s.tmod", userId, project.Id, release.Id, project.Slug, release.VersionNumber)
+}
 
-	err = s.projectReleaseRepo.InsertRelease(ctx, tx, &release)
+// Inserts the release and its dependencies, and bumps the project's updatedAt.
+func (s *projectReleaseService) insertRelease(ctx context.Context, tx *sql.Tx, project *models.Project, userId string, release *models.ProjectRelease, dependencies []CreateProjectReleaseDependencyParams) error {
+	err := s.projectReleaseRepo.InsertRelease(ctx, tx, release)
 
 	if err != nil {
 		switch {
 		case errors.Is(err, database.ErrUniqueViolation):
-			return nil, custom_errors.ErrProjectReleaseNumberAlreadyExists
+			return custom_errors.ErrProjectReleaseNumberAlreadyExists.With("versionNumber", release.VersionNumber)
 		}
-		return nil, err
+		return err
 	}
 
 	seen := make(map[string]bool)
 
-	for _, d := range params.Dependencies {
+	for _, d := range dependencies {
 		key := d.ProjectId
 
 		if !seen[key] {
 			seen[key] = true
 		} else {
-			return nil, custom_errors.ErrDuplicateProjectReleaseDependency
+			return custom_errors.ErrDuplicateProjectReleaseDependency.With("projectId", key)
 		}
 	}
 
-	deps := make([]models.ProjectReleaseDependency, 0, len(params.Dependencies))
+	deps := make([]models.ProjectReleaseDependency, 0, len(dependencies))
 
-	for _, dep := range params.Dependencies {
+	for _, dep := range dependencies {
 		p, err := s.projectRepo.FindProjectByIdentifier(ctx, tx, dep.ProjectId, userId)
 
 		if err != nil {
-			return nil, err
+			return err
 		}
 
 		if p == nil {
-			return nil, custom_errors.ErrProjectReleaseDependencyNotFound
+			return custom_errors.ErrProjectReleaseDependencyNotFound.With("projectId", dep.ProjectId)
 		}
 
 		if p.Id == project.Id {
-			return nil, custom_errors.ErrCircularProjectReleaseDependency
+			return custom_errors.ErrCircularProjectReleaseDependency.With("projectId", dep.ProjectId)
 		}
 
 		if dep.MinVersionNumber != nil {
 			min, err := s.projectReleaseRepo.FindByProjectIdAndVersionNumber(ctx, tx, dep.ProjectId, *dep.MinVersionNumber)
 
 			if err != nil {
-				return nil, err
+				return err
 			}
 
 			if min == nil {
-				return nil, custom_errors.ErrProjectReleaseDependencyMinVersionDoesNotExist
+				return custom_errors.ErrProjectReleaseDependencyMinVersionDoesNotExist.With("projectId", dep.ProjectId).With("minVersionNumber", *dep.MinVersionNumber)
 			}
 		}
 
@@ -260,25 +495,27 @@ func (s *projectReleaseService) CreateRelease(ctx context.Context, projectIdenti
 		err := s.projectReleaseRepo.InsertDependencies(ctx, tx, deps)
 
 		if err != nil {
-			return nil, err
+			return err
 		}
 	}
 
 	err = s.projectRepo.UpdateProject(ctx, tx, *project)
 
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	err = tx.Commit()
+	release.Dependencies = deps
 
-	if err != nil {
-		return nil, err
-	}
+	return nil
+}
 
-	release.Dependencies = deps
+func (s *projectReleaseService) invalidateReleases(ctx context.Context, projectId string) {
+	err := s.releaseCache.DeleteRelease(ctx, projectId, "")
 
-	return &release, nil
+	if err != nil {
+		s.logger.WarnContext(ctx, "Failed to delete project releases from cache", "projectId", projectId, "error", err)
+	}
 }
 
 func (s *projectReleaseService) GenerateProjectReleasePresignedPutUrl(ctx context.Context, projectIdentifier string, userId string, fileSize string) (string, error) {
@@ -288,8 +525,8 @@ func (s *projectReleaseService) GenerateProjectReleasePresignedPutUrl(ctx contex
 		return "", custom_errors.ErrProjectReleaseInvalidFileSize
 	}
 
-	if fileSizeBytes < 0 || fileSizeBytes > 524_288_000 {
-		return "", custom_errors.ErrProjectReleaseInvalidFileSize
+	if fileSizeBytes < 0 || fileSizeBytes > MaxReleaseFileSize {
+		return "", custom_errors.ErrProjectReleaseInvalidFileSize.With("maxFileSize", MaxReleaseFileSize)
 	}
 
 	project, err := s.projectRepo.FindProjectByIdentifier(ctx, s.db, projectIdentifier, userId)
@@ -318,29 +555,80 @@ func (s *projectReleaseService) GenerateProjectReleasePresignedPutUrl(ctx contex
 	url, err := s.objectStoreService.GeneratePresignedPutUrl(ctx, key, "application/octet-stream", fileSizeBytes)
 
 	if err != nil {
-		s.logger.Error("Failed to generate project release presigned url.", "Error:", err)
+		s.logger.ErrorContext(ctx, "Failed to generate project release presigned url.", "error", err)
 		return "", err
 	}
 
 	return url, err
 }
 
-func (s *projectReleaseService) GetReleasesByProjectId(ctx context.Context, projectIdentifier string, userId string) ([]models.ProjectRelease, error) {
+func (s *projectReleaseService) GetReleasesByProjectId(ctx context.Context, projectIdentifier string, userId string, page pagination.Params) (pagination.Page[models.ProjectRelease], error) {
 	project, err := s.projectRepo.FindProjectByIdentifier(ctx, s.db, projectIdentifier, userId)
 
 	if err != nil {
-		return nil, err
+		return pagination.Page[models.ProjectRelease]{}, err
 	}
 
 	if project == nil {
-		return nil, custom_errors.ErrProjectNotFound
+		return pagination.Page[models.ProjectRelease]{}, custom_errors.ErrProjectNotFound
 	}
 
-	releases, err := s.projectReleaseRepo.FindReleasesByProjectIdWithLoaderVersion(ctx, s.db, project.Id)
+	// Only the first page at the default limit is cached, later pages go to the database
+	if project.Status != models.ProjectStatusApproved || !isCachedPage(page) {
+		return s.projectReleaseRepo.FindReleasesByProjectIdWithLoaderVersion(ctx, s.db, project.Id, page)
+	}
+
+	releases, err := s.releaseCache.GetReleases(ctx, project.Id)
+
+	if err == nil {
+		return releases, nil
+	}
+
+	if errors.Is(err, cache.ErrCacheStale) {
+		s.tasks.Go(ctx, func(ctx context.Context) {
+			s.refreshReleases(ctx, project)
+		})
+		return releases, nil
+	}
+
+	if !errors.Is(err, cache.ErrCacheMiss) {
+		s.logger.WarnContext(ctx, "Failed to read releases from cache", "projectId", project.Id, "error", err)
+	}
+
+	return s.loadReleases(ctx, project)
+}
+
+// Loads the cached first page of an approved project's releases.
+func (s *projectReleaseService) loadReleases(ctx context.Context, project *models.Project) (pagination.Page[models.ProjectRelease], error) {
+	v, err, _ := s.loads.Do("releases:"+project.Id, func() (any, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), cacheRefreshTimeout)
+		defer cancel()
+
+		releases, err := s.projectReleaseRepo.FindReleasesByProjectIdWithLoaderVersion(ctx, s.db, project.Id, pagination.Params{Limit: pagination.DefaultLimit})
+
+		if err != nil {
+			return nil, err
+		}
+
+		_ = s.releaseCache.SetReleases(ctx, project.Id, releases, releaseCacheTTL)
+
+		return releases, nil
+	})
 
 	if err != nil {
-		return nil, err
+		return pagination.Page[models.ProjectRelease]{}, err
 	}
 
-	return releases, nil
+	return v.(pagination.Page[models.ProjectRelease]), nil
+}
+
+func (s *projectReleaseService) refreshReleases(ctx context.Context, project *models.Project) {
+	ctx, cancel := context.WithTimeout(ctx, cacheRefreshTimeout)
+	defer cancel()
+
+	_, err := s.loadReleases(ctx, project)
+
+	if err != nil {
+		s.logger.WarnContext(ctx, "Failed to refresh cached releases", "projectId", project.Id, "error", err)
+	}
 }
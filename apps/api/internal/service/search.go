@@ -3,13 +3,16 @@ package service
 import (
 	"context"
 	"log/slog"
+	"time"
 
+	"github.com/terraforge-gg/terraforge/internal/metrics"
 	"github.com/terraforge-gg/terraforge/internal/models"
 	"github.com/terraforge-gg/terraforge/internal/repository"
 )
 
 type SearchService interface {
 	SearchProjects(ctx context.Context, query string, projectType string, limit int64, offset int64) ([]models.Project, int64, error)
+	SearchUsers(ctx context.Context, query string, limit int64, offset int64) ([]models.UserWithStats, int64, error)
 	Health(ctx context.Context) error
 }
 
@@ -23,7 +26,9 @@ func NewSearchService(logger *slog.Logger, searchRepo repository.SearchRepositor
 }
 
 func (s *searchService) SearchProjects(ctx context.Context, query string, projectType string, limit int64, offset int64) ([]models.Project, int64, error) {
+	start := time.Now()
 	result, err := s.searchRepo.FindProjects(ctx, query, projectType, limit, offset)
+	metrics.ObserveSince(metrics.SearchDuration, "projects", start, err)
 
 	if err != nil {
 		return nil, 0, err
@@ -51,6 +56,37 @@ func (s *searchService) SearchProjects(ctx context.Context, query string, projec
 	return projects, result.TotalHits, nil
 }
 
+func (s *searchService) SearchUsers(ctx context.Context, query string, limit int64, offset int64) ([]models.UserWithStats, int64, error) {
+	start := time.Now()
+	result, err := s.searchRepo.FindUsers(ctx, query, limit, offset)
+	metrics.ObserveSince(metrics.SearchDuration, "users", start, err)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	users := make([]models.UserWithStats, len(result.Users))
+
+	for i, u := range result.Users {
+		users[i] = models.UserWithStats{
+			User: models.User{
+				Id:              u.Id,
+				Name:            u.Name,
+				Username:        u.Username,
+				DisplayUsername: u.DisplayUsername,
+				Image:           u.Image,
+				CreatedAt:       u.CreatedAt,
+			},
+			Stats: models.UserStats{
+				ProjectCount:   u.ProjectCount,
+				TotalDownloads: u.TotalDownloads,
+			},
+		}
+	}
+
+	return users, result.TotalHits, nil
+}
+
 func (s *searchService) Health(ctx context.Context) error {
 	err := s.searchRepo.Health(ctx)
 
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/terraforge-gg/terraforge/internal/background"
+	"github.com/terraforge-gg/terraforge/internal/models"
+	"github.com/terraforge-gg/terraforge/internal/repository"
+	"github.com/terraforge-gg/terraforge/internal/utils"
+)
+
+type SearchAnalyticsService interface {
+	RecordSearch(ctx context.Context, params RecordSearchParams) (string, bool)
+	RecordClick(ctx context.Context, params RecordClickParams)
+	GetReport(ctx context.Context, since time.Time, limit int64) (*models.SearchAnalyticsReport, error)
+}
+
+type searchAnalyticsService struct {
+	logger              *slog.Logger
+	db                  *sql.DB
+	searchAnalyticsRepo repository.SearchAnalyticsRepository
+	sampleRate          float64
+	tasks               *background.Group
+}
+
+func NewSearchAnalyticsService(logger *slog.Logger, db *sql.DB, searchAnalyticsRepo repository.SearchAnalyticsRepository, sampleRate float64, tasks *background.Group) SearchAnalyticsService {
+	return &searchAnalyticsService{logger: logger, db: db, searchAnalyticsRepo: searchAnalyticsRepo, sampleRate: sampleRate, tasks: tasks}
+}
+
+type RecordSearchParams struct {
+	Index       models.SearchIndex
+	Query       string
+	Filters     map[string]string
+	ResultCount int64
+	Limit       int64
+	Offset      int64
+	UserId      string
+}
+
+// Records a sampled search in the background. Returns the id of the recorded search and
+// true when the search was sampled, so callers can hand the id back for click attribution.
+func (s *searchAnalyticsService) RecordSearch(ctx context.Context, params RecordSearchParams) (string, bool) {
+	if s.sampleRate <= 0 || rand.Float64() >= s.sampleRate {
+		return "", false
+	}
+
+	var userId *string
+	if params.UserId != "" {
+		userId = &params.UserId
+	}
+
+	searchQuery := &models.SearchQuery{
+		Id:              utils.NewUUID(),
+		Index:           params.Index,
+		Query:           params.Query,
+		NormalizedQuery: normalizeSearchQuery(params.Query),
+		Filters:         params.Filters,
+		ResultCount:     params.ResultCount,
+		Limit:           params.Limit,
+		Offset:          params.Offset,
+		UserId:          userId,
+		CreatedAt:       time.Now().UTC(),
+	}
+
+	s.tasks.Go(ctx, func(ctx context.Context) {
+		err := s.searchAnalyticsRepo.InsertSearchQuery(ctx, s.db, searchQuery)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "Failed to record search query.", "index", params.Index, "error", err)
+		}
+	})
+
+	return searchQuery.Id, true
+}
+
+type RecordClickParams struct {
+	SearchQueryId string
+	ProjectId     string
+	ReleaseId     *string
+	Type          models.SearchClickType
+}
+
+// Records a click on a search result in the background. The search id is sent by the client,
+// so the click is dropped unless it belongs to a recorded project search.
+func (s *searchAnalyticsService) RecordClick(ctx context.Context, params RecordClickParams) {
+	if params.SearchQueryId == "" {
+		return
+	}
+
+	searchClick := &models.SearchClick{
+		Id:            utils.NewUUID(),
+		SearchQueryId: params.SearchQueryId,
+		ProjectId:     params.ProjectId,
+		ReleaseId:     params.ReleaseId,
+		Type:          params.Type,
+		CreatedAt:     time.Now().UTC(),
+	}
+
+	s.tasks.Go(ctx, func(ctx context.Context) {
+		err := s.searchAnalyticsRepo.InsertSearchClick(ctx, s.db, searchClick)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "Failed to record search click.", "searchQueryId", params.SearchQueryId, "error", err)
+		}
+	})
+}
+
+func (s *searchAnalyticsService) GetReport(ctx context.Context, since time.Time, limit int64) (*models.SearchAnalyticsReport, error) {
+	topQueries, err := s.searchAnalyticsRepo.FindTopQueries(ctx, s.db, since, limit)
+
+	if err != nil {
+		return nil, err
+	}
+
+	zeroResultQueries, err := s.searchAnalyticsRepo.FindZeroResultQueries(ctx, s.db, since, limit)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.SearchAnalyticsReport{
+		Since:             since,
+		TopQueries:        topQueries,
+		ZeroResultQueries: zeroResultQueries,
+	}, nil
+}
+
+func normalizeSearchQuery(query string) string {
+	return strings.Join(strings.Fields(strings.ToLower(query)), " ")
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"time"
+
+	"github.com/terraforge-gg/terraforge/internal/database"
+	custom_errors "github.com/terraforge-gg/terraforge/internal/errors"
+	"github.com/terraforge-gg/terraforge/internal/models"
+	"github.com/terraforge-gg/terraforge/internal/pagination"
+	"github.com/terraforge-gg/terraforge/internal/repository"
+)
+
+type UserService interface {
+	SyncUserSearchDocument(ctx context.Context, userId string) error
+	SyncUserSearchDocuments(ctx context.Context, updatedSince time.Time) (int, error)
+	RunUserSearchSync(ctx context.Context, interval time.Duration)
+}
+
+type userService struct {
+	logger     *slog.Logger
+	db         *sql.DB
+	userRepo   repository.UserRepository
+	searchRepo repository.SearchRepository
+}
+
+func NewUserService(logger *slog.Logger, db *sql.DB, userRepo repository.UserRepository, searchRepo repository.SearchRepository) UserService {
+	return &userService{logger: logger, db: db, userRepo: userRepo, searchRepo: searchRepo}
+}
+
+func (s *userService) SyncUserSearchDocument(ctx context.Context, userId string) error {
+	return syncUserSearchDocument(ctx, s.db, s.userRepo, s.searchRepo, userId)
+}
+
+const userSearchSyncBatchSize int64 = 500
+
+// Indexes every user updated at or after updatedSince in batches.
+// Returns the number of documents sent to the search index.
+func (s *userService) SyncUserSearchDocuments(ctx context.Context, updatedSince time.Time) (int, error) {
+	page := pagination.Params{Limit: userSearchSyncBatchSize}
+	synced := 0
+
+	for {
+		users, err := s.userRepo.FindUsersWithStats(ctx, s.db, updatedSince, page)
+
+		if err != nil {
+			return synced, err
+		}
+
+		if err := s.searchRepo.IndexUsers(ctx, users.Items); err != nil {
+			return synced, err
+		}
+
+		synced += len(users.Items)
+
+		if users.NextCursor == "" {
+			return synced, nil
+		}
+
+		page.Cursor = users.NextCursor
+	}
+}
+
+// Download counts change without touching any timestamp, so users are fully re-indexed this
+// often to keep their total downloads from drifting.
+const userSearchFullSyncInterval = 24 * time.Hour
+
+// Profiles are edited through the auth app, so the API cannot hook those writes directly.
+// Instead users are fully indexed once, then anything updated since the previous run is
+// re-indexed on every tick, with a full run every userSearchFullSyncInterval, until ctx is
+// cancelled.
+func (s *userService) RunUserSearchSync(ctx context.Context, interval time.Duration) {
+	var since time.Time
+	var lastFullSync time.Time
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		startedAt := time.Now().UTC()
+
+		if startedAt.Sub(lastFullSync) >= userSearchFullSyncInterval {
+			since = time.Time{}
+		}
+
+		count, err := s.SyncUserSearchDocuments(ctx, since)
+
+		if err != nil {
+			s.logger.ErrorContext(ctx, "Failed to sync user search documents.", "since", since, "error", err)
+		} else {
+			s.logger.InfoContext(ctx, "Synced user search documents.", "since", since, "count", count)
+
+			if since.IsZero() {
+				lastFullSync = startedAt
+			}
+
+			since = startedAt
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func syncUserSearchDocument(ctx context.Context, q database.Querier, userRepo repository.UserRepository, searchRepo repository.SearchRepository, userId string) error {
+	user, err := userRepo.FindUserByIdentifier(ctx, q, userId)
+
+	if err != nil {
+		return err
+	}
+
+	if user == nil {
+		return custom_errors.ErrUserNotFound
+	}
+
+	stats, err := userRepo.FindUserStatsById(ctx, q, user.Id)
+
+	if err != nil {
+		return err
+	}
+
+	return searchRepo.IndexUser(ctx, &models.UserWithStats{
+		User:  *user,
+		Stats: *stats,
+	})
+}
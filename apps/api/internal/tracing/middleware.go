@@ -0,0 +1,62 @@
+package tracing
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.39.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Middleware starts a span for every request, continuing the caller's trace when one is
+// propagated. It goes after requestctx.Middleware so the request id is known.
+func Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c *echo.Context) error {
+			req := c.Request()
+			ctx := otel.GetTextMapPropagator().Extract(req.Context(), propagation.HeaderCarrier(req.Header))
+
+			// Named by the route pattern so path parameters don't make every span unique
+			route := c.Path()
+			name := req.Method
+			if route != "" {
+				name += " " + route
+			}
+
+			ctx, span := Tracer().Start(ctx, name,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					semconv.HTTPRequestMethodKey.String(req.Method),
+					semconv.HTTPRoute(route),
+					semconv.URLPath(req.URL.Path),
+					semconv.ClientAddress(c.RealIP()),
+				),
+			)
+			defer span.End()
+
+			c.SetRequest(req.WithContext(ctx))
+
+			err := next(c)
+
+			// The user is only known once the caller has been authenticated further down the chain
+			span.SetAttributes(requestAttributes(ctx)...)
+
+			_, status := echo.ResolveResponseStatus(c.Response(), err)
+			span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+
+			// Client errors are the caller's problem, not a failed operation
+			if status >= http.StatusInternalServerError {
+				if err != nil {
+					span.RecordError(err)
+				}
+
+				span.SetStatus(codes.Error, http.StatusText(status))
+			}
+
+			return err
+		}
+	}
+}
@@ -0,0 +1,140 @@
+// Package tracing sets up OpenTelemetry tracing for the API. Requests, database queries, Redis
+// commands and calls to Meilisearch and S3 become spans, each tagged with the request id and
+// user of the request that caused them.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/terraforge-gg/terraforge/internal/config"
+	"github.com/terraforge-gg/terraforge/internal/requestctx"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.39.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	tracerName  = "github.com/terraforge-gg/terraforge"
+	serviceName = "terraforge-api"
+)
+
+const (
+	ExporterOtlp   = "otlp"
+	ExporterStdout = "stdout"
+)
+
+// Not in the semantic conventions, named after the X-Request-Id header it comes from.
+const RequestIdKey = attribute.Key("http.request.id")
+
+func Tracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}
+
+// Setup installs the global tracer provider for the configured exporter and returns a function
+// that flushes the spans still buffered. Without an exporter tracing stays a no-op.
+func Setup(ctx context.Context, cfg *config.Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	var err error
+
+	switch cfg.TracingExporter {
+	case "":
+		return func(context.Context) error { return nil }, nil
+	case ExporterOtlp:
+		exporter, err = otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(cfg.TracingEndpoint))
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithPrettyPrint())
+	default:
+		err = fmt.Errorf("unknown exporter %q", cfg.TracingExporter)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(serviceName),
+		semconv.DeploymentEnvironmentName(cfg.Env),
+	))
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace resource: %w", err)
+	}
+
+	provider := newTracerProvider(sdktrace.NewBatchSpanProcessor(exporter), cfg.TracingSampleRate, res)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+// Requests that arrive with a sampled parent are always traced so traces aren't cut short.
+func newTracerProvider(processor sdktrace.SpanProcessor, sampleRate float64, res *resource.Resource) *sdktrace.TracerProvider {
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRate))),
+		sdktrace.WithResource(res),
+		sdktrace.WithSpanProcessor(requestProcessor{}),
+		sdktrace.WithSpanProcessor(processor),
+	)
+}
+
+// HTTPClient returns a client whose requests to a backend such as Meilisearch or S3 are
+// traced. Spans are named by operation when it is given and returns a name, otherwise by the
+// HTTP method. Trace headers are not sent, third party services have no use for them.
+func HTTPClient(backend string, base http.RoundTripper, operation func(r *http.Request) string) *http.Client {
+	return &http.Client{
+		Transport: otelhttp.NewTransport(base,
+			otelhttp.WithPropagators(propagation.NewCompositeTextMapPropagator()),
+			otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+				if operation != nil {
+					if name := operation(r); name != "" {
+						return backend + " " + name
+					}
+				}
+
+				return backend + " " + r.Method
+			}),
+			otelhttp.WithSpanOptions(trace.WithAttributes(semconv.ServicePeerName(backend))),
+		),
+	}
+}
+
+// The request id and user, as far as they are known, of the request ctx belongs to.
+func requestAttributes(ctx context.Context) []attribute.KeyValue {
+	info, ok := requestctx.FromContext(ctx)
+
+	if !ok {
+		return nil
+	}
+
+	attrs := []attribute.KeyValue{RequestIdKey.String(info.Id)}
+
+	if userId := info.UserId(); userId != "" {
+		attrs = append(attrs, semconv.UserID(userId))
+	}
+
+	return attrs
+}
+
+// Copies the request attributes onto spans started by instrumentation that doesn't know about them.
+type requestProcessor struct{}
+
+func (requestProcessor) OnStart(ctx context.Context, s sdktrace.ReadWriteSpan) {
+	s.SetAttributes(requestAttributes(ctx)...)
+}
+
+func (requestProcessor) OnEnd(sdktrace.ReadOnlySpan) {}
+
+func (requestProcessor) Shutdown(context.Context) error { return nil }
+
+func (requestProcessor) ForceFlush(context.Context) error { return nil }
@@ -0,0 +1,144 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/terraforge-gg/terraforge/internal/requestctx"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.39.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Records every span through the global provider for the rest of the test.
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(newTracerProvider(recorder, 1, resource.Empty()))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	return recorder
+}
+
+func attributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value)
+
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+
+	return attrs
+}
+
+func TestMiddleware(t *testing.T) {
+	recorder := recordSpans(t)
+	e := echo.New()
+	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c *echo.Context) error {
+			c.Response().Header().Set(echo.HeaderXRequestID, "request-1")
+			return next(c)
+		}
+	})
+	e.Use(requestctx.Middleware())
+	e.Use(Middleware())
+	e.GET("/v1/projects/:identifier", func(c *echo.Context) error {
+		_, span := Tracer().Start(c.Request().Context(), "query")
+		span.End()
+		return c.NoContent(http.StatusOK)
+	}, func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c *echo.Context) error {
+			c.Set("userId", "user-1")
+			return next(c)
+		}
+	}, requestctx.UserMiddleware())
+
+	parent := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1},
+		SpanID:     trace.SpanID{1},
+		TraceFlags: trace.FlagsSampled,
+	})
+	req := httptest.NewRequest(http.MethodGet, "/v1/projects/my-mod", nil)
+	otel.GetTextMapPropagator().Inject(trace.ContextWithSpanContext(context.Background(), parent), propagation.HeaderCarrier(req.Header))
+
+	e.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	query, server := spans[0], spans[1]
+
+	assert.Equal(t, "GET /v1/projects/:identifier", server.Name())
+	assert.Equal(t, trace.SpanKindServer, server.SpanKind())
+	// Continues the caller's trace
+	assert.Equal(t, parent.TraceID(), server.SpanContext().TraceID())
+	assert.Equal(t, server.SpanContext().SpanID(), query.Parent().SpanID())
+
+	serverAttrs := attributes(server)
+	assert.Equal(t, "request-1", serverAttrs[RequestIdKey].AsString())
+	assert.Equal(t, "user-1", serverAttrs[semconv.UserIDKey].AsString())
+	assert.Equal(t, int64(http.StatusOK), serverAttrs[semconv.HTTPResponseStatusCodeKey].AsInt64())
+
+	// Spans started under the request carry it without being told
+	queryAttrs := attributes(query)
+	assert.Equal(t, "request-1", queryAttrs[RequestIdKey].AsString())
+	assert.Equal(t, "user-1", queryAttrs[semconv.UserIDKey].AsString())
+}
+
+func TestMiddleware_ServerError(t *testing.T) {
+	recorder := recordSpans(t)
+	e := echo.New()
+	e.Use(Middleware())
+	e.GET("/fail", func(c *echo.Context) error {
+		return echo.ErrServiceUnavailable
+	})
+	e.GET("/missing", func(c *echo.Context) error {
+		return echo.ErrNotFound
+	})
+
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/fail", nil))
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/missing", nil))
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, codes.Unset, spans[1].Status().Code)
+}
+
+func TestHTTPClient(t *testing.T) {
+	recorder := recordSpans(t)
+	var traceparent string
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+	}))
+	t.Cleanup(backend.Close)
+
+	ctx, span := Tracer().Start(requestctx.WithInfo(context.Background(), &requestctx.Info{Id: "request-1"}), "request")
+	client := HTTPClient("s3", http.DefaultTransport, func(r *http.Request) string { return "PutObject" })
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, backend.URL, nil)
+	require.NoError(t, err)
+
+	res, err := client.Do(req)
+	require.NoError(t, err)
+	res.Body.Close()
+	span.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "s3 PutObject", spans[0].Name())
+	assert.Equal(t, trace.SpanKindClient, spans[0].SpanKind())
+	assert.Equal(t, "request-1", attributes(spans[0])[RequestIdKey].AsString())
+	assert.Empty(t, traceparent)
+}
@@ -1,6 +1,9 @@
 package utils
 
-import "github.com/labstack/echo/v5"
+import (
+	"github.com/labstack/echo/v5"
+	"github.com/terraforge-gg/terraforge/internal/models"
+)
 
 // Extracts the userId from the Echo request context.
 // Returns the userId string and ok bool indicating success
@@ -17,3 +20,42 @@ func GetSessionUserId(c *echo.Context) (string, bool) {
 
 	return s, true
 }
+
+// Extracts the personal access token the request was authenticated with.
+// Returns false for anonymous requests and requests authenticated with a session JWT.
+func GetSessionAccessToken(c *echo.Context) (*models.PersonalAccessToken, bool) {
+	token, ok := c.Get("accessToken").(*models.PersonalAccessToken)
+	return token, ok && token != nil
+}
+
+// Extracts the platform role of the session user. Personal access tokens and
+// tokens without a role claim are treated as regular users.
+func GetSessionUserRole(c *echo.Context) models.UserRole {
+	role, ok := c.Get("userRole").(models.UserRole)
+
+	if !ok {
+		return models.UserRoleUser
+	}
+
+	return role
+}
+
+// Extracts the id of the auth app session a JWT was issued for.
+// Returns false for anonymous requests and personal access tokens.
+func GetSessionId(c *echo.Context) (string, bool) {
+	id, ok := c.Get("sessionId").(string)
+	return id, ok && id != ""
+}
+
+// Reports whether the session user has verified their email, as claimed by their JWT or
+// loaded with their personal access token. Always false for anonymous requests.
+func GetSessionEmailVerified(c *echo.Context) bool {
+	verified, _ := c.Get("emailVerified").(bool)
+	return verified
+}
+
+// Reports whether the session user is a test account flagged by an admin.
+func GetSessionTestAccount(c *echo.Context) bool {
+	testAccount, _ := c.Get("testAccount").(bool)
+	return testAccount
+}
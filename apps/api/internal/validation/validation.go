@@ -2,6 +2,7 @@ package validation
 
 import (
 	"fmt"
+	"net/http"
 	"net/url"
 	"regexp"
 	"strings"
@@ -23,13 +24,20 @@ func (e *ValidationError) Error() string {
 	return "validation failed"
 }
 
+func (e *ValidationError) StatusCode() int {
+	return http.StatusBadRequest
+}
+
 func NewValidator(cfg *config.Config) *validator.Validate {
 	validate := validator.New()
 	validate.RegisterValidation("url_slug", ValidateUrlSlug)
 	validate.RegisterValidation("project_type", ValidateProjectType)
+	validate.RegisterValidation("project_status", ValidateProjectStatus)
+	validate.RegisterValidation("project_member_role", ValidateProjectMemberRole)
 	validate.RegisterValidation("project_version_dependency_type", ValidateProjectDependencyType)
 	validate.RegisterValidation("file_url", createFileUrlValidator(cfg.CdnUrl))
 	validate.RegisterValidation("semver", ValidateSemVer)
+	validate.RegisterValidation("token_scope", ValidateTokenScope)
 
 	return validate
 }
@@ -54,12 +62,18 @@ func (v *Validator) Validate(i any) error {
 				errors[field] = fmt.Sprintf("'%s' is not a valid slug.", err.Value())
 			case "project_type":
 				errors[field] = fmt.Sprintf("'%s' is not a valid project type.", err.Value())
+			case "project_status":
+				errors[field] = fmt.Sprintf("'%s' is not a valid project status.", err.Value())
+			case "project_member_role":
+				errors[field] = fmt.Sprintf("'%s' is not a valid project member role.", err.Value())
 			case "file_url":
 				errors[field] = "invalid file url"
 			case "project_version_dependency_type":
 				errors[field] = "invalid project version dependency type"
 			case "semver":
 				errors[field] = "invalid semver"
+			case "token_scope":
+				errors[field] = fmt.Sprintf("'%s' is not a valid token scope.", err.Value())
 			default:
 				errors[field] = "Invalid"
 			}
@@ -84,6 +98,29 @@ func ValidateProjectType(fl validator.FieldLevel) bool {
 	return false
 }
 
+func ValidateProjectStatus(fl validator.FieldLevel) bool {
+	switch models.ProjectStatus(fl.Field().String()) {
+	case models.ProjectStatusDraft,
+		models.ProjectStatusRejected,
+		models.ProjectStatusApproved,
+		models.ProjectStatusBanned:
+		return true
+	}
+	return false
+}
+
+// The owner role is given to the project's creator and cannot be granted.
+func ValidateProjectMemberRole(fl validator.FieldLevel) bool {
+	switch models.ProjectMemberRole(fl.Field().String()) {
+	case models.ProjectMemberRoleAdmin,
+		models.ProjectMemberRoleDeveloper,
+		models.ProjectMemberRoleMaintainer,
+		models.ProjectMemberRoleMember:
+		return true
+	}
+	return false
+}
+
 var SemVerValidator = regexp.MustCompile(`^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(-[a-zA-Z0-9.]+)?$`)
 
 func ValidateSemVer(fl validator.FieldLevel) bool {
@@ -99,6 +136,16 @@ func ValidateProjectDependencyType(fl validator.FieldLevel) bool {
 	return false
 }
 
+func ValidateTokenScope(fl validator.FieldLevel) bool {
+	switch models.TokenScope(fl.Field().String()) {
+	case models.TokenScopeProjectRead,
+		models.TokenScopeProjectWrite,
+		models.TokenScopeReleaseWrite:
+		return true
+	}
+	return false
+}
+
 func createFileUrlValidator(cdnUrl string) validator.Func {
 	return func(fl validator.FieldLevel) bool {
 		parsedCdnUrl, err := url.Parse(cdnUrl)
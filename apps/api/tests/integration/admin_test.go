@@ -0,0 +1,179 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/terraforge-gg/terraforge/internal/database"
+	"github.com/terraforge-gg/terraforge/internal/dto"
+	"github.com/terraforge-gg/terraforge/internal/models"
+)
+
+func createTestProject(t *testing.T, env *testEnv) dto.ProjectResponse {
+	t.Helper()
+
+	summary := ExampleModSummary
+	body := createCreateProjectRequestBody(t, ExampleModName, ExampleModSlug, &summary, string(models.ProjectTypeMod))
+	req := httptest.NewRequest(http.MethodPost, "/v1/projects", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set("Authorization", "Bearer "+env.token1)
+	rec := httptest.NewRecorder()
+	env.server.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	var project dto.ProjectResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &project))
+
+	return project
+}
+
+func TestIntegration_Admin_GetUsers(t *testing.T) {
+	// Arrange
+	env := newTestEnv(t)
+
+	// Act
+	req := httptest.NewRequest(http.MethodGet, "/v1/admin/users", nil)
+	req.Header.Set("Authorization", "Bearer "+env.adminToken)
+	rec := httptest.NewRecorder()
+	env.server.ServeHTTP(rec, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response dto.AdminUsersResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.EqualValues(t, 2, response.TotalHits)
+}
+
+func TestIntegration_Admin_Forbidden(t *testing.T) {
+	// Arrange
+	env := newTestEnv(t)
+
+	// Act
+	req := httptest.NewRequest(http.MethodGet, "/v1/admin/users", nil)
+	req.Header.Set("Authorization", "Bearer "+env.token1)
+	rec := httptest.NewRecorder()
+	env.server.ServeHTTP(rec, req)
+
+	// Assert
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+func TestIntegration_Admin_BanUser(t *testing.T) {
+	// Arrange
+	env := newTestEnv(t)
+	body := `{"reason":"spam"}`
+
+	// Act
+	req := httptest.NewRequest(http.MethodPost, "/v1/admin/users/"+database.TestUser2Id+"/ban", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set("Authorization", "Bearer "+env.adminToken)
+	rec := httptest.NewRecorder()
+	env.server.ServeHTTP(rec, req)
+
+	auditReq := httptest.NewRequest(http.MethodGet, "/v1/admin/audit-logs", nil)
+	auditReq.Header.Set("Authorization", "Bearer "+env.adminToken)
+	auditRec := httptest.NewRecorder()
+	env.server.ServeHTTP(auditRec, auditReq)
+
+	// Assert
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, http.StatusOK, auditRec.Code)
+
+	var user dto.AdminUserResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &user))
+	assert.True(t, user.Banned)
+
+	var auditLogs dto.ListResponse[dto.AuditLogResponse]
+	require.NoError(t, json.Unmarshal(auditRec.Body.Bytes(), &auditLogs))
+	require.Len(t, auditLogs.Data, 1)
+	assert.Equal(t, string(models.AuditActionUserBan), auditLogs.Data[0].Action)
+	assert.Equal(t, database.TestUser2Id, auditLogs.Data[0].TargetId)
+}
+
+func TestIntegration_Admin_BanSelf(t *testing.T) {
+	// Arrange
+	env := newTestEnv(t)
+
+	// Act
+	req := httptest.NewRequest(http.MethodPost, "/v1/admin/users/"+database.TestUser1Id+"/ban", nil)
+	req.Header.Set("Authorization", "Bearer "+env.adminToken)
+	rec := httptest.NewRecorder()
+	env.server.ServeHTTP(rec, req)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestIntegration_Admin_UpdateProjectStatus(t *testing.T) {
+	// Arrange
+	env := newTestEnv(t)
+	project := createTestProject(t, env)
+	body := `{"status":"rejected","reason":"Broken upload"}`
+
+	// Act
+	req := httptest.NewRequest(http.MethodPut, "/v1/admin/projects/"+project.Id+"/status", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set("Authorization", "Bearer "+env.adminToken)
+	rec := httptest.NewRecorder()
+	env.server.ServeHTTP(rec, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response dto.ProjectResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, string(models.ProjectStatusRejected), response.Status)
+}
+
+func TestIntegration_Admin_RestoreProject(t *testing.T) {
+	// Arrange
+	env := newTestEnv(t)
+	project := createTestProject(t, env)
+	deleteReq := httptest.NewRequest(http.MethodDelete, "/v1/projects/"+ExampleModSlug, nil)
+	deleteReq.Header.Set("Authorization", "Bearer "+env.token1)
+	deleteRec := httptest.NewRecorder()
+	env.server.ServeHTTP(deleteRec, deleteReq)
+	require.Equal(t, http.StatusOK, deleteRec.Code)
+
+	// Act
+	req := httptest.NewRequest(http.MethodPost, "/v1/admin/projects/"+project.Id+"/restore", nil)
+	req.Header.Set("Authorization", "Bearer "+env.adminToken)
+	rec := httptest.NewRecorder()
+	env.server.ServeHTTP(rec, req)
+
+	getReq := httptest.NewRequest(http.MethodGet, "/v1/projects/"+ExampleModSlug, nil)
+	getReq.Header.Set("Authorization", "Bearer "+env.token1)
+	getRec := httptest.NewRecorder()
+	env.server.ServeHTTP(getRec, getReq)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, http.StatusOK, getRec.Code)
+}
+
+func TestIntegration_Admin_UpdateTestAccount(t *testing.T) {
+	// Arrange
+	env := newTestEnv(t)
+	body := `{"testAccount":true}`
+
+	// Act
+	req := httptest.NewRequest(http.MethodPut, "/v1/admin/users/"+database.TestUser2Id+"/test-account", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set("Authorization", "Bearer "+env.adminToken)
+	rec := httptest.NewRecorder()
+	env.server.ServeHTTP(rec, req)
+
+	// Assert
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var user dto.AdminUserResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &user))
+	assert.True(t, user.TestAccount)
+}
@@ -1,12 +1,15 @@
 package integration
 
 import (
+	"bytes"
 	"encoding/json"
+	"mime/multipart"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 	"github.com/terraforge-gg/terraforge/internal/auth"
 	"github.com/terraforge-gg/terraforge/internal/dto"
+	"github.com/terraforge-gg/terraforge/internal/lib/tmod"
 )
 
 func generateTestToken(t *testing.T, testAuth *auth.TestAuth, userId string, username string, email string) string {
@@ -99,3 +102,38 @@ func createCreateReleaseRequestBody(t *testing.T, name string, versionNumber str
 
 	return string(b)
 }
+
+// Builds a publish request body with the metadata part followed by the file part.
+// Returns the body and its multipart content type.
+func createPublishReleaseRequestBody(t *testing.T, name string, versionNumber string, changelog *string, loaderVersionId string, fileName string, fileContent []byte) (*bytes.Buffer, string) {
+	t.Helper()
+	metadata, err := json.Marshal(dto.PublishProjectReleaseRequest{
+		Name:            name,
+		VersionNumber:   versionNumber,
+		Changelog:       changelog,
+		LoaderVersionId: loaderVersionId,
+	})
+	require.NoError(t, err)
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	require.NoError(t, writer.WriteField("metadata", string(metadata)))
+
+	part, err := writer.CreateFormFile("file", fileName)
+	require.NoError(t, err)
+
+	_, err = part.Write(fileContent)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	return body, writer.FormDataContentType()
+}
+
+// Minimal .tmod contents: the magic bytes, a length-prefixed loader version and some payload.
+func createTestTmodFile(loaderVersion string) []byte {
+	b := []byte(tmod.Magic)
+	b = append(b, byte(len(loaderVersion)))
+	b = append(b, loaderVersion...)
+	return append(b, []byte("fake mod file content for testing")...)
+}
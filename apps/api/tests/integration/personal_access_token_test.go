@@ -0,0 +1,201 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/terraforge-gg/terraforge/internal/database"
+	"github.com/terraforge-gg/terraforge/internal/dto"
+	"github.com/terraforge-gg/terraforge/internal/models"
+)
+
+func createPersonalAccessToken(t *testing.T, env *testEnv, scopes []string, projects []string) dto.CreatePersonalAccessTokenResponse {
+	t.Helper()
+
+	b, err := json.Marshal(dto.CreatePersonalAccessTokenRequest{
+		Name:     "ci",
+		Scopes:   scopes,
+		Projects: projects,
+	})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/tokens", strings.NewReader(string(b)))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set("Authorization", "Bearer "+env.token1)
+	rec := httptest.NewRecorder()
+	env.server.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	var response dto.CreatePersonalAccessTokenResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+
+	return response
+}
+
+func TestIntegration_CreatePersonalAccessToken(t *testing.T) {
+	// Arrange
+	env := newTestEnv(t)
+
+	// Act
+	token := createPersonalAccessToken(t, env, []string{string(models.TokenScopeProjectWrite)}, nil)
+
+	// Assert
+	assert.True(t, strings.HasPrefix(token.Token, models.PersonalAccessTokenPrefix))
+	assert.True(t, strings.HasPrefix(token.Token, token.TokenPrefix))
+	assert.Equal(t, []string{string(models.TokenScopeProjectWrite)}, token.Scopes)
+}
+
+func TestIntegration_CreatePersonalAccessToken_InvalidScope(t *testing.T) {
+	// Arrange
+	env := newTestEnv(t)
+	body := `{"name":"ci","scopes":["everything"]}`
+
+	// Act
+	req := httptest.NewRequest(http.MethodPost, "/v1/tokens", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set("Authorization", "Bearer "+env.token1)
+	rec := httptest.NewRecorder()
+	env.server.ServeHTTP(rec, req)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestIntegration_PersonalAccessToken_CreatesProject(t *testing.T) {
+	// Arrange
+	env := newTestEnv(t)
+	token := createPersonalAccessToken(t, env, []string{string(models.TokenScopeProjectWrite)}, nil)
+	summary := ExampleModSummary
+	body := createCreateProjectRequestBody(t, ExampleModName, ExampleModSlug, &summary, string(models.ProjectTypeMod))
+
+	// Act
+	req := httptest.NewRequest(http.MethodPost, "/v1/projects", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set("Authorization", "Bearer "+token.Token)
+	rec := httptest.NewRecorder()
+	env.server.ServeHTTP(rec, req)
+
+	// Assert
+	assert.Equal(t, http.StatusCreated, rec.Code)
+}
+
+func TestIntegration_PersonalAccessToken_MissingScope(t *testing.T) {
+	// Arrange
+	env := newTestEnv(t)
+	token := createPersonalAccessToken(t, env, []string{string(models.TokenScopeProjectRead)}, nil)
+	summary := ExampleModSummary
+	body := createCreateProjectRequestBody(t, ExampleModName, ExampleModSlug, &summary, string(models.ProjectTypeMod))
+
+	// Act
+	req := httptest.NewRequest(http.MethodPost, "/v1/projects", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set("Authorization", "Bearer "+token.Token)
+	rec := httptest.NewRecorder()
+	env.server.ServeHTTP(rec, req)
+
+	// Assert
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+func TestIntegration_PersonalAccessToken_ProjectRestricted(t *testing.T) {
+	// Arrange
+	env := newTestEnv(t)
+	summary := ExampleModSummary
+	for _, slug := range []string{ExampleModSlug, CoolModSlug} {
+		body := createCreateProjectRequestBody(t, slug, slug, &summary, string(models.ProjectTypeMod))
+		req := httptest.NewRequest(http.MethodPost, "/v1/projects", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set("Authorization", "Bearer "+env.token1)
+		rec := httptest.NewRecorder()
+		env.server.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusCreated, rec.Code)
+	}
+	token := createPersonalAccessToken(t, env, []string{string(models.TokenScopeProjectWrite)}, []string{ExampleModSlug})
+	name := CoolModName
+	body := createUpdateProjectRequestBody(t, &name, nil, nil, nil, nil)
+
+	// Act
+	allowedReq := httptest.NewRequest(http.MethodPatch, "/v1/projects/"+ExampleModSlug, strings.NewReader(body))
+	allowedReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	allowedReq.Header.Set("Authorization", "Bearer "+token.Token)
+	allowedRec := httptest.NewRecorder()
+	env.server.ServeHTTP(allowedRec, allowedReq)
+
+	deniedReq := httptest.NewRequest(http.MethodPatch, "/v1/projects/"+CoolModSlug, strings.NewReader(body))
+	deniedReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	deniedReq.Header.Set("Authorization", "Bearer "+token.Token)
+	deniedRec := httptest.NewRecorder()
+	env.server.ServeHTTP(deniedRec, deniedReq)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, allowedRec.Code)
+	assert.Equal(t, http.StatusForbidden, deniedRec.Code)
+}
+
+func TestIntegration_RevokePersonalAccessToken(t *testing.T) {
+	// Arrange
+	env := newTestEnv(t)
+	token := createPersonalAccessToken(t, env, []string{string(models.TokenScopeProjectWrite)}, nil)
+
+	// Act
+	req := httptest.NewRequest(http.MethodDelete, "/v1/tokens/"+token.Id, nil)
+	req.Header.Set("Authorization", "Bearer "+env.token1)
+	rec := httptest.NewRecorder()
+	env.server.ServeHTTP(rec, req)
+
+	listReq := httptest.NewRequest(http.MethodGet, "/v1/tokens", nil)
+	listReq.Header.Set("Authorization", "Bearer "+token.Token)
+	listRec := httptest.NewRecorder()
+	env.server.ServeHTTP(listRec, listReq)
+
+	// Assert
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, http.StatusUnauthorized, listRec.Code)
+}
+
+func TestIntegration_PersonalAccessToken_CannotManageTokens(t *testing.T) {
+	// Arrange
+	env := newTestEnv(t)
+	token := createPersonalAccessToken(t, env, []string{string(models.TokenScopeProjectWrite)}, nil)
+
+	// Act
+	req := httptest.NewRequest(http.MethodGet, "/v1/tokens", nil)
+	req.Header.Set("Authorization", "Bearer "+token.Token)
+	rec := httptest.NewRecorder()
+	env.server.ServeHTTP(rec, req)
+
+	// Assert
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+func TestIntegration_PersonalAccessToken_EmailNoLongerVerified(t *testing.T) {
+	// Arrange
+	env := newTestEnv(t)
+	token := createPersonalAccessToken(t, env, []string{string(models.TokenScopeProjectWrite)}, nil)
+
+	_, err := env.db.Db.Exec(`UPDATE "user" SET "emailVerified" = false WHERE "id" = $1`, database.TestUser1Id)
+	require.NoError(t, err)
+
+	summary := ExampleModSummary
+	body := createCreateProjectRequestBody(t, ExampleModName, ExampleModSlug, &summary, string(models.ProjectTypeMod))
+
+	// Act
+	req := httptest.NewRequest(http.MethodPost, "/v1/projects", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set("Authorization", "Bearer "+token.Token)
+	rec := httptest.NewRecorder()
+	env.server.ServeHTTP(rec, req)
+
+	// Assert
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	var problem dto.ProblemDetails
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+	assert.Equal(t, dto.ProblemTypeEmailNotVerified, problem.Type)
+}
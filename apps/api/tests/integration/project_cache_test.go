@@ -0,0 +1,173 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/terraforge-gg/terraforge/internal/background"
+	"github.com/terraforge-gg/terraforge/internal/cache"
+	"github.com/terraforge-gg/terraforge/internal/database"
+	custom_errors "github.com/terraforge-gg/terraforge/internal/errors"
+	redis_client_wrapper "github.com/terraforge-gg/terraforge/internal/lib/redis"
+	"github.com/terraforge-gg/terraforge/internal/logger"
+	"github.com/terraforge-gg/terraforge/internal/models"
+	"github.com/terraforge-gg/terraforge/internal/pagination"
+	"github.com/terraforge-gg/terraforge/internal/repository"
+	"github.com/terraforge-gg/terraforge/internal/service"
+)
+
+// The project and release services backed by Redis rather than the mock caches of testEnv,
+// with an approved project whose project, members and releases are all cached.
+type cachedProjectEnv struct {
+	redis          *redis_client_wrapper.TestRedis
+	projectService service.ProjectService
+	project        *models.Project
+}
+
+func newCachedProjectEnv(t *testing.T) *cachedProjectEnv {
+	t.Helper()
+
+	env := newTestEnv(t)
+	tr, err := redis_client_wrapper.NewTestRedisWithCleanup(t)
+	require.NoError(t, err)
+
+	tasks := background.NewGroup()
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		tasks.Shutdown(ctx)
+	})
+
+	log := logger.New()
+	projectRepo := repository.NewProjectRepository()
+	projectService := service.NewProjectService(log, env.db.Db, projectRepo, repository.NewMockSearchRepository(), cache.NewProjectCache(tr.Client, nil), repository.NewUserRepository(), tasks)
+	releaseService := service.NewProjectReleaseService(
+		log,
+		"",
+		env.db.Db,
+		projectRepo,
+		repository.NewProjectReleaseRepository(),
+		repository.NewLoaderVersionRepository(),
+		nil,
+		cache.NewReleaseCache(tr.Client),
+		tasks,
+	)
+
+	ctx := context.Background()
+	summary := ExampleModSummary
+	project, err := projectService.CreateUserProject(ctx, service.CreateUserProjectParams{
+		Name:    ExampleModName,
+		Slug:    ExampleModSlug,
+		Summary: &summary,
+		Type:    models.ProjectTypeMod,
+		UserId:  database.TestUser1Id,
+	})
+	require.NoError(t, err)
+
+	// Only approved projects are cached
+	_, err = env.db.Db.Exec(`UPDATE "project" SET "status" = 'approved' WHERE "id" = $1`, project.Id)
+	require.NoError(t, err)
+
+	_, err = projectService.GetProjectByIdentifier(ctx, service.GetProjectByIdentifierParams{Identifier: ExampleModSlug})
+	require.NoError(t, err)
+	_, err = projectService.GetProjectMembers(ctx, service.GetProjectMembersParams{
+		Identifier: ExampleModSlug,
+		Page:       pagination.Params{Limit: pagination.DefaultLimit},
+	})
+	require.NoError(t, err)
+	_, err = releaseService.GetReleasesByProjectId(ctx, ExampleModSlug, "", pagination.Params{Limit: pagination.DefaultLimit})
+	require.NoError(t, err)
+
+	c := &cachedProjectEnv{redis: tr, projectService: projectService, project: project}
+	c.requireKeys(t, c.derivedKeys(ExampleModSlug)...)
+
+	return c
+}
+
+// The keys the cache derives from the project, as written by internal/cache.
+func (c *cachedProjectEnv) derivedKeys(slug string) []string {
+	return []string{
+		"project:" + c.project.Id,
+		"project:slug:" + slug,
+		"project:" + c.project.Id + ":members",
+		"project:slug:" + slug + ":members",
+		"project:" + c.project.Id + ":releases",
+	}
+}
+
+func (c *cachedProjectEnv) existingKeys(t *testing.T, keys ...string) []string {
+	t.Helper()
+
+	var existing []string
+
+	for _, key := range keys {
+		n, err := c.redis.Client.Client.Exists(context.Background(), key).Result()
+		require.NoError(t, err)
+
+		if n > 0 {
+			existing = append(existing, key)
+		}
+	}
+
+	return existing
+}
+
+func (c *cachedProjectEnv) requireKeys(t *testing.T, keys ...string) {
+	t.Helper()
+	require.ElementsMatch(t, keys, c.existingKeys(t, keys...))
+}
+
+func TestIntegration_UpdateProject_RemovesDerivedCacheKeys(t *testing.T) {
+	// Arrange
+	c := newCachedProjectEnv(t)
+	ctx := context.Background()
+	newSlug := "renamed-mod"
+
+	// Act
+	_, err := c.projectService.UpdateProject(ctx, service.UpdateProjectParams{
+		Identifier: ExampleModSlug,
+		Slug:       &newSlug,
+		UserId:     database.TestUser1Id,
+	})
+	require.NoError(t, err)
+
+	// Assert
+	assert.Empty(t, c.existingKeys(t,
+		"project:slug:"+ExampleModSlug,
+		"project:slug:"+ExampleModSlug+":members",
+		"project:"+c.project.Id+":members",
+		"project:"+c.project.Id+":releases",
+	))
+
+	// The updated project is cached again under its new slug only
+	c.requireKeys(t, "project:"+c.project.Id, "project:slug:"+newSlug)
+
+	_, err = c.projectService.GetProjectByIdentifier(ctx, service.GetProjectByIdentifierParams{Identifier: ExampleModSlug})
+	assert.ErrorIs(t, err, custom_errors.ErrProjectNotFound)
+
+	project, err := c.projectService.GetProjectByIdentifier(ctx, service.GetProjectByIdentifierParams{Identifier: newSlug})
+	require.NoError(t, err)
+	assert.Equal(t, newSlug, project.Slug)
+}
+
+func TestIntegration_DeleteProject_RemovesDerivedCacheKeys(t *testing.T) {
+	// Arrange
+	c := newCachedProjectEnv(t)
+	ctx := context.Background()
+
+	// Act
+	err := c.projectService.DeleteProject(ctx, service.DeleteProjectParams{
+		Identifier: ExampleModSlug,
+		UserId:     database.TestUser1Id,
+	})
+	require.NoError(t, err)
+
+	// Assert
+	assert.Empty(t, c.existingKeys(t, append(c.derivedKeys(ExampleModSlug), "project:"+c.project.Id+":keys")...))
+
+	_, err = c.projectService.GetProjectByIdentifier(ctx, service.GetProjectByIdentifierParams{Identifier: ExampleModSlug})
+	assert.ErrorIs(t, err, custom_errors.ErrProjectNotFound)
+}
@@ -245,12 +245,14 @@ func TestIntegration_GetReleases(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, getReleasesRec.Code)
 
-	var response []dto.ProjectReleaseResponse
+	var response dto.ListResponse[dto.ProjectReleaseResponse]
 	err = json.Unmarshal(getReleasesRec.Body.Bytes(), &response)
 	require.NoError(t, err)
-	assert.Len(t, response, 1)
-	assert.Equal(t, ExampleReleaseName, response[0].Name)
-	assert.Equal(t, ExampleReleaseVersion, response[0].VersionNumber)
+	assert.Len(t, response.Data, 1)
+	assert.Empty(t, response.Next)
+	assert.Empty(t, getReleasesRec.Header().Get("Link"))
+	assert.Equal(t, ExampleReleaseName, response.Data[0].Name)
+	assert.Equal(t, ExampleReleaseVersion, response.Data[0].VersionNumber)
 }
 
 func TestIntegration_GetRelease(t *testing.T) {
@@ -472,3 +474,145 @@ func TestIntegration_CreateRelease_Unauthorized(t *testing.T) {
 
 	assert.Equal(t, http.StatusUnauthorized, releaseRec.Code)
 }
+
+func TestIntegration_PublishRelease(t *testing.T) {
+	// Arrange
+	env := newTestEnv(t)
+	summary := ExampleModSummary
+	body := createCreateProjectRequestBody(t, ExampleModName, ExampleModSlug, &summary, "mod")
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/projects", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set("Authorization", "Bearer "+env.token1)
+	rec := httptest.NewRecorder()
+	env.server.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	changelog := ExampleReleaseChangelog
+	fileContent := createTestTmodFile("v2025.1.0.0")
+	publishBody, contentType := createPublishReleaseRequestBody(
+		t,
+		ExampleReleaseName,
+		ExampleReleaseVersion,
+		&changelog,
+		database.TestLoaderVersionId,
+		ExampleModSlug+".tmod",
+		fileContent,
+	)
+
+	// Act
+	publishReq := httptest.NewRequest(http.MethodPost, "/v1/projects/"+ExampleModSlug+"/releases/publish", publishBody)
+	publishReq.Header.Set(echo.HeaderContentType, contentType)
+	publishReq.Header.Set("Authorization", "Bearer "+env.token1)
+	publishRec := httptest.NewRecorder()
+	env.server.ServeHTTP(publishRec, publishReq)
+
+	// Assert
+	assert.Equal(t, http.StatusCreated, publishRec.Code)
+	var response dto.ProjectReleaseResponse
+	err := json.Unmarshal(publishRec.Body.Bytes(), &response)
+	require.NoError(t, err)
+	assert.Equal(t, ExampleReleaseName, response.Name)
+	assert.Equal(t, ExampleReleaseVersion, response.VersionNumber)
+	assert.Equal(t, int64(len(fileContent)), response.FileSize)
+	assert.True(t, strings.HasSuffix(response.FileUrl, ".tmod"))
+}
+
+func TestIntegration_PublishRelease_InvalidFile(t *testing.T) {
+	// Arrange
+	env := newTestEnv(t)
+	summary := ExampleModSummary
+	body := createCreateProjectRequestBody(t, ExampleModName, ExampleModSlug, &summary, "mod")
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/projects", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set("Authorization", "Bearer "+env.token1)
+	rec := httptest.NewRecorder()
+	env.server.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	publishBody, contentType := createPublishReleaseRequestBody(
+		t,
+		ExampleReleaseName,
+		ExampleReleaseVersion,
+		nil,
+		database.TestLoaderVersionId,
+		ExampleModSlug+".tmod",
+		[]byte("not a tmod file"),
+	)
+
+	// Act
+	publishReq := httptest.NewRequest(http.MethodPost, "/v1/projects/"+ExampleModSlug+"/releases/publish", publishBody)
+	publishReq.Header.Set(echo.HeaderContentType, contentType)
+	publishReq.Header.Set("Authorization", "Bearer "+env.token1)
+	publishRec := httptest.NewRecorder()
+	env.server.ServeHTTP(publishRec, publishReq)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, publishRec.Code)
+}
+
+// Uploads a file and creates the example release for the project with slug, as user 1.
+func createTestRelease(t *testing.T, env *testEnv, slug string) dto.ProjectReleaseResponse {
+	t.Helper()
+
+	uploadUrlReq := httptest.NewRequest(http.MethodGet, "/v1/projects/"+slug+"/releases/upload-url?fileSize="+ExampleReleaseFileSize, nil)
+	uploadUrlReq.Header.Set("Authorization", "Bearer "+env.token1)
+	uploadUrlRec := httptest.NewRecorder()
+	env.server.ServeHTTP(uploadUrlRec, uploadUrlReq)
+	require.Equal(t, http.StatusOK, uploadUrlRec.Code)
+
+	var uploadUrl string
+	require.NoError(t, json.Unmarshal(uploadUrlRec.Body.Bytes(), &uploadUrl))
+
+	putReq, err := http.NewRequest(http.MethodPut, uploadUrl, bytes.NewReader([]byte("fake mod file content for testing")))
+	require.NoError(t, err)
+	putReq.Header.Set("Content-Type", "application/octet-stream")
+	putRes, err := http.DefaultClient.Do(putReq)
+	require.NoError(t, err)
+	defer putRes.Body.Close()
+	require.Equal(t, http.StatusOK, putRes.StatusCode)
+
+	origin, pathname, err := utils.ExtractOriginAndPathFromUrl(uploadUrl)
+	require.NoError(t, err)
+
+	changelog := ExampleReleaseChangelog
+	releaseBody := createCreateReleaseRequestBody(t, ExampleReleaseName, ExampleReleaseVersion, &changelog, database.TestLoaderVersionId, origin+pathname, nil)
+	releaseReq := httptest.NewRequest(http.MethodPost, "/v1/projects/"+slug+"/releases", strings.NewReader(releaseBody))
+	releaseReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	releaseReq.Header.Set("Authorization", "Bearer "+env.token1)
+	releaseRec := httptest.NewRecorder()
+	env.server.ServeHTTP(releaseRec, releaseReq)
+	require.Equal(t, http.StatusOK, releaseRec.Code)
+
+	var release dto.ProjectReleaseResponse
+	require.NoError(t, json.Unmarshal(releaseRec.Body.Bytes(), &release))
+
+	return release
+}
+
+func TestIntegration_GetRelease_OfAnotherProject(t *testing.T) {
+	// Arrange
+	env := newTestEnv(t)
+	createTestProject(t, env)
+	release := createTestRelease(t, env, ExampleModSlug)
+
+	summary := CoolModSummary
+	body := createCreateProjectRequestBody(t, CoolModName, CoolModSlug, &summary, "mod")
+	req := httptest.NewRequest(http.MethodPost, "/v1/projects", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set("Authorization", "Bearer "+env.token1)
+	rec := httptest.NewRecorder()
+	env.server.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	// Act
+	getReleaseReq := httptest.NewRequest(http.MethodGet, "/v1/projects/"+CoolModSlug+"/releases/"+release.Id, nil)
+	getReleaseReq.Header.Set("Authorization", "Bearer "+env.token1)
+	getReleaseRec := httptest.NewRecorder()
+	env.server.ServeHTTP(getReleaseRec, getReleaseReq)
+
+	// Assert
+	assert.Equal(t, http.StatusNotFound, getReleaseRec.Code)
+	assert.Contains(t, getReleaseRec.Body.String(), "release-not-found")
+}
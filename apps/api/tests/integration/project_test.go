@@ -215,3 +215,164 @@ func TestIntegration_GetProject_NotFound(t *testing.T) {
 	// Assert
 	assert.Equal(t, http.StatusNotFound, rec.Code)
 }
+
+func TestIntegration_CreateProject_EmailNotVerified(t *testing.T) {
+	// Arrange
+	env := newTestEnv(t)
+	token, err := env.testAuth.GenerateTokenWithClaims(database.TestUser1Id, database.TestUser1Username, database.TestUser1Email, map[string]any{
+		"emailVerified": false,
+	})
+	require.NoError(t, err)
+	summary := ExampleModSummary
+	body := createCreateProjectRequestBody(t, ExampleModName, ExampleModSlug, &summary, string(models.ProjectTypeMod))
+
+	// Act
+	req := httptest.NewRequest(http.MethodPost, "/v1/projects", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	env.server.ServeHTTP(rec, req)
+
+	// Assert
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	var problem dto.ProblemDetails
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+	assert.Equal(t, dto.ProblemTypeEmailNotVerified, problem.Type)
+}
+
+func TestIntegration_CreateProject_TestAccountEmailNotVerified(t *testing.T) {
+	// Arrange
+	env := newTestEnv(t)
+	token, err := env.testAuth.GenerateTokenWithClaims(database.TestUser1Id, database.TestUser1Username, database.TestUser1Email, map[string]any{
+		"emailVerified": false,
+		"testAccount":   true,
+	})
+	require.NoError(t, err)
+	summary := ExampleModSummary
+	body := createCreateProjectRequestBody(t, ExampleModName, ExampleModSlug, &summary, string(models.ProjectTypeMod))
+
+	// Act
+	req := httptest.NewRequest(http.MethodPost, "/v1/projects", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	env.server.ServeHTTP(rec, req)
+
+	// Assert
+	assert.Equal(t, http.StatusCreated, rec.Code)
+}
+
+func addProjectMember(env *testEnv, token string, identifier string, user string, role string) *httptest.ResponseRecorder {
+	body := `{"user":"` + user + `","role":"` + role + `"}`
+	req := httptest.NewRequest(http.MethodPost, "/v1/projects/"+identifier+"/members", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	env.server.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestIntegration_AddProjectMember(t *testing.T) {
+	// Arrange
+	env := newTestEnv(t)
+	project := createTestProject(t, env)
+
+	// Act
+	rec := addProjectMember(env, env.token1, project.Slug, database.TestUser2Username, string(models.ProjectMemberRoleDeveloper))
+
+	listReq := httptest.NewRequest(http.MethodGet, "/v1/projects/"+project.Slug+"/members", nil)
+	listReq.Header.Set("Authorization", "Bearer "+env.token1)
+	listRec := httptest.NewRecorder()
+	env.server.ServeHTTP(listRec, listReq)
+
+	// Assert
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	var member dto.ProjectMemberResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &member))
+	assert.Equal(t, database.TestUser2Id, member.UserId)
+	assert.Equal(t, database.TestUser2Username, member.Username)
+	assert.Equal(t, string(models.ProjectMemberRoleDeveloper), member.Role)
+
+	require.Equal(t, http.StatusOK, listRec.Code)
+	var members dto.ListResponse[dto.ProjectMemberResponse]
+	require.NoError(t, json.Unmarshal(listRec.Body.Bytes(), &members))
+	assert.Len(t, members.Data, 2)
+}
+
+func TestIntegration_AddProjectMember_AlreadyMember(t *testing.T) {
+	// Arrange
+	env := newTestEnv(t)
+	project := createTestProject(t, env)
+
+	// Act
+	rec := addProjectMember(env, env.token1, project.Slug, database.TestUser1Id, string(models.ProjectMemberRoleMember))
+
+	// Assert
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Contains(t, rec.Body.String(), "project-member-exists")
+}
+
+func TestIntegration_AddProjectMember_OwnerRole(t *testing.T) {
+	// Arrange
+	env := newTestEnv(t)
+	project := createTestProject(t, env)
+
+	// Act
+	rec := addProjectMember(env, env.token1, project.Slug, database.TestUser2Id, string(models.ProjectMemberRoleOwner))
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestIntegration_AddProjectMember_UserNotFound(t *testing.T) {
+	// Arrange
+	env := newTestEnv(t)
+	project := createTestProject(t, env)
+
+	// Act
+	rec := addProjectMember(env, env.token1, project.Slug, "nobody", string(models.ProjectMemberRoleMember))
+
+	// Assert
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Contains(t, rec.Body.String(), "user-not-found")
+}
+
+func TestIntegration_AddProjectMember_NotOwner(t *testing.T) {
+	// Arrange
+	env := newTestEnv(t)
+	project := createTestProject(t, env)
+	require.Equal(t, http.StatusCreated, addProjectMember(env, env.token1, project.Slug, database.TestUser2Id, string(models.ProjectMemberRoleAdmin)).Code)
+
+	// Drafts are only visible to their owner
+	_, err := env.db.Db.Exec(`UPDATE "project" SET "status" = 'approved' WHERE "id" = $1`, project.Id)
+	require.NoError(t, err)
+
+	// Act
+	rec := addProjectMember(env, env.token2, project.Slug, database.TestUser1Id, string(models.ProjectMemberRoleMember))
+
+	// Assert
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+func TestIntegration_AddProjectMember_EmailNotVerified(t *testing.T) {
+	// Arrange
+	env := newTestEnv(t)
+	project := createTestProject(t, env)
+	token, err := env.testAuth.GenerateTokenWithClaims(database.TestUser1Id, database.TestUser1Username, database.TestUser1Email, map[string]any{
+		"emailVerified": false,
+	})
+	require.NoError(t, err)
+
+	// Act
+	rec := addProjectMember(env, token, project.Slug, database.TestUser2Id, string(models.ProjectMemberRoleMember))
+
+	// Assert
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	var problem dto.ProblemDetails
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+	assert.Equal(t, dto.ProblemTypeEmailNotVerified, problem.Type)
+}
@@ -0,0 +1,198 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/terraforge-gg/terraforge/internal/background"
+	"github.com/terraforge-gg/terraforge/internal/logger"
+	"github.com/terraforge-gg/terraforge/internal/models"
+	"github.com/terraforge-gg/terraforge/internal/repository"
+	"github.com/terraforge-gg/terraforge/internal/service"
+	"github.com/terraforge-gg/terraforge/internal/utils"
+)
+
+// Runs record with a search analytics service sampling at sampleRate and waits for the rows
+// it writes in the background.
+func recordSearchAnalytics(t *testing.T, env *testEnv, sampleRate float64, record func(s service.SearchAnalyticsService)) {
+	t.Helper()
+
+	tasks := background.NewGroup()
+	record(service.NewSearchAnalyticsService(logger.New(), env.db.Db, repository.NewSearchAnalyticsRepository(), sampleRate, tasks))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	require.NoError(t, tasks.Shutdown(ctx))
+}
+
+func countRows(t *testing.T, env *testEnv, table string) int {
+	t.Helper()
+
+	var count int
+	require.NoError(t, env.db.Db.QueryRow(`SELECT COUNT(*) FROM "`+table+`"`).Scan(&count))
+
+	return count
+}
+
+func insertSearchQuery(t *testing.T, env *testEnv, query string, resultCount int64) string {
+	t.Helper()
+
+	searchQuery := &models.SearchQuery{
+		Id:              utils.NewUUID(),
+		Index:           models.SearchIndexProjects,
+		Query:           query,
+		NormalizedQuery: query,
+		ResultCount:     resultCount,
+		Limit:           25,
+		CreatedAt:       time.Now().UTC(),
+	}
+	require.NoError(t, repository.NewSearchAnalyticsRepository().InsertSearchQuery(context.Background(), env.db.Db, searchQuery))
+
+	return searchQuery.Id
+}
+
+func insertSearchClick(t *testing.T, env *testEnv, searchQueryId string, projectId string, clickType models.SearchClickType) {
+	t.Helper()
+
+	err := repository.NewSearchAnalyticsRepository().InsertSearchClick(context.Background(), env.db.Db, &models.SearchClick{
+		Id:            utils.NewUUID(),
+		SearchQueryId: searchQueryId,
+		ProjectId:     projectId,
+		Type:          clickType,
+		CreatedAt:     time.Now().UTC(),
+	})
+	require.NoError(t, err)
+}
+
+func TestIntegration_RecordSearch_NotSampled(t *testing.T) {
+	// Arrange
+	env := newTestEnv(t)
+	var sampled bool
+
+	// Act
+	recordSearchAnalytics(t, env, 0, func(s service.SearchAnalyticsService) {
+		_, sampled = s.RecordSearch(context.Background(), service.RecordSearchParams{Index: models.SearchIndexProjects, Query: "boss"})
+	})
+
+	// Assert
+	assert.False(t, sampled)
+	assert.Zero(t, countRows(t, env, "search_query"))
+}
+
+func TestIntegration_RecordSearch_Sampled(t *testing.T) {
+	// Arrange
+	env := newTestEnv(t)
+	var searchId string
+	var sampled bool
+
+	// Act
+	recordSearchAnalytics(t, env, 1, func(s service.SearchAnalyticsService) {
+		searchId, sampled = s.RecordSearch(context.Background(), service.RecordSearchParams{
+			Index:       models.SearchIndexProjects,
+			Query:       "  Boss   Checklist ",
+			ResultCount: 3,
+			Limit:       25,
+		})
+	})
+
+	// Assert
+	require.True(t, sampled)
+
+	var normalizedQuery string
+	require.NoError(t, env.db.Db.QueryRow(`SELECT "normalizedQuery" FROM "search_query" WHERE "id" = $1`, searchId).Scan(&normalizedQuery))
+	assert.Equal(t, "boss checklist", normalizedQuery)
+}
+
+func TestIntegration_RecordClick(t *testing.T) {
+	// Arrange
+	env := newTestEnv(t)
+	project := createTestProject(t, env)
+	searchId := insertSearchQuery(t, env, "example", 1)
+
+	// Act
+	recordSearchAnalytics(t, env, 1, func(s service.SearchAnalyticsService) {
+		s.RecordClick(context.Background(), service.RecordClickParams{SearchQueryId: searchId, ProjectId: project.Id, Type: models.SearchClickTypeView})
+	})
+
+	// Assert
+	assert.Equal(t, 1, countRows(t, env, "search_click"))
+}
+
+func TestIntegration_RecordClick_UnknownSearchDropped(t *testing.T) {
+	// Arrange
+	env := newTestEnv(t)
+	project := createTestProject(t, env)
+
+	// Act
+	recordSearchAnalytics(t, env, 1, func(s service.SearchAnalyticsService) {
+		s.RecordClick(context.Background(), service.RecordClickParams{SearchQueryId: "made-up", ProjectId: project.Id, Type: models.SearchClickTypeView})
+	})
+
+	// Assert
+	assert.Zero(t, countRows(t, env, "search_click"))
+}
+
+func TestIntegration_RecordClick_RepeatedClickCountedOnce(t *testing.T) {
+	// Arrange
+	env := newTestEnv(t)
+	project := createTestProject(t, env)
+	searchId := insertSearchQuery(t, env, "example", 1)
+
+	// Act
+	for range 3 {
+		insertSearchClick(t, env, searchId, project.Id, models.SearchClickTypeDownload)
+	}
+
+	// Assert
+	assert.Equal(t, 1, countRows(t, env, "search_click"))
+}
+
+func TestIntegration_FindTopQueries(t *testing.T) {
+	// Arrange
+	env := newTestEnv(t)
+	project := createTestProject(t, env)
+
+	clicked := insertSearchQuery(t, env, "boss", 10)
+	insertSearchQuery(t, env, "boss", 20)
+	insertSearchQuery(t, env, "ui", 5)
+
+	insertSearchClick(t, env, clicked, project.Id, models.SearchClickTypeView)
+	insertSearchClick(t, env, clicked, project.Id, models.SearchClickTypeDownload)
+
+	// Act
+	stats, err := repository.NewSearchAnalyticsRepository().FindTopQueries(context.Background(), env.db.Db, time.Now().UTC().Add(-time.Hour), 10)
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, stats, 2)
+
+	assert.Equal(t, "boss", stats[0].NormalizedQuery)
+	assert.EqualValues(t, 2, stats[0].Searches)
+	// Not weighted towards the clicked search
+	assert.InDelta(t, 15, stats[0].AverageResultCount, 0.001)
+	assert.EqualValues(t, 2, stats[0].Clicks)
+
+	assert.Equal(t, "ui", stats[1].NormalizedQuery)
+	assert.EqualValues(t, 1, stats[1].Searches)
+	assert.Zero(t, stats[1].Clicks)
+}
+
+func TestIntegration_FindZeroResultQueries(t *testing.T) {
+	// Arrange
+	env := newTestEnv(t)
+	insertSearchQuery(t, env, "calamity", 0)
+	insertSearchQuery(t, env, "calamity", 0)
+	insertSearchQuery(t, env, "boss", 10)
+
+	// Act
+	stats, err := repository.NewSearchAnalyticsRepository().FindZeroResultQueries(context.Background(), env.db.Db, time.Now().UTC().Add(-time.Hour), 10)
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, stats, 1)
+	assert.Equal(t, "calamity", stats[0].NormalizedQuery)
+	assert.EqualValues(t, 2, stats[0].Searches)
+}
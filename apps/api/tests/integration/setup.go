@@ -1,18 +1,23 @@
 package integration
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/labstack/echo/v5"
 	"github.com/stretchr/testify/require"
 	"github.com/terraforge-gg/terraforge/internal/auth"
+	"github.com/terraforge-gg/terraforge/internal/background"
 	"github.com/terraforge-gg/terraforge/internal/cache"
 	"github.com/terraforge-gg/terraforge/internal/config"
 	"github.com/terraforge-gg/terraforge/internal/database"
+	custom_errors "github.com/terraforge-gg/terraforge/internal/errors"
 	"github.com/terraforge-gg/terraforge/internal/handler"
 	"github.com/terraforge-gg/terraforge/internal/lib/aws"
 	"github.com/terraforge-gg/terraforge/internal/logger"
 	"github.com/terraforge-gg/terraforge/internal/middleware"
+	"github.com/terraforge-gg/terraforge/internal/models"
 	"github.com/terraforge-gg/terraforge/internal/repository"
 	"github.com/terraforge-gg/terraforge/internal/service"
 	"github.com/terraforge-gg/terraforge/internal/validation"
@@ -22,7 +27,13 @@ type testEnv struct {
 	server *echo.Echo
 	token1 string
 	token2 string
-	cfg    *config.Config
+	// Signed in as user 1 with the admin role
+	adminToken string
+	cfg        *config.Config
+	testAuth   *auth.TestAuth
+	db         *database.TestDatabase
+	// Search is not backed by Meilisearch, tests set the results it returns
+	searchService *service.MockSearchService
 }
 
 func newTestEnv(t *testing.T) *testEnv {
@@ -30,35 +41,56 @@ func newTestEnv(t *testing.T) *testEnv {
 	testAuth, err := auth.NewTestAuth()
 	require.NoError(t, err)
 
-	jwtValidator := auth.NewValidatorFromJWKS(testAuth.JWKS)
-	authMiddleware := middleware.JWTMiddleware(jwtValidator)
-	authOptionalMiddleware := middleware.OptionalJWTMiddleware(jwtValidator)
+	// Registered after the database cleanup so background work finishes before the database goes away
+	tasks := background.NewGroup()
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		tasks.Shutdown(ctx)
+	})
+
+	projectRepo := repository.NewProjectRepository()
+	tokenService := service.NewPersonalAccessTokenService(logger.New(), db.Db, repository.NewPersonalAccessTokenRepository(), projectRepo, tasks)
+
+	jwtValidator := auth.NewValidatorFromJWKS(testAuth.JWKS, testAuth.ValidatorOptions())
+	authMiddleware := middleware.JWTMiddleware(jwtValidator, tokenService)
+	authOptionalMiddleware := middleware.OptionalJWTMiddleware(jwtValidator, tokenService)
+	sessionOnly := middleware.SessionOnly()
 
 	token1 := generateTestToken(t, testAuth, database.TestUser1Id, database.TestUser1Username, database.TestUser1Email)
 	token2 := generateTestToken(t, testAuth, database.TestUser2Id, database.TestUser2Username, database.TestUser2Email)
+	adminToken, err := testAuth.GenerateTokenWithClaims(database.TestUser1Id, database.TestUser1Username, database.TestUser1Email, map[string]any{
+		"role": string(models.UserRoleAdmin),
+	})
+	require.NoError(t, err)
 
 	cfg := &config.Config{
 		Env:         "test",
 		DatabaseUrl: db.DatabaseUrl,
 		R2Bucket:    "terraforge-app-test",
+		// On like in production, test tokens claim a verified email
+		RequireVerifiedEmail: true,
 	}
 
+	verifiedEmail := middleware.RequireVerifiedEmail(cfg.RequireVerifiedEmail)
+
 	s3Client, host, err := aws.NewTestLocalStackS3Client(t, cfg.R2Bucket)
 	cfg.CdnUrl = host
 
 	log := logger.New()
 
-	projectRepo := repository.NewProjectRepository()
 	userRepo := repository.NewUserRepository()
 	searchRepo := repository.NewMockSearchRepository()
 	projectCache := cache.NewMockProjectCache()
 	loaderVersionRepo := repository.NewLoaderVersionRepository()
 	objectStoreService := service.NewObjectStoreService(s3Client, cfg.R2Bucket)
 
-	projectService := service.NewProjectService(log, db.Db, projectRepo, searchRepo, projectCache, userRepo)
+	projectService := service.NewProjectService(log, db.Db, projectRepo, searchRepo, projectCache, userRepo, tasks)
 	searchService := service.NewMockSearchService()
+	searchAnalyticsService := service.NewMockSearchAnalyticsService()
 
-	projectHandler := handler.NewProjectHandler(cfg, log, projectService, searchService)
+	projectHandler := handler.NewProjectHandler(cfg, log, projectService, searchService, searchAnalyticsService)
+	userHandler := handler.NewUserHandler(cfg, log, projectService, searchService, searchAnalyticsService)
 
 	projectReleaseRepo := repository.NewProjectReleaseRepository()
 	projectReleaseService := service.NewProjectReleaseService(
@@ -69,30 +101,88 @@ func newTestEnv(t *testing.T) *testEnv {
 		projectReleaseRepo,
 		loaderVersionRepo,
 		objectStoreService,
+		cache.NewMockReleaseCache(),
+		tasks,
+	)
+	projectReleaseHandler := handler.NewProjectReleaseHandler(cfg, log, projectReleaseService, searchAnalyticsService)
+
+	tokenHandler := handler.NewPersonalAccessTokenHandler(cfg, log, tokenService)
+
+	adminService := service.NewAdminService(
+		log,
+		db.Db,
+		userRepo,
+		projectRepo,
+		repository.NewPersonalAccessTokenRepository(),
+		repository.NewAuditLogRepository(),
+		searchRepo,
+		projectCache,
+		tasks,
 	)
-	projectReleaseHandler := handler.NewProjectReleaseHandler(cfg, log, projectReleaseService)
+	adminHandler := handler.NewAdminHandler(cfg, log, searchAnalyticsService, adminService)
+
+	resolveProjectId := func(ctx context.Context, identifier string, userId string) (string, error) {
+		project, err := projectRepo.FindProjectByIdentifier(ctx, db.Db, identifier, userId)
+		if err != nil {
+			return "", err
+		}
+		if project == nil {
+			return "", custom_errors.ErrProjectNotFound
+		}
+		return project.Id, nil
+	}
+
+	projectRead := middleware.RequireScope(models.TokenScopeProjectRead, resolveProjectId)
+	projectWrite := middleware.RequireScope(models.TokenScopeProjectWrite, resolveProjectId)
+	releaseWrite := middleware.RequireScope(models.TokenScopeReleaseWrite, resolveProjectId)
 
 	validate := validation.NewValidator(cfg)
 
 	e := echo.New()
 	e.Validator = &validation.Validator{Validator: validate}
+	e.HTTPErrorHandler = handler.NewErrorHandler(log)
 
 	v1 := e.Group("/v1")
-	v1.POST("/projects", projectHandler.CreateProject, authMiddleware)
+	v1.POST("/projects", projectHandler.CreateProject, authMiddleware, verifiedEmail, projectWrite)
 	v1.GET("/projects/:identifier", projectHandler.GetProjectByIdentifier, authOptionalMiddleware)
-	v1.PATCH("/projects/:identifier", projectHandler.UpdateProject, authMiddleware)
-	v1.DELETE("/projects/:identifier", projectHandler.DeleteProject, authMiddleware)
+	v1.PATCH("/projects/:identifier", projectHandler.UpdateProject, authMiddleware, projectWrite)
+	v1.DELETE("/projects/:identifier", projectHandler.DeleteProject, authMiddleware, projectWrite)
+	v1.GET("/projects/:identifier/members", projectHandler.GetProjectMembers, authOptionalMiddleware, projectRead)
+	v1.POST("/projects/:identifier/members", projectHandler.AddProjectMember, authMiddleware, verifiedEmail, projectWrite)
 	v1.GET("/projects", projectHandler.SearchProjects)
 
-	v1.POST("/projects/:identifier/releases", projectReleaseHandler.CreateRelease, authMiddleware)
+	v1.POST("/projects/:identifier/releases", projectReleaseHandler.CreateRelease, authMiddleware, verifiedEmail, releaseWrite)
+	v1.POST("/projects/:identifier/releases/publish", projectReleaseHandler.PublishRelease, authMiddleware, verifiedEmail, releaseWrite)
 	v1.GET("/projects/:identifier/releases", projectReleaseHandler.GetReleases, authOptionalMiddleware)
 	v1.GET("/projects/:identifier/releases/:releaseId", projectReleaseHandler.GetRelease, authOptionalMiddleware)
-	v1.GET("/projects/:identifier/releases/upload-url", projectReleaseHandler.GeneratePresignedPutUrl, authMiddleware)
+	v1.GET("/projects/:identifier/releases/upload-url", projectReleaseHandler.GeneratePresignedPutUrl, authMiddleware, verifiedEmail, releaseWrite)
+
+	v1.GET("/users", userHandler.SearchUsers)
+	v1.GET("/users/:userIdentifier/projects", userHandler.GetProjectsByUserId, authOptionalMiddleware, projectRead)
+
+	v1.POST("/tokens", tokenHandler.CreateToken, authMiddleware, sessionOnly, verifiedEmail)
+	v1.GET("/tokens", tokenHandler.GetTokens, authMiddleware, sessionOnly)
+	v1.DELETE("/tokens/:tokenId", tokenHandler.RevokeToken, authMiddleware, sessionOnly)
+
+	requireAdmin := middleware.RequireRole(models.UserRoleAdmin)
+
+	admin := v1.Group("/admin", authMiddleware, sessionOnly, middleware.RequireRole(models.UserRoleModerator))
+	admin.GET("/users", adminHandler.GetUsers)
+	admin.POST("/users/:userId/ban", adminHandler.BanUser, requireAdmin)
+	admin.DELETE("/users/:userId/ban", adminHandler.UnbanUser, requireAdmin)
+	admin.PUT("/projects/:projectId/status", adminHandler.UpdateProjectStatus)
+	admin.PUT("/users/:userId/test-account", adminHandler.UpdateTestAccount, requireAdmin)
+	admin.POST("/projects/:projectId/restore", adminHandler.RestoreProject, requireAdmin)
+	admin.GET("/audit-logs", adminHandler.GetAuditLogs, requireAdmin)
 
 	return &testEnv{
-		server: e,
-		token1: token1,
-		token2: token2,
-		cfg:    cfg,
+		server:        e,
+		token1:        token1,
+		token2:        token2,
+		adminToken:    adminToken,
+		cfg:           cfg,
+		testAuth:      testAuth,
+		db:            db,
+		searchService: searchService,
 	}
 }
@@ -0,0 +1,222 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/terraforge-gg/terraforge/internal/database"
+	"github.com/terraforge-gg/terraforge/internal/dto"
+	"github.com/terraforge-gg/terraforge/internal/models"
+	"github.com/terraforge-gg/terraforge/internal/pagination"
+	"github.com/terraforge-gg/terraforge/internal/repository"
+)
+
+func findUsersWithStats(t *testing.T, env *testEnv, updatedSince time.Time) []models.UserWithStats {
+	t.Helper()
+
+	page, err := repository.NewUserRepository().FindUsersWithStats(context.Background(), env.db.Db, updatedSince, pagination.Params{Limit: 10})
+	require.NoError(t, err)
+
+	return page.Items
+}
+
+func TestIntegration_FindUsersWithStats_CountsApprovedProjects(t *testing.T) {
+	// Arrange
+	env := newTestEnv(t)
+	project := createTestProject(t, env)
+
+	_, err := env.db.Db.Exec(`UPDATE "project" SET "status" = 'approved', "downloads" = 42 WHERE "id" = $1`, project.Id)
+	require.NoError(t, err)
+
+	// Act
+	users := findUsersWithStats(t, env, time.Time{})
+
+	// Assert
+	require.Len(t, users, 2)
+	assert.Equal(t, database.TestUser1Id, users[0].User.Id)
+	assert.Equal(t, models.UserStats{ProjectCount: 1, TotalDownloads: 42}, users[0].Stats)
+	assert.Equal(t, database.TestUser2Id, users[1].User.Id)
+	assert.Equal(t, models.UserStats{}, users[1].Stats)
+}
+
+func TestIntegration_FindUsersWithStats_DraftProjectsNotCounted(t *testing.T) {
+	// Arrange
+	env := newTestEnv(t)
+	createTestProject(t, env)
+
+	// Act
+	users := findUsersWithStats(t, env, time.Time{})
+
+	// Assert
+	require.Len(t, users, 2)
+	assert.Equal(t, models.UserStats{}, users[0].Stats)
+}
+
+func TestIntegration_FindUsersWithStats_IncludesUsersWithUpdatedProjects(t *testing.T) {
+	// Arrange
+	env := newTestEnv(t)
+	createTestProject(t, env)
+
+	_, err := env.db.Db.Exec(`UPDATE "user" SET "updatedAt" = now() - INTERVAL '1 day'`)
+	require.NoError(t, err)
+
+	// Act
+	users := findUsersWithStats(t, env, time.Now().Add(-time.Hour))
+
+	// Assert
+	require.Len(t, users, 1)
+	assert.Equal(t, database.TestUser1Id, users[0].User.Id)
+}
+
+func TestIntegration_FindUsersWithStats_SkipsUnchangedUsers(t *testing.T) {
+	// Arrange
+	env := newTestEnv(t)
+
+	_, err := env.db.Db.Exec(`UPDATE "user" SET "updatedAt" = now() - INTERVAL '1 day'`)
+	require.NoError(t, err)
+
+	// Act
+	users := findUsersWithStats(t, env, time.Now().Add(-time.Hour))
+
+	// Assert
+	assert.Empty(t, users)
+}
+
+func TestIntegration_FindUsersWithStats_Pages(t *testing.T) {
+	// Arrange
+	env := newTestEnv(t)
+	userRepo := repository.NewUserRepository()
+	page := pagination.Params{Limit: 1}
+
+	// Act
+	first, err := userRepo.FindUsersWithStats(context.Background(), env.db.Db, time.Time{}, page)
+	require.NoError(t, err)
+
+	page.Cursor = first.NextCursor
+	second, err := userRepo.FindUsersWithStats(context.Background(), env.db.Db, time.Time{}, page)
+	require.NoError(t, err)
+
+	// Assert
+	require.Len(t, first.Items, 1)
+	assert.Equal(t, database.TestUser1Id, first.Items[0].User.Id)
+	require.Len(t, second.Items, 1)
+	assert.Equal(t, database.TestUser2Id, second.Items[0].User.Id)
+	assert.Empty(t, second.NextCursor)
+}
+
+func TestIntegration_SearchUsers(t *testing.T) {
+	// Arrange
+	env := newTestEnv(t)
+	results := []models.UserWithStats{
+		{User: models.User{Id: "1", Username: "boss"}, Stats: models.UserStats{ProjectCount: 2, TotalDownloads: 10}},
+		{User: models.User{Id: "2", Username: "bossman"}},
+		{User: models.User{Id: "3", Username: "bossy"}},
+	}
+
+	var offsets []int64
+	env.searchService.SearchUsersFunc = func(ctx context.Context, query string, limit int64, offset int64) ([]models.UserWithStats, int64, error) {
+		assert.Equal(t, "boss", query)
+		offsets = append(offsets, offset)
+		return results[offset:min(offset+limit, int64(len(results)))], int64(len(results)), nil
+	}
+
+	// Act
+	req := httptest.NewRequest(http.MethodGet, "/v1/users?q=boss&limit=2", nil)
+	rec := httptest.NewRecorder()
+	env.server.ServeHTTP(rec, req)
+
+	var first dto.UserSearchResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &first))
+
+	nextReq := httptest.NewRequest(http.MethodGet, first.Next, nil)
+	nextRec := httptest.NewRecorder()
+	env.server.ServeHTTP(nextRec, nextReq)
+
+	var second dto.UserSearchResponse
+	require.NoError(t, json.Unmarshal(nextRec.Body.Bytes(), &second))
+
+	// Assert
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, first.Data, 2)
+	assert.Equal(t, "boss", first.Data[0].Username)
+	assert.EqualValues(t, 10, first.Data[0].TotalDownloads)
+	assert.EqualValues(t, 3, first.TotalHits)
+	assert.Equal(t, `<`+first.Next+`>; rel="next"`, rec.Header().Get("Link"))
+
+	require.Equal(t, http.StatusOK, nextRec.Code)
+	require.Len(t, second.Data, 1)
+	assert.Equal(t, "bossy", second.Data[0].Username)
+	assert.Empty(t, second.Next)
+	assert.Equal(t, []int64{0, 2}, offsets)
+}
+
+func TestIntegration_SearchUsers_InvalidCursor(t *testing.T) {
+	// Arrange
+	env := newTestEnv(t)
+
+	// Act
+	req := httptest.NewRequest(http.MethodGet, "/v1/users?cursor=nope", nil)
+	rec := httptest.NewRecorder()
+	env.server.ServeHTTP(rec, req)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "invalid-cursor")
+}
+
+func TestIntegration_GetUserProjects_PagesNewestFirst(t *testing.T) {
+	// Arrange
+	env := newTestEnv(t)
+	summary := ExampleModSummary
+	for _, slug := range []string{ExampleModSlug, CoolModSlug} {
+		body := createCreateProjectRequestBody(t, slug, slug, &summary, string(models.ProjectTypeMod))
+		req := httptest.NewRequest(http.MethodPost, "/v1/projects", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set("Authorization", "Bearer "+env.token1)
+		rec := httptest.NewRecorder()
+		env.server.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusCreated, rec.Code)
+	}
+
+	_, err := env.db.Db.Exec(`UPDATE "project" SET "status" = 'approved', "createdAt" = "createdAt" - INTERVAL '1 day' WHERE "slug" = $1`, ExampleModSlug)
+	require.NoError(t, err)
+	_, err = env.db.Db.Exec(`UPDATE "project" SET "status" = 'approved' WHERE "slug" = $1`, CoolModSlug)
+	require.NoError(t, err)
+
+	// Act
+	req := httptest.NewRequest(http.MethodGet, "/v1/users/"+database.TestUser1Username+"/projects?limit=1", nil)
+	rec := httptest.NewRecorder()
+	env.server.ServeHTTP(rec, req)
+
+	var first dto.ListResponse[dto.ProjectResponse]
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &first))
+
+	// Downloads changing between pages does not move projects between them
+	_, err = env.db.Db.Exec(`UPDATE "project" SET "downloads" = 1000 WHERE "slug" = $1`, ExampleModSlug)
+	require.NoError(t, err)
+
+	nextReq := httptest.NewRequest(http.MethodGet, first.Next, nil)
+	nextRec := httptest.NewRecorder()
+	env.server.ServeHTTP(nextRec, nextReq)
+
+	var second dto.ListResponse[dto.ProjectResponse]
+	require.NoError(t, json.Unmarshal(nextRec.Body.Bytes(), &second))
+
+	// Assert
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, first.Data, 1)
+	assert.Equal(t, CoolModSlug, first.Data[0].Slug)
+
+	require.Equal(t, http.StatusOK, nextRec.Code)
+	require.Len(t, second.Data, 1)
+	assert.Equal(t, ExampleModSlug, second.Data[0].Slug)
+	assert.Empty(t, second.Next)
+}
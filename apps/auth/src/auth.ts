@@ -1,9 +1,14 @@
 import { betterAuth } from "better-auth";
+import { APIError } from "better-auth/api";
 import { v7 as uuidv7 } from "uuid";
 import { jwt, openAPI, username } from "better-auth/plugins";
 import { env } from "./env.js";
 import { Pool } from "pg";
 
+export const pool = new Pool({
+  connectionString: env.DATABASE_URL,
+});
+
 export const auth = betterAuth({
   baseURL: env.BETTER_AUTH_URL,
   basePath: "/api/auth",
@@ -11,6 +16,49 @@ export const auth = betterAuth({
   emailAndPassword: {
     enabled: true,
   },
+  user: {
+    // Managed by the API's admin routes. Included in the JWT payload so the API
+    // can authorise requests without a lookup.
+    additionalFields: {
+      role: {
+        type: "string",
+        required: false,
+        defaultValue: "user",
+        input: false,
+      },
+      banned: {
+        type: "boolean",
+        required: false,
+        defaultValue: false,
+        input: false,
+      },
+      // Flagged by admins, exempts the account from the verified email requirement
+      testAccount: {
+        type: "boolean",
+        required: false,
+        defaultValue: false,
+        input: false,
+      },
+    },
+  },
+  databaseHooks: {
+    session: {
+      create: {
+        before: async (session) => {
+          const result = await pool.query<{ banned: boolean }>(
+            'SELECT "banned" FROM "user" WHERE "id" = $1',
+            [session.userId],
+          );
+
+          if (result.rows[0]?.banned) {
+            throw new APIError("FORBIDDEN", {
+              message: "This account has been banned.",
+            });
+          }
+        },
+      },
+    },
+  },
   socialProviders: {
     discord: {
       clientId: env.DISCORD_CLIENT_ID,
@@ -44,8 +92,21 @@ export const auth = betterAuth({
       generateId: () => uuidv7(),
     },
   },
-  plugins: [username(), jwt(), openAPI()],
-  database: new Pool({
-    connectionString: env.DATABASE_URL,
-  }),
+  plugins: [
+    username(),
+    jwt({
+      jwt: {
+        issuer: env.JWT_ISSUER ?? env.BETTER_AUTH_URL,
+        audience: env.JWT_AUDIENCE ?? env.BETTER_AUTH_URL,
+        // The API requires the session id and token type on every token
+        definePayload: ({ user, session }) => ({
+          ...user,
+          sid: session.id,
+          token_type: "access",
+        }),
+      },
+    }),
+    openAPI(),
+  ],
+  database: pool,
 });